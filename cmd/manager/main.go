@@ -1,15 +1,21 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/armory/spinnaker-operator/pkg/accounts"
+	"github.com/armory/spinnaker-operator/pkg/accounts/ecs"
 	"github.com/armory/spinnaker-operator/pkg/accounts/kubernetes"
 	"github.com/armory/spinnaker-operator/pkg/apis"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
 	"github.com/armory/spinnaker-operator/pkg/controller/accountvalidating"
 	"github.com/armory/spinnaker-operator/pkg/controller/spinnakeraccount"
+	"github.com/armory/spinnaker-operator/pkg/controller/spinnakeraccounttemplate"
 	"github.com/armory/spinnaker-operator/pkg/controller/spinnakerservice"
 	"github.com/armory/spinnaker-operator/pkg/controller/spinnakervalidating"
+	"github.com/armory/spinnaker-operator/pkg/migrate"
 	"github.com/armory/spinnaker-operator/pkg/operator"
 )
 
@@ -19,7 +25,16 @@ func main() {
 	accountvalidating.TypesFactory = interfaces.DefaultTypesFactory
 	spinnakerservice.TypesFactory = interfaces.DefaultTypesFactory
 	spinnakeraccount.TypesFactory = interfaces.DefaultTypesFactory
+	spinnakeraccounttemplate.TypesFactory = interfaces.DefaultTypesFactory
 	accounts.TypesFactory = interfaces.DefaultTypesFactory
 	kubernetes.TypesFactory = interfaces.DefaultTypesFactory
+	ecs.TypesFactory = interfaces.DefaultTypesFactory
+	if len(os.Args) > 1 && os.Args[1] == migrate.AccountsCommand {
+		if err := migrate.RunAccounts(os.Args[2:], os.Stdout, os.Stderr); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 	operator.Start(apis.AddToScheme)
 }
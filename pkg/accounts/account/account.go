@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -48,3 +49,89 @@ type Account interface {
 type AccountValidator interface {
 	Validate(interfaces.SpinnakerService, client.Client, context.Context, logr.Logger) error
 }
+
+// ValidatorFunc adapts a function to an AccountValidator
+type ValidatorFunc func(interfaces.SpinnakerService, client.Client, context.Context, logr.Logger) error
+
+func (f ValidatorFunc) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	return f(spinSvc, c, ctx, log)
+}
+
+// AccountWithSettingsValidator is implemented by accounts whose validator reaches external services (e.g. AWS STS).
+// Accounts defined in Spinnaker settings only fail validation on errors of the settings validator, errors of the
+// full validator are reported without rejecting the SpinnakerService.
+type AccountWithSettingsValidator interface {
+	// NewSettingsValidator returns a validator checking the account settings without network calls
+	NewSettingsValidator() AccountValidator
+}
+
+// AccountTypeWithEnabledKey is implemented by account types whose accounts in Spinnaker settings are only validated
+// when the provider is enabled (e.g. providers.aws.enabled)
+type AccountTypeWithEnabledKey interface {
+	GetEnabledKey() string
+}
+
+// Dependency references another account by type and name
+type Dependency struct {
+	Type interfaces.AccountType
+	Name string
+}
+
+// AccountWithDependencies is implemented by accounts referencing other accounts (e.g. ECS accounts referencing
+// an AWS account). Accounts whose dependencies can't be resolved are invalid.
+type AccountWithDependencies interface {
+	GetDependencies() []Dependency
+}
+
+// AccountTypeWithServiceSettings is implemented by account types that need more than their accounts in the settings
+// of some services (e.g. CI accounts enabling the CI integration in Igor and Echo).
+type AccountTypeWithServiceSettings interface {
+	// GetServiceSettings returns settings added to the service when at least one account of the type is deployed.
+	// Keys are property paths (e.g. jenkins.enabled).
+	GetServiceSettings(svc string) map[string]interface{}
+	// RendersAccounts returns false if the service only needs the settings and not the accounts themselves
+	RendersAccounts(svc string) bool
+}
+
+// SingletonAccountType is implemented by account types of which services only support a single configuration
+// (e.g. Slack notifications in Echo). Settings of these accounts are keyed by property path and set in the service's
+// settings instead of being added to a list of accounts. Only the first account of the type is used.
+type SingletonAccountType interface {
+	IsSingleton() bool
+}
+
+// AccountFinalizer is added to every SpinnakerAccount so the account is removed from Spinnaker's services before
+// it's deleted
+const AccountFinalizer = "spinnaker.io/account-cleanup"
+
+// ProvisionFinalizer is added to SpinnakerAccount with provisioned resources that can't be garbage collected
+// (e.g. resources in other namespaces)
+const ProvisionFinalizer = "spinnaker.io/provisioned-resources"
+
+// AccountTypeWithProvisioning is implemented by account types that can create the resources an account needs
+// (e.g. a Kubernetes service account and its RBAC). Resources are created before the account is validated.
+type AccountTypeWithProvisioning interface {
+	// NeedsProvisioning returns true if the account asks for resources to be provisioned
+	NeedsProvisioning(acc interfaces.SpinnakerAccount) bool
+	// ProvisionedNamespaces returns the namespaces the account is given access to, or an error if the account asks for
+	// access the operator isn't allowed to provision
+	ProvisionedNamespaces(acc interfaces.SpinnakerAccount) ([]string, error)
+	// Provision creates or updates the resources of the account. Rotated credentials are recorded in the account status.
+	Provision(ctx context.Context, c client.Client, acc interfaces.SpinnakerAccount) error
+	// Deprovision deletes the resources of the account
+	Deprovision(ctx context.Context, c client.Client, acc interfaces.SpinnakerAccount) error
+	// RotatedFiles returns the secret file references rendered in the account's settings whose content is updated
+	// when credentials are rotated
+	RotatedFiles(acc interfaces.SpinnakerAccount) []string
+}
+
+// FileReader returns the content of a file referenced in Spinnaker settings (e.g. a kubeconfigFile)
+type FileReader func(name string) ([]byte, error)
+
+// AccountTypeWithMigration is implemented by account types whose accounts defined in Spinnaker settings can be
+// converted to SpinnakerAccount objects.
+type AccountTypeWithMigration interface {
+	// Migrate returns the SpinnakerAccount equivalent to the account settings and the secrets it references.
+	// Returned objects have no namespace.
+	Migrate(ctx context.Context, settings map[string]interface{}, readFile FileReader) (interfaces.SpinnakerAccount, []*corev1.Secret, error)
+}
@@ -0,0 +1,211 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	yamlsecrets "github.com/armory/go-yaml-tools/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/util"
+)
+
+// ParseName returns the name of an account read from Spinnaker settings
+func ParseName(tp interfaces.AccountType, settings map[string]interface{}) (string, error) {
+	n, ok := settings["name"]
+	if !ok {
+		return "", fmt.Errorf("%s account missing name", tp)
+	}
+	name, ok := n.(string)
+	if !ok {
+		return "", fmt.Errorf("name is not a string")
+	}
+	return name, nil
+}
+
+type namespaceKey struct{}
+
+// WithNamespace returns a context in which secrets referenced by an account are read from ns, the namespace of its
+// SpinnakerAccount, instead of the namespace of the secret context (e.g. the SpinnakerService namespace, still used
+// for secrets of the SpinnakerService config). Cleanup must be called once done with the context.
+func WithNamespace(ctx context.Context, ns string) context.Context {
+	sc, ok := secrets.FromContext(ctx)
+	if !ok || sc.Namespace == ns {
+		return ctx
+	}
+	return context.WithValue(ctx, namespaceKey{}, secrets.NewContext(ctx, sc.RestConfig, ns))
+}
+
+type mountedFilesKey struct{}
+
+// WithMountedFiles returns a context in which accounts render secrets the operator rotates as file references instead
+// of their contents. The files are mounted in the services' pods and updated in place without restarting them.
+func WithMountedFiles(ctx context.Context) context.Context {
+	return context.WithValue(ctx, mountedFilesKey{}, true)
+}
+
+// MountedFiles returns true if secrets rotated by the operator are rendered as file references
+func MountedFiles(ctx context.Context) bool {
+	m, _ := ctx.Value(mountedFilesKey{}).(bool)
+	return m
+}
+
+// Cleanup deletes temporary files of secrets read in the namespace given to WithNamespace
+func Cleanup(ctx context.Context) {
+	if c, ok := ctx.Value(namespaceKey{}).(context.Context); ok {
+		secrets.Cleanup(c)
+	}
+}
+
+// secretContext returns the context secrets referenced by an account are read with
+func secretContext(ctx context.Context) context.Context {
+	if c, ok := ctx.Value(namespaceKey{}).(context.Context); ok {
+		return c
+	}
+	return ctx
+}
+
+// ReadSecret reads a value from a Kubernetes secret in the namespace of the account
+func ReadSecret(ctx context.Context, ref *interfaces.SecretInNamespaceReference) (string, error) {
+	sc, err := secrets.FromContextWithError(secretContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	return util.GetSecretContent(sc.RestConfig, sc.Namespace, ref.Name, ref.Key)
+}
+
+// DecodeSecret decodes a secret reference of the account's settings, Kubernetes secrets are read in the namespace
+// of the account
+func DecodeSecret(ctx context.Context, val string) (string, error) {
+	v, _, err := secrets.Decode(secretContext(ctx), val)
+	return v, err
+}
+
+// DecodeSecretAsFile decodes a secret reference of the account's settings to a file, Kubernetes secrets are read in
+// the namespace of the account
+func DecodeSecretAsFile(ctx context.Context, val string) (string, error) {
+	return secrets.DecodeAsFile(secretContext(ctx), val)
+}
+
+// ResolveReferences resolves Kubernetes secret and config map references in settings rendered with a context returned
+// by WithNamespace, which are otherwise read from the namespace of the services. References are checked with the
+// account's context and made explicit to the namespace they're read from, which the secret context is then allowed
+// to read. Values are never inlined: references are mapped to the services' secrets when Spinnaker is deployed.
+func ResolveReferences(ctx context.Context, settings map[string]interface{}) (map[string]interface{}, error) {
+	actx, ok := ctx.Value(namespaceKey{}).(context.Context)
+	if !ok {
+		return settings, nil
+	}
+	sc, err := secrets.FromContextWithError(ctx)
+	if err != nil {
+		return nil, err
+	}
+	asc, err := secrets.FromContextWithError(actx)
+	if err != nil {
+		return nil, err
+	}
+	h := func(val string) (string, error) {
+		e, isFile, p := yamlsecrets.GetEngine(val)
+		if e != secrets.KubernetesSecretEngine && e != secrets.KubernetesConfigMapEngine {
+			return val, nil
+		}
+		// The reference is checked with the account's context
+		prefix := "encrypted"
+		if isFile {
+			prefix = "encryptedFile"
+			if _, err := secrets.DecodeAsFile(actx, val); err != nil {
+				return "", err
+			}
+		} else if _, _, err := secrets.Decode(actx, val); err != nil {
+			return "", err
+		}
+		ns, name, key, err := secrets.ParseKubernetesReferenceParams(p)
+		if err != nil {
+			return "", err
+		}
+		if ns == "" {
+			ns = asc.Namespace
+		}
+		sc.AllowNamespace(ns)
+		return fmt.Sprintf("%s:%s!ns:%s!n:%s!k:%s", prefix, e, ns, name, key), nil
+	}
+	res, err := inspect.InspectStrings(settings, h)
+	if err != nil {
+		return nil, err
+	}
+	return res.(map[string]interface{}), nil
+}
+
+// GetSecretValue returns the value referenced by a Kubernetes secret if set, or the value of the given
+// setting, decoded if it's a secret reference.
+func GetSecretValue(ctx context.Context, ref *interfaces.SecretInNamespaceReference, settings interfaces.FreeForm, key string) (string, error) {
+	if ref != nil {
+		return ReadSecret(ctx, ref)
+	}
+	s, ok := settings[key].(string)
+	if !ok || s == "" {
+		return "", nil
+	}
+	v, err := DecodeSecret(ctx, s)
+	if err != nil {
+		return "", fmt.Errorf("Error decoding %s:\n  %w", key, err)
+	}
+	return v, nil
+}
+
+// ReadJsonKey returns the service account key of a Google account, referenced either as a Kubernetes secret or via
+// jsonPath in settings. It returns nil if no key is configured.
+func ReadJsonKey(ctx context.Context, ref *interfaces.SecretInNamespaceReference, settings interfaces.FreeForm, spinSvc interfaces.SpinnakerService) ([]byte, error) {
+	if ref != nil {
+		c, err := ReadSecret(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(c), nil
+	}
+	p, ok := settings["jsonPath"].(string)
+	if !ok || p == "" {
+		return nil, nil
+	}
+	if yamlsecrets.IsEncryptedSecret(p) {
+		f, err := DecodeSecretAsFile(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.ReadFile(f)
+	}
+	if filepath.IsAbs(p) {
+		// if file path is absolute, it may already be a path decoded by secret engines
+		return ioutil.ReadFile(p)
+	}
+	if spinSvc == nil {
+		return nil, nil
+	}
+	// we're taking relative file paths as files defined inside spec.spinnakerConfig.files
+	c := spinSvc.GetSpinnakerConfig().GetFileContent(p)
+	if len(c) == 0 {
+		return nil, fmt.Errorf("file \"%s\" not found in spec.spinnakerConfig.files", p)
+	}
+	return c, nil
+}
+
+// SecretReference returns the reference to a value of a Kubernetes secret of the account. Settings of accounts in
+// another namespace are made explicit to their namespace by ResolveReferences.
+func SecretReference(ref *interfaces.SecretInNamespaceReference) string {
+	return fmt.Sprintf("encrypted:%s!n:%s!k:%s", secrets.KubernetesSecretEngine, ref.Name, ref.Key)
+}
+
+// SecretFileReference returns the reference to a file holding a value of a Kubernetes secret of the account
+func SecretFileReference(ref *interfaces.SecretInNamespaceReference) string {
+	return fmt.Sprintf("encryptedFile:%s!n:%s!k:%s", secrets.KubernetesSecretEngine, ref.Name, ref.Key)
+}
+
+// SetSecretReference sets a reference to the value of a Kubernetes secret in settings if the reference is set
+func SetSecretReference(m map[string]interface{}, ref *interfaces.SecretInNamespaceReference, key string) {
+	if ref != nil {
+		m[key] = SecretReference(ref)
+	}
+}
@@ -2,10 +2,23 @@ package accounts
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/accounts/artifacts"
+	"github.com/armory/spinnaker-operator/pkg/accounts/aws"
+	"github.com/armory/spinnaker-operator/pkg/accounts/azure"
+	"github.com/armory/spinnaker-operator/pkg/accounts/ci"
+	"github.com/armory/spinnaker-operator/pkg/accounts/cloudfoundry"
+	"github.com/armory/spinnaker-operator/pkg/accounts/docker"
+	"github.com/armory/spinnaker-operator/pkg/accounts/ecs"
+	"github.com/armory/spinnaker-operator/pkg/accounts/google"
 	"github.com/armory/spinnaker-operator/pkg/accounts/kubernetes"
+	"github.com/armory/spinnaker-operator/pkg/accounts/notifications"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
 )
@@ -15,8 +28,6 @@ const (
 	AccountSecretPath = "/var/operator-accounts"
 )
 
-var ServicesWithAccountsFiles = []string{"clouddriver"}
-
 var TypesFactory interfaces.TypesFactory
 var Types = map[interfaces.AccountType]account.SpinnakerAccountType{}
 
@@ -27,7 +38,11 @@ func Register(accountTypes ...account.SpinnakerAccountType) {
 }
 
 func init() {
-	Register(&kubernetes.AccountType{})
+	Register(&kubernetes.AccountType{}, &aws.AccountType{}, &docker.AccountType{}, &google.AccountType{}, &azure.AccountType{}, &cloudfoundry.AccountType{}, &ecs.AccountType{},
+		&artifacts.GitHubAccountType{}, &artifacts.GitLabAccountType{}, &artifacts.S3AccountType{}, &artifacts.GCSAccountType{},
+		&artifacts.HelmAccountType{}, &artifacts.HTTPAccountType{},
+		&ci.JenkinsAccountType{}, &ci.GitHubActionsAccountType{}, &ci.GitLabCIAccountType{},
+		&notifications.SlackAccountType{}, &notifications.SMTPAccountType{}, &notifications.MicrosoftTeamsAccountType{})
 }
 
 func GetType(tp interfaces.AccountType) (account.SpinnakerAccountType, error) {
@@ -41,15 +56,88 @@ func GetType(tp interfaces.AccountType) (account.SpinnakerAccountType, error) {
 	return nil, fmt.Errorf("account type %s not recognized, valid types are %s", tp, strings.Join(tps, ", "))
 }
 
-func AllValidCRDAccounts(ctx context.Context, c client.Client, ns string) ([]account.Account, error) {
-	spinAccounts := TypesFactory.NewAccountList()
-	if err := c.List(ctx, spinAccounts, client.InNamespace(ns)); err != nil {
+// AllValidCRDAccounts returns all enabled SpinnakerAccount selected by the SpinnakerService (see spec.accounts) with
+// their dependencies, and records them in the SpinnakerService status, written by the SpinnakerService controller.
+// If spec.accounts.excludeInvalid is true, accounts whose last validation of their current spec failed are left out.
+func AllValidCRDAccounts(ctx context.Context, c client.Client, spinsvc interfaces.SpinnakerService) ([]account.Account, error) {
+	accounts, err := selectCRDAccounts(ctx, c, spinsvc)
+	if err != nil {
+		return nil, err
+	}
+
+	valid := make([]account.Account, 0)
+	included := make([]interfaces.IncludedAccount, 0)
+	for _, a := range includedAccounts(accounts) {
+		valid = append(valid, a.namespaced())
+		included = append(included, interfaces.IncludedAccount{Name: a.crd.GetName(), Namespace: a.crd.GetNamespace(), Type: a.crd.GetSpec().Type})
+	}
+	st := spinsvc.GetStatus()
+	st.Accounts = included
+	st.AccountCount = len(included)
+	return valid, nil
+}
+
+// IncludedCRDAccounts returns the accounts AllValidCRDAccounts returns without recording them in the status of the
+// SpinnakerService
+func IncludedCRDAccounts(ctx context.Context, c client.Client, spinsvc interfaces.SpinnakerService) ([]account.Account, error) {
+	accounts, err := selectCRDAccounts(ctx, c, spinsvc)
+	if err != nil {
+		return nil, err
+	}
+	valid := make([]account.Account, 0)
+	for _, a := range includedAccounts(accounts) {
+		valid = append(valid, a.namespaced())
+	}
+	return valid, nil
+}
+
+// CRDAccountsHash returns a hash of the accounts AllValidCRDAccounts returns for the SpinnakerService. The hash changes
+// when an account is added, removed or changed. Rotated credentials are mounted files updated without redeploying
+// Spinnaker (see account.WithMountedFiles).
+func CRDAccountsHash(ctx context.Context, c client.Client, spinsvc interfaces.SpinnakerService) (string, error) {
+	accounts, err := selectCRDAccounts(ctx, c, spinsvc)
+	if err != nil {
+		return "", err
+	}
+	accounts = includedAccounts(accounts)
+	type accountHash struct {
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+		Spec      string `json:"spec"`
+	}
+	hashes := make([]accountHash, 0, len(accounts))
+	for _, a := range accounts {
+		h, err := SpecHash(a.crd)
+		if err != nil {
+			return "", err
+		}
+		hashes = append(hashes, accountHash{Namespace: a.crd.GetNamespace(), Name: a.crd.GetName(), Spec: h})
+	}
+	data, err := json.Marshal(hashes)
+	if err != nil {
+		return "", err
+	}
+	m := md5.Sum(data)
+	return hex.EncodeToString(m[:]), nil
+}
+
+// selectCRDAccounts returns the accounts selected by the SpinnakerService that are enabled and valid, in the order
+// they're listed. Accounts left out of Spinnaker's services because of other accounts have an excludedReason, accounts
+// that can't be parsed an invalidReason.
+func selectCRDAccounts(ctx context.Context, c client.Client, spinsvc interfaces.SpinnakerService) ([]crdAccount, error) {
+	spinAccounts, err := listCRDAccounts(ctx, c, spinsvc)
+	if err != nil {
 		return nil, err
 	}
 
-	accounts := make([]account.Account, 0)
-	for _, a := range spinAccounts.GetItems() {
-		if !a.GetSpec().Enabled {
+	excludeInvalid := spinsvc.GetAccountConfig().ExcludeInvalid
+	accounts := make([]crdAccount, 0)
+	for _, a := range spinAccounts {
+		// Accounts being deleted are removed from Spinnaker before their finalizer is released
+		if !a.GetSpec().Enabled || a.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if excludeInvalid && isInvalid(a) {
 			continue
 		}
 		accountType, err := GetType(a.GetSpec().Type)
@@ -58,13 +146,210 @@ func AllValidCRDAccounts(ctx context.Context, c client.Client, ns string) ([]acc
 		}
 		acc, err := accountType.FromCRD(a)
 		if err != nil {
-			return nil, err
+			// Other accounts are still selected
+			accounts = append(accounts, crdAccount{crd: a.DeepCopyInterface(), invalidReason: err.Error()})
+			continue
+		}
+		ca := crdAccount{account: acc, crd: a.DeepCopyInterface()}
+		if err := CheckProvisioning(ctx, c, spinsvc, a); err != nil {
+			ca.excludedReason = err.Error()
 		}
-		accounts = append(accounts, acc)
+		accounts = append(accounts, ca)
 	}
+	excludeDuplicates(accounts)
+	excludeExtraSingletons(accounts)
+	excludeUnresolved(ctx, spinsvc, accounts)
 	return accounts, nil
 }
 
+// isInvalid returns true if the current spec of the account failed its last validation.
+// Status recorded for a prior version of the spec is ignored until the account is validated again.
+func isInvalid(a interfaces.SpinnakerAccount) bool {
+	st := a.GetStatus()
+	if st.InvalidReason == "" || st.Hash == "" {
+		return false
+	}
+	h, err := SpecHash(a)
+	return err == nil && h == st.Hash
+}
+
+// SpecHash returns a hash of the account's spec, recorded in status when the account is validated
+func SpecHash(a interfaces.SpinnakerAccount) (string, error) {
+	data, err := json.Marshal(a.GetSpec())
+	if err != nil {
+		return "", err
+	}
+	m := md5.Sum(data)
+	return hex.EncodeToString(m[:]), nil
+}
+
+type crdAccount struct {
+	account account.Account
+	crd     interfaces.SpinnakerAccount
+	// excludedReason is set if the account is left out of Spinnaker's services
+	excludedReason string
+	// invalidReason is set if the account can't be parsed, account is then nil
+	invalidReason string
+}
+
+// excluded returns true if the account is left out of Spinnaker's services
+func (a crdAccount) excluded() bool {
+	return a.excludedReason != "" || a.invalidReason != ""
+}
+
+// namespacedAccount is a SpinnakerAccount whose secret references are read in its namespace when rendered, instead of
+// the namespace of the SpinnakerService
+type namespacedAccount struct {
+	account.Account
+	namespace string
+}
+
+func (n *namespacedAccount) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	ctx = account.WithNamespace(ctx, n.namespace)
+	defer account.Cleanup(ctx)
+	m, err := n.Account.ToSpinnakerSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return account.ResolveReferences(ctx, m)
+}
+
+// namespaced returns the account with secret references read in the namespace of the SpinnakerAccount
+func (a crdAccount) namespaced() account.Account {
+	return &namespacedAccount{Account: a.account, namespace: a.crd.GetNamespace()}
+}
+
+// includedAccounts returns the accounts that are not excluded
+func includedAccounts(accounts []crdAccount) []crdAccount {
+	included := make([]crdAccount, 0, len(accounts))
+	for _, a := range accounts {
+		if !a.excluded() {
+			included = append(included, a)
+		}
+	}
+	return included
+}
+
+// excludeDuplicates excludes accounts with the same type and name as an account listed before them, e.g. accounts
+// of another namespace with the same name as an account of the SpinnakerService namespace.
+func excludeDuplicates(accounts []crdAccount) {
+	seen := make(map[string]interfaces.SpinnakerAccount)
+	for i, a := range accounts {
+		if a.excluded() {
+			continue
+		}
+		k := fmt.Sprintf("%s/%s", a.account.GetType(), a.account.GetName())
+		if prior, ok := seen[k]; ok {
+			accounts[i].excludedReason = fmt.Sprintf("%s account \"%s\" is already defined in namespace %s", a.account.GetType(), a.account.GetName(), prior.GetNamespace())
+			continue
+		}
+		seen[k] = a.crd
+	}
+}
+
+// excludeExtraSingletons excludes accounts of singleton types (e.g. Slack) listed after the first account of their
+// type, services only supporting a single configuration.
+func excludeExtraSingletons(accounts []crdAccount) {
+	seen := make(map[interfaces.AccountType]interfaces.SpinnakerAccount)
+	for i, a := range accounts {
+		if a.excluded() {
+			continue
+		}
+		t, err := GetType(a.account.GetType())
+		if err != nil {
+			continue
+		}
+		if st, ok := t.(account.SingletonAccountType); !ok || !st.IsSingleton() {
+			continue
+		}
+		if prior, ok := seen[t.GetType()]; ok {
+			accounts[i].excludedReason = fmt.Sprintf("only one %s account is supported, account \"%s\" of namespace %s is used", t.GetType(), prior.GetName(), prior.GetNamespace())
+			continue
+		}
+		seen[t.GetType()] = a.crd
+	}
+}
+
+// excludeUnresolved excludes accounts referencing accounts that are not enabled or don't exist, either as
+// SpinnakerAccount or in the SpinnakerService config.
+func excludeUnresolved(ctx context.Context, spinsvc interfaces.SpinnakerService, accounts []crdAccount) {
+	known := make(map[account.Dependency]bool)
+	for _, a := range includedAccounts(accounts) {
+		known[account.Dependency{Type: a.account.GetType(), Name: a.account.GetName()}] = true
+	}
+	for i, a := range accounts {
+		if a.excluded() {
+			continue
+		}
+		if err := checkDependencies(ctx, spinsvc, a.account, known); err != nil {
+			accounts[i].excludedReason = err.Error()
+		}
+	}
+}
+
+// RecordExclusions records why the SpinnakerAccount objects selected by the SpinnakerService are left out of its
+// services in their status. It's called by the SpinnakerAccount controller, which owns their status.
+func RecordExclusions(ctx context.Context, c client.Client, spinsvc interfaces.SpinnakerService) error {
+	accounts, err := selectCRDAccounts(ctx, c, spinsvc)
+	if err != nil {
+		return err
+	}
+	return recordExclusions(ctx, c, accounts)
+}
+
+// recordExclusions records why accounts are left out of Spinnaker's services in their status, and clears it once
+// they're included again. Unlike status.invalidReason, it doesn't change when accounts are validated. Accounts that
+// can't be parsed have status.invalidReason set.
+func recordExclusions(ctx context.Context, c client.Client, accounts []crdAccount) error {
+	for _, a := range accounts {
+		st := a.crd.GetStatus()
+		// Accounts that can't be parsed are invalid until they're validated again
+		if st.ExcludedReason == a.excludedReason && (a.invalidReason == "" || st.InvalidReason == a.invalidReason) {
+			continue
+		}
+		st.ExcludedReason = a.excludedReason
+		if a.invalidReason != "" {
+			st.InvalidReason = a.invalidReason
+		}
+		// Status is updated again on the next reconcile if the account changed in the meantime
+		if err := c.Status().Update(ctx, a.crd); err != nil && !errors.IsConflict(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkDependencies(ctx context.Context, spinsvc interfaces.SpinnakerService, a account.Account, known map[account.Dependency]bool) error {
+	d, ok := a.(account.AccountWithDependencies)
+	if !ok {
+		return nil
+	}
+	for _, dep := range d.GetDependencies() {
+		if !known[dep] && !hasConfigAccount(ctx, spinsvc, dep) {
+			return fmt.Errorf("%s account \"%s\" referenced by account \"%s\" is missing or not enabled", dep.Type, dep.Name, a.GetName())
+		}
+	}
+	return nil
+}
+
+// hasConfigAccount checks if the account is defined in the SpinnakerService config (e.g. providers.aws.accounts)
+func hasConfigAccount(ctx context.Context, spinsvc interfaces.SpinnakerService, dep account.Dependency) bool {
+	t, err := GetType(dep.Type)
+	if err != nil {
+		return false
+	}
+	arr, err := spinsvc.GetSpinnakerConfig().GetHalConfigObjectArray(ctx, t.GetConfigAccountsKey())
+	if err != nil {
+		return false
+	}
+	for _, a := range arr {
+		if a["name"] == dep.Name {
+			return true
+		}
+	}
+	return false
+}
+
 // FromSpinnakerConfigSlice builds accounts from a given slice of settings
 func FromSpinnakerConfigSlice(ctx context.Context, accountType account.SpinnakerAccountType, settingsSlice []map[string]interface{}, ignoreInvalid bool) ([]account.Account, error) {
 	ar := make([]account.Account, 0)
@@ -0,0 +1,432 @@
+package accounts
+
+import (
+	"context"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+func newAccount(name string, tp interfaces.AccountType, enabled bool) *v1alpha2.SpinnakerAccount {
+	a := &v1alpha2.SpinnakerAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1"},
+		Spec:       interfaces.SpinnakerAccountSpec{Type: tp, Enabled: enabled},
+	}
+	switch tp {
+	case interfaces.AWSAccountType:
+		a.Spec.AWS = &interfaces.AWSAccount{AccountId: "123456789012"}
+	case interfaces.ECSAccountType:
+		a.Spec.ECS = &interfaces.ECSAccount{AwsAccount: "aws1"}
+	case interfaces.SlackAccountType:
+		a.Spec.Slack = &interfaces.SlackAccount{BotName: "spinnakerbot"}
+	}
+	return a
+}
+
+func deleting(a *v1alpha2.SpinnakerAccount) *v1alpha2.SpinnakerAccount {
+	now := metav1.Now()
+	a.DeletionTimestamp = &now
+	a.Finalizers = []string{"spinnaker.io/account-cleanup"}
+	return a
+}
+
+func newSpinnakerService(accounts interfaces.AccountConfig) *v1alpha2.SpinnakerService {
+	return &v1alpha2.SpinnakerService{
+		ObjectMeta: metav1.ObjectMeta{Name: "spinnaker", Namespace: "ns1"},
+		Spec:       interfaces.SpinnakerServiceSpec{Accounts: accounts},
+	}
+}
+
+func fakeClient(t *testing.T, objs ...client.Object) client.Client {
+	s := runtime.NewScheme()
+	if err := v1alpha2.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func TestAllValidCRDAccounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		objs     []client.Object
+		config   interfaces.FreeForm
+		expected []string
+		invalid  []string
+	}{
+		{
+			name:     "ecs account with its aws account",
+			objs:     []client.Object{newAccount("aws1", interfaces.AWSAccountType, true), newAccount("ecs1", interfaces.ECSAccountType, true)},
+			expected: []string{"aws1", "ecs1"},
+		},
+		{
+			name:     "ecs account with missing aws account",
+			objs:     []client.Object{newAccount("ecs1", interfaces.ECSAccountType, true)},
+			expected: []string{},
+			invalid:  []string{"ecs1"},
+		},
+		{
+			name:     "ecs account with disabled aws account",
+			objs:     []client.Object{newAccount("aws1", interfaces.AWSAccountType, false), newAccount("ecs1", interfaces.ECSAccountType, true)},
+			expected: []string{},
+			invalid:  []string{"ecs1"},
+		},
+		{
+			name: "ecs account with aws account in config",
+			objs: []client.Object{newAccount("ecs1", interfaces.ECSAccountType, true)},
+			config: interfaces.FreeForm{
+				"providers": map[string]interface{}{
+					"aws": map[string]interface{}{
+						"accounts": []interface{}{map[string]interface{}{"name": "aws1"}},
+					},
+				},
+			},
+			expected: []string{"ecs1"},
+		},
+		{
+			name:     "account being deleted",
+			objs:     []client.Object{deleting(newAccount("aws1", interfaces.AWSAccountType, true)), newAccount("aws2", interfaces.AWSAccountType, true)},
+			expected: []string{"aws2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fakeClient(t, tt.objs...)
+			spinsvc := newSpinnakerService(interfaces.AccountConfig{Enabled: true})
+			spinsvc.Spec.SpinnakerConfig.Config = tt.config
+			accs, err := AllValidCRDAccounts(context.TODO(), c, spinsvc)
+			if !assert.Nil(t, err) {
+				return
+			}
+			names := make([]string, 0)
+			for _, a := range accs {
+				names = append(names, a.GetName())
+			}
+			assert.ElementsMatch(t, tt.expected, names)
+			assert.Equal(t, len(tt.expected), spinsvc.Status.AccountCount)
+
+			assert.Nil(t, RecordExclusions(context.TODO(), c, spinsvc))
+			for _, n := range tt.invalid {
+				a := &v1alpha2.SpinnakerAccount{}
+				if assert.Nil(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: n}, a)) {
+					assert.Contains(t, a.Status.ExcludedReason, "AWS account \"aws1\"")
+				}
+			}
+		})
+	}
+}
+
+func TestCRDAccountsHash(t *testing.T) {
+	spinsvc := newSpinnakerService(interfaces.AccountConfig{Enabled: true})
+	hash := func(objs ...client.Object) string {
+		h, err := CRDAccountsHash(context.TODO(), fakeClient(t, objs...), spinsvc)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		return h
+	}
+	changed := newAccount("aws1", interfaces.AWSAccountType, true)
+	changed.Spec.AWS.AccountId = "210987654321"
+	rotated := newAccount("aws1", interfaces.AWSAccountType, true)
+	rotated.Status.CredentialsRefreshAt = &metav1.Timestamp{Seconds: 1600000000}
+
+	h := hash(newAccount("aws1", interfaces.AWSAccountType, true))
+	assert.Equal(t, h, hash(newAccount("aws1", interfaces.AWSAccountType, true), newAccount("aws2", interfaces.AWSAccountType, false)))
+	assert.NotEqual(t, h, hash(newAccount("aws1", interfaces.AWSAccountType, true), newAccount("aws2", interfaces.AWSAccountType, true)))
+	assert.NotEqual(t, h, hash(changed))
+	// rotated credentials are updated without redeploying
+	assert.Equal(t, h, hash(rotated))
+	assert.NotEqual(t, h, hash())
+	// Listing accounts doesn't record them in the status
+	assert.Empty(t, spinsvc.Status.Accounts)
+}
+
+func TestAllValidCRDAccountsExcludeInvalid(t *testing.T) {
+	invalid := func(name string, sameSpec bool) *v1alpha2.SpinnakerAccount {
+		a := newAccount(name, interfaces.AWSAccountType, true)
+		h, err := SpecHash(a)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		if !sameSpec {
+			a.Spec.AWS.AccountId = "210987654321"
+		}
+		a.Status = interfaces.SpinnakerAccountStatus{InvalidReason: "some error", Hash: h}
+		return a
+	}
+	tests := []struct {
+		name           string
+		objs           []client.Object
+		excludeInvalid bool
+		expected       []string
+	}{
+		{
+			name:     "invalid accounts are kept by default",
+			objs:     []client.Object{newAccount("aws1", interfaces.AWSAccountType, true), invalid("aws2", true)},
+			expected: []string{"aws1", "aws2"},
+		},
+		{
+			name:           "invalid accounts are excluded",
+			objs:           []client.Object{newAccount("aws1", interfaces.AWSAccountType, true), invalid("aws2", true)},
+			excludeInvalid: true,
+			expected:       []string{"aws1"},
+		},
+		{
+			name:           "accounts changed since their last validation are kept",
+			objs:           []client.Object{invalid("aws2", false)},
+			excludeInvalid: true,
+			expected:       []string{"aws2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fakeClient(t, tt.objs...)
+			accs, err := AllValidCRDAccounts(context.TODO(), c, newSpinnakerService(interfaces.AccountConfig{Enabled: true, ExcludeInvalid: tt.excludeInvalid}))
+			if !assert.Nil(t, err) {
+				return
+			}
+			names := make([]string, 0)
+			for _, a := range accs {
+				names = append(names, a.GetName())
+			}
+			assert.ElementsMatch(t, tt.expected, names)
+		})
+	}
+}
+
+func TestAllValidCRDAccountsSelectors(t *testing.T) {
+	inNamespace := func(name, ns string, l map[string]string) *v1alpha2.SpinnakerAccount {
+		a := newAccount(name, interfaces.AWSAccountType, true)
+		a.Namespace = ns
+		a.Labels = l
+		return a
+	}
+	namespace := func(name string, l map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: l}}
+	}
+	team := map[string]string{"team": "a"}
+	objs := []client.Object{
+		namespace("ns1", nil), namespace("ns2", team), namespace("ns3", nil),
+		inNamespace("aws1", "ns1", nil),
+		inNamespace("aws2", "ns2", team),
+		inNamespace("aws3", "ns2", nil),
+		inNamespace("aws4", "ns3", team),
+	}
+	tests := []struct {
+		name     string
+		config   interfaces.AccountConfig
+		expected []string
+	}{
+		{
+			name:     "only the SpinnakerService namespace by default",
+			expected: []string{"ns1/aws1"},
+		},
+		{
+			name:     "namespaces matching the namespace selector",
+			config:   interfaces.AccountConfig{NamespaceSelector: &metav1.LabelSelector{MatchLabels: team}},
+			expected: []string{"ns1/aws1", "ns2/aws2", "ns2/aws3"},
+		},
+		{
+			name:     "all namespaces with an empty namespace selector",
+			config:   interfaces.AccountConfig{NamespaceSelector: &metav1.LabelSelector{}},
+			expected: []string{"ns1/aws1", "ns2/aws2", "ns2/aws3", "ns3/aws4"},
+		},
+		{
+			name: "accounts matching the selector",
+			config: interfaces.AccountConfig{
+				NamespaceSelector: &metav1.LabelSelector{},
+				Selector:          &metav1.LabelSelector{MatchLabels: team},
+			},
+			expected: []string{"ns2/aws2", "ns3/aws4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fakeClient(t, objs...)
+			spinsvc := newSpinnakerService(tt.config)
+			_, err := AllValidCRDAccounts(context.TODO(), c, spinsvc)
+			if !assert.Nil(t, err) {
+				return
+			}
+			names := make([]string, 0)
+			for _, a := range spinsvc.Status.Accounts {
+				names = append(names, a.Namespace+"/"+a.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestAllValidCRDAccountsDuplicates(t *testing.T) {
+	other := newAccount("aws1", interfaces.AWSAccountType, true)
+	other.Namespace = "ns2"
+	c := fakeClient(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}}, other, newAccount("aws1", interfaces.AWSAccountType, true))
+	spinsvc := newSpinnakerService(interfaces.AccountConfig{NamespaceSelector: &metav1.LabelSelector{}})
+	accs, err := AllValidCRDAccounts(context.TODO(), c, spinsvc)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 1, len(accs))
+	assert.Equal(t, []interfaces.IncludedAccount{{Name: "aws1", Namespace: "ns1", Type: interfaces.AWSAccountType}}, spinsvc.Status.Accounts)
+
+	assert.Nil(t, RecordExclusions(context.TODO(), c, spinsvc))
+	a := &v1alpha2.SpinnakerAccount{}
+	if assert.Nil(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "ns2", Name: "aws1"}, a)) {
+		assert.Equal(t, "AWS account \"aws1\" is already defined in namespace ns1", a.Status.ExcludedReason)
+	}
+}
+
+func TestAllValidCRDAccountsSingletons(t *testing.T) {
+	other := newAccount("slack0", interfaces.SlackAccountType, true)
+	other.Namespace = "ns2"
+	c := fakeClient(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}}, other,
+		newAccount("slack2", interfaces.SlackAccountType, true), newAccount("slack1", interfaces.SlackAccountType, true))
+	spinsvc := newSpinnakerService(interfaces.AccountConfig{NamespaceSelector: &metav1.LabelSelector{}})
+	accs, err := AllValidCRDAccounts(context.TODO(), c, spinsvc)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 1, len(accs))
+	assert.Equal(t, []interfaces.IncludedAccount{{Name: "slack1", Namespace: "ns1", Type: interfaces.SlackAccountType}}, spinsvc.Status.Accounts)
+
+	assert.Nil(t, RecordExclusions(context.TODO(), c, spinsvc))
+	for _, k := range []types.NamespacedName{{Namespace: "ns1", Name: "slack2"}, {Namespace: "ns2", Name: "slack0"}} {
+		a := &v1alpha2.SpinnakerAccount{}
+		if assert.Nil(t, c.Get(context.TODO(), k, a)) {
+			assert.Equal(t, "only one Slack account is supported, account \"slack1\" of namespace ns1 is used", a.Status.ExcludedReason)
+		}
+	}
+}
+
+func TestAllValidCRDAccountsUnparsable(t *testing.T) {
+	invalid := newAccount("slack1", interfaces.SlackAccountType, true)
+	invalid.Spec.Slack = nil
+	c := fakeClient(t, invalid, newAccount("aws1", interfaces.AWSAccountType, true))
+	spinsvc := newSpinnakerService(interfaces.AccountConfig{Enabled: true})
+	accs, err := AllValidCRDAccounts(context.TODO(), c, spinsvc)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, []interfaces.IncludedAccount{{Name: "aws1", Namespace: "ns1", Type: interfaces.AWSAccountType}}, spinsvc.Status.Accounts)
+	assert.Equal(t, 1, len(accs))
+
+	assert.Nil(t, RecordExclusions(context.TODO(), c, spinsvc))
+	a := &v1alpha2.SpinnakerAccount{}
+	if assert.Nil(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "slack1"}, a)) {
+		assert.Equal(t, "slack needs to be defined", a.Status.InvalidReason)
+		assert.Equal(t, "", a.Status.ExcludedReason)
+	}
+}
+
+func TestExcludedReasonCleared(t *testing.T) {
+	c := fakeClient(t, newAccount("ecs1", interfaces.ECSAccountType, true))
+	spinsvc := newSpinnakerService(interfaces.AccountConfig{Enabled: true})
+	get := func() *v1alpha2.SpinnakerAccount {
+		a := &v1alpha2.SpinnakerAccount{}
+		assert.Nil(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "ns1", Name: "ecs1"}, a))
+		return a
+	}
+
+	// neither the hash nor the accounts deployed record exclusions
+	_, err := CRDAccountsHash(context.TODO(), c, spinsvc)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = AllValidCRDAccounts(context.TODO(), c, spinsvc)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "", get().Status.ExcludedReason)
+
+	assert.Nil(t, RecordExclusions(context.TODO(), c, spinsvc))
+	a := get()
+	assert.NotEqual(t, "", a.Status.ExcludedReason)
+	assert.Equal(t, "", a.Status.InvalidReason)
+
+	assert.Nil(t, c.Create(context.TODO(), newAccount("aws1", interfaces.AWSAccountType, true)))
+	assert.Nil(t, RecordExclusions(context.TODO(), c, spinsvc))
+	accs, err := AllValidCRDAccounts(context.TODO(), c, spinsvc)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 2, len(accs))
+	assert.Equal(t, "", get().Status.ExcludedReason)
+}
+
+func TestNamespacedAccountSecretReferences(t *testing.T) {
+	acc := newAccount("aws1", interfaces.AWSAccountType, true)
+	acc.Namespace = "team-a"
+	acc.Spec.Settings = interfaces.FreeForm{"environment": "dev", "secretKey": "encrypted:k8s!n:creds!k:key!ns:spinnaker"}
+	c := fakeClient(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}, acc)
+	spinsvc := newSpinnakerService(interfaces.AccountConfig{NamespaceSelector: &metav1.LabelSelector{}})
+	spinsvc.Namespace = "spinnaker"
+	accs, err := AllValidCRDAccounts(context.TODO(), c, spinsvc)
+	if !assert.Nil(t, err) || !assert.Equal(t, 1, len(accs)) {
+		return
+	}
+
+	// references of the account are read from its namespace, which can't read the SpinnakerService namespace
+	ctx := secrets.NewContext(context.TODO(), nil, "spinnaker")
+	defer secrets.Cleanup(ctx)
+	_, err = accs[0].ToSpinnakerSettings(ctx)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Namespace spinnaker is not allowed")
+	}
+}
+
+func TestFindSpinnakerServices(t *testing.T) {
+	acc := newAccount("aws1", interfaces.AWSAccountType, true)
+	acc.Namespace = "ns2"
+	selecting := newSpinnakerService(interfaces.AccountConfig{NamespaceSelector: &metav1.LabelSelector{}})
+	including := newSpinnakerService(interfaces.AccountConfig{})
+	including.Namespace = "ns3"
+	including.Status.Accounts = []interfaces.IncludedAccount{{Name: "aws1", Namespace: "ns2", Type: interfaces.AWSAccountType}}
+	other := newSpinnakerService(interfaces.AccountConfig{})
+	other.Namespace = "ns4"
+	c := fakeClient(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}}, selecting, including, other)
+
+	svcs, err := FindSpinnakerServices(context.TODO(), c, acc)
+	if !assert.Nil(t, err) {
+		return
+	}
+	nss := make([]string, 0)
+	for _, s := range svcs {
+		nss = append(nss, s.GetNamespace())
+	}
+	assert.ElementsMatch(t, []string{"ns1", "ns3"}, nss)
+}
+
+func TestCheckProvisioning(t *testing.T) {
+	selected := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"spinnaker": "true"}}}
+	}
+	provisioned := func(role string, namespaces ...string) *v1alpha2.SpinnakerAccount {
+		a := newAccount("kube1", interfaces.KubernetesAccountType, true)
+		a.Namespace = "team-a"
+		a.Spec.Kubernetes = &interfaces.KubernetesAuth{Provision: &interfaces.KubernetesProvision{Namespaces: namespaces, Role: role}}
+		return a
+	}
+	c := fakeClient(t, selected("team-a"), selected("team-a-dev"), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}})
+	spinsvc := newSpinnakerService(interfaces.AccountConfig{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"spinnaker": "true"}}})
+
+	assert.Nil(t, CheckProvisioning(context.TODO(), c, spinsvc, provisioned("", "team-a", "team-a-dev")))
+	assert.Nil(t, CheckProvisioning(context.TODO(), c, spinsvc, newAccount("aws1", interfaces.AWSAccountType, true)))
+
+	err := CheckProvisioning(context.TODO(), c, spinsvc, provisioned("", "team-a", "kube-system"))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "namespace kube-system is not selected by SpinnakerService spinnaker (spec.accounts.namespaceSelector)", err.Error())
+	}
+	assert.NotNil(t, CheckProvisioning(context.TODO(), c, spinsvc, provisioned("", "unknown")))
+	assert.NotNil(t, CheckProvisioning(context.TODO(), c, spinsvc, provisioned("cluster-admin", "team-a")))
+	assert.NotNil(t, CheckProvisioning(context.TODO(), c, nil, provisioned("", "team-a")))
+}
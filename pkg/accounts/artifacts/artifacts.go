@@ -0,0 +1,65 @@
+package artifacts
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+)
+
+// Artifact accounts are read from their section of the SpinnakerAccount (e.g. `githubArtifact`) or from
+// Spinnaker settings under `artifacts.<kind>.accounts`. Secrets referenced in Kubernetes secrets are read when
+// the account is rendered to Clouddriver's settings.
+const (
+	UsernameSettings = "username"
+	PasswordSettings = "password"
+	TokenSettings    = "token"
+)
+
+var (
+	noGitHubDefinedError = errors.New("githubArtifact needs to be defined")
+	noGitLabDefinedError = errors.New("gitlabArtifact needs to be defined")
+	noS3DefinedError     = errors.New("s3Artifact needs to be defined")
+	noGCSDefinedError    = errors.New("gcsArtifact needs to be defined")
+	noHelmDefinedError   = errors.New("helmArtifact needs to be defined")
+	noHTTPDefinedError   = errors.New("httpArtifact needs to be defined")
+	noRepositoryError    = errors.New("helm artifact repository is required")
+)
+
+// artifactKind holds what's common to the types of a kind of artifact account
+type artifactKind struct {
+	accountType interfaces.AccountType
+	// name of the kind in Spinnaker's settings (e.g. github in artifacts.github.accounts)
+	name string
+}
+
+func (k artifactKind) accountsKey() string {
+	return fmt.Sprintf("artifacts.%s.accounts", k.name)
+}
+
+func (k artifactKind) services() []string {
+	return []string{"clouddriver"}
+}
+
+// validationSettings returns the validation settings of the kind in spec.validation.artifacts
+func (k artifactKind) validationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	v := spinsvc.GetSpinnakerValidation()
+	for n, s := range v.Artifacts {
+		if strings.ToLower(n) == k.name {
+			return &s
+		}
+	}
+	return v.GetValidationSettings()
+}
+
+// validateUsernamePassword checks that username and password are both set or both empty
+func validateUsernamePassword(name, username, password string) error {
+	if username != "" && password == "" {
+		return fmt.Errorf("artifact account \"%s\" has a username but no password", name)
+	}
+	if username == "" && password != "" {
+		return fmt.Errorf("artifact account \"%s\" has a password but no username", name)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package artifacts
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/user/repos", r.URL.Path)
+		if r.Header.Get("Authorization") != "token good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "Bad credentials"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	c := &gitHubClientImpl{baseUrl: srv.URL}
+	assert.Nil(t, c.ListRepos(context.TODO(), "", "", "good"))
+	err := c.ListRepos(context.TODO(), "", "", "bad")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Bad credentials")
+	}
+}
+
+func TestGitLabClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/projects", r.URL.Path)
+		if r.Header.Get("PRIVATE-TOKEN") != "good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	c := &gitLabClientImpl{baseUrl: srv.URL}
+	assert.Nil(t, c.ListProjects(context.TODO(), "good"))
+	assert.NotNil(t, c.ListProjects(context.TODO(), "bad"))
+}
+
+func TestHelmClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/charts/index.yaml":
+			u, p, ok := r.BasicAuth()
+			if !ok || u != "admin" || p != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte("apiVersion: v1\nentries: {}\n"))
+		case "/empty/index.yaml":
+			_, _ = w.Write([]byte("entries: {}\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := &helmClientImpl{}
+	assert.Nil(t, c.FetchIndex(context.TODO(), srv.URL+"/charts/", "admin", "secret"))
+	assert.NotNil(t, c.FetchIndex(context.TODO(), srv.URL+"/charts", "admin", "wrong"))
+	err := c.FetchIndex(context.TODO(), srv.URL+"/empty", "", "")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "missing apiVersion")
+	}
+	assert.NotNil(t, c.FetchIndex(context.TODO(), srv.URL+"/missing", "", ""))
+}
+
+func TestS3Client(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		if r.URL.Path != "/my-bucket" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := aws.NewConfig().
+		WithRegion(defaultS3Region).
+		WithEndpoint(srv.URL).
+		WithS3ForcePathStyle(true).
+		WithCredentials(credentials.NewStaticCredentials("AKIA", "secret", ""))
+	c := &s3ClientImpl{}
+	assert.Nil(t, c.HeadBucket(context.TODO(), cfg, "my-bucket"))
+	assert.NotNil(t, c.HeadBucket(context.TODO(), cfg, "other-bucket"))
+}
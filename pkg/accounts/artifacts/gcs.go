@@ -0,0 +1,127 @@
+package artifacts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const JsonPathSettings = "jsonPath"
+
+var gcsKind = artifactKind{accountType: interfaces.GCSArtifactAccountType, name: "gcs"}
+
+type GCSAccountType struct{}
+
+func (k *GCSAccountType) GetType() interfaces.AccountType {
+	return gcsKind.accountType
+}
+
+func (k *GCSAccountType) GetAccountsKey() string {
+	return gcsKind.accountsKey()
+}
+
+func (k *GCSAccountType) GetConfigAccountsKey() string {
+	return gcsKind.accountsKey()
+}
+
+func (k *GCSAccountType) GetServices() []string {
+	return gcsKind.services()
+}
+
+// GetPrimaryAccountsKey returns an empty key, artifact accounts have no primary account
+func (k *GCSAccountType) GetPrimaryAccountsKey() string {
+	return ""
+}
+
+func (k *GCSAccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	return gcsKind.validationSettings(spinsvc)
+}
+
+func (k *GCSAccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := &GCSAccount{Name: account.GetName(), Settings: account.GetSpec().Settings}
+	if account.GetSpec().GCSArtifact == nil {
+		return nil, noGCSDefinedError
+	}
+	account.GetSpec().GCSArtifact.DeepCopyInto(&a.GCS)
+	return a, nil
+}
+
+func (k *GCSAccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	name, err := account.ParseName(k.GetType(), settings)
+	if err != nil {
+		return nil, err
+	}
+	return &GCSAccount{Name: name, Settings: settings}, nil
+}
+
+type GCSAccount struct {
+	*account.BaseAccount
+	Name     string                        `json:"name,omitempty"`
+	GCS      interfaces.GCSArtifactAccount `json:"gcs,omitempty"`
+	Settings interfaces.FreeForm           `json:"settings,omitempty"`
+}
+
+func (k *GCSAccount) GetType() interfaces.AccountType {
+	return gcsKind.accountType
+}
+
+func (k *GCSAccount) GetName() string {
+	return k.Name
+}
+
+func (k *GCSAccount) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *GCSAccount) NewValidator() account.AccountValidator {
+	return &gcsAccountValidator{account: k, client: &gcsClientImpl{baseUrl: gcsApiUrl}}
+}
+
+func (k *GCSAccount) NewSettingsValidator() account.AccountValidator {
+	return &gcsAccountValidator{account: k}
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings.
+// The key referenced in a Kubernetes secret is mounted in Clouddriver's pods when Spinnaker is deployed.
+func (k *GCSAccount) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	if ref := k.GCS.JsonKeySecret; ref != nil {
+		m[JsonPathSettings] = account.SecretFileReference(ref)
+	}
+	return m, nil
+}
+
+type gcsAccountValidator struct {
+	account *GCSAccount
+	// client is nil if only settings are validated
+	client gcsClient
+}
+
+func (v *gcsAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	key, err := account.ReadJsonKey(ctx, v.account.GCS.JsonKeySecret, v.account.Settings, spinSvc)
+	if err != nil {
+		return fmt.Errorf("error reading service account key of gcs artifact account \"%s\":\n  %w", v.account.Name, err)
+	}
+	if key == nil {
+		// Clouddriver will use its default credentials, nothing more we can check
+		return nil
+	}
+	k := struct {
+		ProjectId string `json:"project_id"`
+	}{}
+	if err := json.Unmarshal(key, &k); err != nil {
+		return fmt.Errorf("invalid service account key in gcs artifact account \"%s\":\n  %w", v.account.Name, err)
+	}
+	if v.client == nil {
+		return nil
+	}
+	if err := v.client.ListBuckets(ctx, key, k.ProjectId); err != nil {
+		return fmt.Errorf("unable to list buckets with gcs artifact account \"%s\":\n  %w", v.account.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	googleoauth "golang.org/x/oauth2/google"
+)
+
+const (
+	gcsApiUrl = "https://storage.googleapis.com/storage/v1"
+	gcsScope  = "https://www.googleapis.com/auth/devstorage.read_only"
+)
+
+// gcsClient lists the buckets of a project with a service account key
+type gcsClient interface {
+	ListBuckets(ctx context.Context, jsonKey []byte, project string) error
+}
+
+type gcsClientImpl struct {
+	baseUrl string
+}
+
+func (g *gcsClientImpl) ListBuckets(ctx context.Context, jsonKey []byte, project string) error {
+	creds, err := googleoauth.CredentialsFromJSON(ctx, jsonKey, gcsScope)
+	if err != nil {
+		return err
+	}
+	token, err := creds.TokenSource.Token()
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/b?maxResults=1&project=%s", g.baseUrl, url.QueryEscape(project))
+	_, err = get(ctx, u, func(req *http.Request) {
+		token.SetAuthHeader(req)
+	})
+	return err
+}
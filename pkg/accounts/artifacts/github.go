@@ -0,0 +1,129 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"github.com/mitchellh/mapstructure"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var gitHubKind = artifactKind{accountType: interfaces.GitHubArtifactAccountType, name: "github"}
+
+type GitHubAccountType struct{}
+
+func (k *GitHubAccountType) GetType() interfaces.AccountType {
+	return gitHubKind.accountType
+}
+
+func (k *GitHubAccountType) GetAccountsKey() string {
+	return gitHubKind.accountsKey()
+}
+
+func (k *GitHubAccountType) GetConfigAccountsKey() string {
+	return gitHubKind.accountsKey()
+}
+
+func (k *GitHubAccountType) GetServices() []string {
+	return gitHubKind.services()
+}
+
+// GetPrimaryAccountsKey returns an empty key, artifact accounts have no primary account
+func (k *GitHubAccountType) GetPrimaryAccountsKey() string {
+	return ""
+}
+
+func (k *GitHubAccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	return gitHubKind.validationSettings(spinsvc)
+}
+
+func (k *GitHubAccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := &GitHubAccount{Name: account.GetName(), Settings: account.GetSpec().Settings}
+	if account.GetSpec().GitHubArtifact == nil {
+		return nil, noGitHubDefinedError
+	}
+	account.GetSpec().GitHubArtifact.DeepCopyInto(&a.GitHub)
+	return a, nil
+}
+
+func (k *GitHubAccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	name, err := account.ParseName(k.GetType(), settings)
+	if err != nil {
+		return nil, err
+	}
+	a := &GitHubAccount{Name: name, Settings: settings}
+	if err := mapstructure.Decode(settings, &a.GitHub); err != nil {
+		return nil, fmt.Errorf("Error reading github artifact settings for account \"%s\":\n  %w", name, err)
+	}
+	return a, nil
+}
+
+type GitHubAccount struct {
+	*account.BaseAccount
+	Name     string                           `json:"name,omitempty"`
+	GitHub   interfaces.GitHubArtifactAccount `json:"github,omitempty"`
+	Settings interfaces.FreeForm              `json:"settings,omitempty"`
+}
+
+func (k *GitHubAccount) GetType() interfaces.AccountType {
+	return gitHubKind.accountType
+}
+
+func (k *GitHubAccount) GetName() string {
+	return k.Name
+}
+
+func (k *GitHubAccount) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *GitHubAccount) NewValidator() account.AccountValidator {
+	return &gitHubAccountValidator{account: k, client: &gitHubClientImpl{baseUrl: gitHubApiUrl}}
+}
+
+func (k *GitHubAccount) NewSettingsValidator() account.AccountValidator {
+	return &gitHubAccountValidator{account: k}
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
+func (k *GitHubAccount) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	if k.GitHub.Username != "" {
+		m[UsernameSettings] = k.GitHub.Username
+	}
+	account.SetSecretReference(m, k.GitHub.PasswordSecret, PasswordSettings)
+	account.SetSecretReference(m, k.GitHub.TokenSecret, TokenSettings)
+	return m, nil
+}
+
+type gitHubAccountValidator struct {
+	account *GitHubAccount
+	// client is nil if only settings are validated
+	client gitHubClient
+}
+
+func (v *gitHubAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	a := v.account
+	password, err := account.GetSecretValue(ctx, a.GitHub.PasswordSecret, a.Settings, PasswordSettings)
+	if err != nil {
+		return err
+	}
+	token, err := account.GetSecretValue(ctx, a.GitHub.TokenSecret, a.Settings, TokenSettings)
+	if err != nil {
+		return err
+	}
+	if err := validateUsernamePassword(a.Name, a.GitHub.Username, password); err != nil {
+		return err
+	}
+	if v.client == nil || token == "" && password == "" {
+		// Anonymous access or credentials from files
+		return nil
+	}
+	if err := v.client.ListRepos(ctx, a.GitHub.Username, password, token); err != nil {
+		return fmt.Errorf("unable to list repositories with github artifact account \"%s\":\n  %w", a.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package artifacts
+
+import (
+	"context"
+	"net/http"
+)
+
+const gitHubApiUrl = "https://api.github.com"
+
+// gitHubClient lists the repositories visible with the given credentials
+type gitHubClient interface {
+	ListRepos(ctx context.Context, username, password, token string) error
+}
+
+type gitHubClientImpl struct {
+	baseUrl string
+}
+
+func (g *gitHubClientImpl) ListRepos(ctx context.Context, username, password, token string) error {
+	_, err := get(ctx, g.baseUrl+"/user/repos?per_page=1", func(req *http.Request) {
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+		if token != "" {
+			req.Header.Set("Authorization", "token "+token)
+		} else {
+			req.SetBasicAuth(username, password)
+		}
+	})
+	return err
+}
@@ -0,0 +1,113 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var gitLabKind = artifactKind{accountType: interfaces.GitLabArtifactAccountType, name: "gitlab"}
+
+type GitLabAccountType struct{}
+
+func (k *GitLabAccountType) GetType() interfaces.AccountType {
+	return gitLabKind.accountType
+}
+
+func (k *GitLabAccountType) GetAccountsKey() string {
+	return gitLabKind.accountsKey()
+}
+
+func (k *GitLabAccountType) GetConfigAccountsKey() string {
+	return gitLabKind.accountsKey()
+}
+
+func (k *GitLabAccountType) GetServices() []string {
+	return gitLabKind.services()
+}
+
+// GetPrimaryAccountsKey returns an empty key, artifact accounts have no primary account
+func (k *GitLabAccountType) GetPrimaryAccountsKey() string {
+	return ""
+}
+
+func (k *GitLabAccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	return gitLabKind.validationSettings(spinsvc)
+}
+
+func (k *GitLabAccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := &GitLabAccount{Name: account.GetName(), Settings: account.GetSpec().Settings}
+	if account.GetSpec().GitLabArtifact == nil {
+		return nil, noGitLabDefinedError
+	}
+	account.GetSpec().GitLabArtifact.DeepCopyInto(&a.GitLab)
+	return a, nil
+}
+
+func (k *GitLabAccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	name, err := account.ParseName(k.GetType(), settings)
+	if err != nil {
+		return nil, err
+	}
+	return &GitLabAccount{Name: name, Settings: settings}, nil
+}
+
+type GitLabAccount struct {
+	*account.BaseAccount
+	Name     string                           `json:"name,omitempty"`
+	GitLab   interfaces.GitLabArtifactAccount `json:"gitlab,omitempty"`
+	Settings interfaces.FreeForm              `json:"settings,omitempty"`
+}
+
+func (k *GitLabAccount) GetType() interfaces.AccountType {
+	return gitLabKind.accountType
+}
+
+func (k *GitLabAccount) GetName() string {
+	return k.Name
+}
+
+func (k *GitLabAccount) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *GitLabAccount) NewValidator() account.AccountValidator {
+	return &gitLabAccountValidator{account: k, client: &gitLabClientImpl{baseUrl: gitLabUrl}}
+}
+
+func (k *GitLabAccount) NewSettingsValidator() account.AccountValidator {
+	return &gitLabAccountValidator{account: k}
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
+func (k *GitLabAccount) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	account.SetSecretReference(m, k.GitLab.TokenSecret, TokenSettings)
+	return m, nil
+}
+
+type gitLabAccountValidator struct {
+	account *GitLabAccount
+	// client is nil if only settings are validated
+	client gitLabClient
+}
+
+func (v *gitLabAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	a := v.account
+	token, err := account.GetSecretValue(ctx, a.GitLab.TokenSecret, a.Settings, TokenSettings)
+	if err != nil {
+		return err
+	}
+	if v.client == nil || token == "" {
+		// Anonymous access or token from a file
+		return nil
+	}
+	if err := v.client.ListProjects(ctx, token); err != nil {
+		return fmt.Errorf("unable to list projects with gitlab artifact account \"%s\":\n  %w", a.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package artifacts
+
+import (
+	"context"
+	"net/http"
+)
+
+const gitLabUrl = "https://gitlab.com"
+
+// gitLabClient lists the projects the token gives access to
+type gitLabClient interface {
+	ListProjects(ctx context.Context, token string) error
+}
+
+type gitLabClientImpl struct {
+	baseUrl string
+}
+
+func (g *gitLabClientImpl) ListProjects(ctx context.Context, token string) error {
+	_, err := get(ctx, g.baseUrl+"/api/v4/projects?membership=true&per_page=1", func(req *http.Request) {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	})
+	return err
+}
@@ -0,0 +1,132 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"github.com/mitchellh/mapstructure"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const RepositorySettings = "repository"
+
+var helmKind = artifactKind{accountType: interfaces.HelmArtifactAccountType, name: "helm"}
+
+type HelmAccountType struct{}
+
+func (k *HelmAccountType) GetType() interfaces.AccountType {
+	return helmKind.accountType
+}
+
+func (k *HelmAccountType) GetAccountsKey() string {
+	return helmKind.accountsKey()
+}
+
+func (k *HelmAccountType) GetConfigAccountsKey() string {
+	return helmKind.accountsKey()
+}
+
+func (k *HelmAccountType) GetServices() []string {
+	return helmKind.services()
+}
+
+// GetPrimaryAccountsKey returns an empty key, artifact accounts have no primary account
+func (k *HelmAccountType) GetPrimaryAccountsKey() string {
+	return ""
+}
+
+func (k *HelmAccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	return helmKind.validationSettings(spinsvc)
+}
+
+func (k *HelmAccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := &HelmAccount{Name: account.GetName(), Settings: account.GetSpec().Settings}
+	if account.GetSpec().HelmArtifact == nil {
+		return nil, noHelmDefinedError
+	}
+	account.GetSpec().HelmArtifact.DeepCopyInto(&a.Helm)
+	return a, nil
+}
+
+func (k *HelmAccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	name, err := account.ParseName(k.GetType(), settings)
+	if err != nil {
+		return nil, err
+	}
+	a := &HelmAccount{Name: name, Settings: settings}
+	if err := mapstructure.Decode(settings, &a.Helm); err != nil {
+		return nil, fmt.Errorf("Error reading helm artifact settings for account \"%s\":\n  %w", name, err)
+	}
+	return a, nil
+}
+
+type HelmAccount struct {
+	*account.BaseAccount
+	Name     string                         `json:"name,omitempty"`
+	Helm     interfaces.HelmArtifactAccount `json:"helm,omitempty"`
+	Settings interfaces.FreeForm            `json:"settings,omitempty"`
+}
+
+func (k *HelmAccount) GetType() interfaces.AccountType {
+	return helmKind.accountType
+}
+
+func (k *HelmAccount) GetName() string {
+	return k.Name
+}
+
+func (k *HelmAccount) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *HelmAccount) NewValidator() account.AccountValidator {
+	return &helmAccountValidator{account: k, client: &helmClientImpl{}}
+}
+
+func (k *HelmAccount) NewSettingsValidator() account.AccountValidator {
+	return &helmAccountValidator{account: k}
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
+func (k *HelmAccount) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	if k.Helm.Repository == "" {
+		return nil, noRepositoryError
+	}
+	m[RepositorySettings] = k.Helm.Repository
+	if k.Helm.Username != "" {
+		m[UsernameSettings] = k.Helm.Username
+	}
+	account.SetSecretReference(m, k.Helm.PasswordSecret, PasswordSettings)
+	return m, nil
+}
+
+type helmAccountValidator struct {
+	account *HelmAccount
+	// client is nil if only settings are validated
+	client helmClient
+}
+
+func (v *helmAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	a := v.account
+	if a.Helm.Repository == "" {
+		return noRepositoryError
+	}
+	password, err := account.GetSecretValue(ctx, a.Helm.PasswordSecret, a.Settings, PasswordSettings)
+	if err != nil {
+		return err
+	}
+	if err := validateUsernamePassword(a.Name, a.Helm.Username, password); err != nil {
+		return err
+	}
+	if v.client == nil {
+		return nil
+	}
+	if err := v.client.FetchIndex(ctx, a.Helm.Repository, a.Helm.Username, password); err != nil {
+		return fmt.Errorf("unable to fetch the index of helm artifact account \"%s\":\n  %w", a.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// helmClient fetches the index of a chart repository
+type helmClient interface {
+	FetchIndex(ctx context.Context, repository, username, password string) error
+}
+
+type helmClientImpl struct{}
+
+type helmIndex struct {
+	ApiVersion string                 `json:"apiVersion"`
+	Entries    map[string]interface{} `json:"entries"`
+}
+
+func (h *helmClientImpl) FetchIndex(ctx context.Context, repository, username, password string) error {
+	b, err := get(ctx, strings.TrimSuffix(repository, "/")+"/index.yaml", func(req *http.Request) {
+		if username != "" {
+			req.SetBasicAuth(username, password)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	idx := helmIndex{}
+	if err := yaml.Unmarshal(b, &idx); err != nil {
+		return fmt.Errorf("invalid index: %w", err)
+	}
+	if idx.ApiVersion == "" {
+		return fmt.Errorf("invalid index: missing apiVersion")
+	}
+	return nil
+}
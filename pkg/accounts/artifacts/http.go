@@ -0,0 +1,109 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"github.com/mitchellh/mapstructure"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var httpKind = artifactKind{accountType: interfaces.HTTPArtifactAccountType, name: "http"}
+
+type HTTPAccountType struct{}
+
+func (k *HTTPAccountType) GetType() interfaces.AccountType {
+	return httpKind.accountType
+}
+
+func (k *HTTPAccountType) GetAccountsKey() string {
+	return httpKind.accountsKey()
+}
+
+func (k *HTTPAccountType) GetConfigAccountsKey() string {
+	return httpKind.accountsKey()
+}
+
+func (k *HTTPAccountType) GetServices() []string {
+	return httpKind.services()
+}
+
+// GetPrimaryAccountsKey returns an empty key, artifact accounts have no primary account
+func (k *HTTPAccountType) GetPrimaryAccountsKey() string {
+	return ""
+}
+
+func (k *HTTPAccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	return httpKind.validationSettings(spinsvc)
+}
+
+func (k *HTTPAccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := &HTTPAccount{Name: account.GetName(), Settings: account.GetSpec().Settings}
+	if account.GetSpec().HTTPArtifact == nil {
+		return nil, noHTTPDefinedError
+	}
+	account.GetSpec().HTTPArtifact.DeepCopyInto(&a.HTTP)
+	return a, nil
+}
+
+func (k *HTTPAccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	name, err := account.ParseName(k.GetType(), settings)
+	if err != nil {
+		return nil, err
+	}
+	a := &HTTPAccount{Name: name, Settings: settings}
+	if err := mapstructure.Decode(settings, &a.HTTP); err != nil {
+		return nil, fmt.Errorf("Error reading http artifact settings for account \"%s\":\n  %w", name, err)
+	}
+	return a, nil
+}
+
+type HTTPAccount struct {
+	*account.BaseAccount
+	Name     string                         `json:"name,omitempty"`
+	HTTP     interfaces.HTTPArtifactAccount `json:"http,omitempty"`
+	Settings interfaces.FreeForm            `json:"settings,omitempty"`
+}
+
+func (k *HTTPAccount) GetType() interfaces.AccountType {
+	return httpKind.accountType
+}
+
+func (k *HTTPAccount) GetName() string {
+	return k.Name
+}
+
+func (k *HTTPAccount) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *HTTPAccount) NewValidator() account.AccountValidator {
+	return &httpAccountValidator{account: k}
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
+func (k *HTTPAccount) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	if k.HTTP.Username != "" {
+		m[UsernameSettings] = k.HTTP.Username
+	}
+	account.SetSecretReference(m, k.HTTP.PasswordSecret, PasswordSettings)
+	return m, nil
+}
+
+// httpAccountValidator checks credentials, there's no fixed URL to check them against
+type httpAccountValidator struct {
+	account *HTTPAccount
+}
+
+func (v *httpAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	a := v.account
+	password, err := account.GetSecretValue(ctx, a.HTTP.PasswordSecret, a.Settings, PasswordSettings)
+	if err != nil {
+		return err
+	}
+	return validateUsernamePassword(a.Name, a.HTTP.Username, password)
+}
@@ -0,0 +1,213 @@
+package artifacts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func TestFromCRD(t *testing.T) {
+	tests := []struct {
+		name        string
+		accountType account.SpinnakerAccountType
+		manifest    string
+		expected    func(t *testing.T, a account.Account, err error)
+	}{
+		{
+			name:        "no githubArtifact section in CRD",
+			accountType: &GitHubAccountType{},
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: GitHubArtifact
+`,
+			expected: func(t *testing.T, _ account.Account, err error) {
+				assert.Equal(t, noGitHubDefinedError, err)
+			},
+		},
+		{
+			name:        "github with token",
+			accountType: &GitHubAccountType{},
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: GitHubArtifact
+  githubArtifact:
+    tokenSecret:
+      name: github
+      key: token
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if assert.Nil(t, err) {
+					g := a.(*GitHubAccount)
+					assert.Equal(t, "account1", g.Name)
+					assert.Equal(t, "github", g.GitHub.TokenSecret.Name)
+				}
+			},
+		},
+		{
+			name:        "gitlab",
+			accountType: &GitLabAccountType{},
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: GitLabArtifact
+  gitlabArtifact: {}
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if assert.Nil(t, err) {
+					assert.Nil(t, a.(*GitLabAccount).GitLab.TokenSecret)
+				}
+			},
+		},
+		{
+			name:        "s3 with custom endpoint",
+			accountType: &S3AccountType{},
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: S3Artifact
+  s3Artifact:
+    apiEndpoint: http://minio:9000
+    apiRegion: us-west-2
+    awsAccessKeyId: AKIA
+    awsSecretAccessKeySecret:
+      name: minio
+      key: secretKey
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if assert.Nil(t, err) {
+					s := a.(*S3Account)
+					assert.Equal(t, "http://minio:9000", s.S3.ApiEndpoint)
+					assert.Equal(t, "AKIA", s.S3.AwsAccessKeyId)
+					assert.Equal(t, "secretKey", s.S3.AwsSecretAccessKeySecret.Key)
+				}
+			},
+		},
+		{
+			name:        "no helmArtifact section in CRD",
+			accountType: &HelmAccountType{},
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: HelmArtifact
+`,
+			expected: func(t *testing.T, _ account.Account, err error) {
+				assert.Equal(t, noHelmDefinedError, err)
+			},
+		},
+		{
+			name:        "helm",
+			accountType: &HelmAccountType{},
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: HelmArtifact
+  helmArtifact:
+    repository: https://charts.helm.sh/stable
+    username: admin
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if assert.Nil(t, err) {
+					h := a.(*HelmAccount)
+					assert.Equal(t, "https://charts.helm.sh/stable", h.Helm.Repository)
+					assert.Equal(t, "admin", h.Helm.Username)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sa := test.TypesFactory.NewAccount()
+			if !assert.Nil(t, yaml.Unmarshal([]byte(tt.manifest), sa)) {
+				return
+			}
+			a, err := tt.accountType.FromCRD(sa)
+			tt.expected(t, a, err)
+		})
+	}
+}
+
+func TestFromSpinnakerSettings(t *testing.T) {
+	k := &S3AccountType{}
+	a, err := k.FromSpinnakerConfig(context.TODO(), map[string]interface{}{
+		"name":               "s3",
+		"region":             "eu-west-1",
+		"awsAccessKeyId":     "AKIA",
+		"awsSecretAccessKey": "encrypted:s3!b:bucket!f:secrets.yml!k:s3.secretKey",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	s := a.(*S3Account)
+	assert.Equal(t, "s3", s.GetName())
+	assert.Equal(t, "eu-west-1", s.S3.Region)
+	assert.Equal(t, "AKIA", s.S3.AwsAccessKeyId)
+
+	ss, err := s.ToSpinnakerSettings(context.TODO())
+	if assert.Nil(t, err) {
+		assert.Equal(t, "encrypted:s3!b:bucket!f:secrets.yml!k:s3.secretKey", ss[AwsSecretAccessKeySettings])
+		assert.Equal(t, "eu-west-1", ss[RegionSettings])
+		assert.Nil(t, ss[ApiEndpointSettings])
+	}
+
+	_, err = k.FromSpinnakerConfig(context.TODO(), map[string]interface{}{"region": "eu-west-1"})
+	assert.NotNil(t, err)
+}
+
+func TestToSpinnakerSettings(t *testing.T) {
+	g := &GCSAccount{
+		Name: "gcs",
+		GCS: interfaces.GCSArtifactAccount{
+			JsonKeySecret: &interfaces.SecretInNamespaceReference{Name: "gcs-secret", Key: "key.json"},
+		},
+	}
+	ss, err := g.ToSpinnakerSettings(context.TODO())
+	if assert.Nil(t, err) {
+		assert.Equal(t, "gcs", ss["name"])
+		assert.Equal(t, "encryptedFile:k8s!n:gcs-secret!k:key.json", ss[JsonPathSettings])
+	}
+
+	h := &HelmAccount{Name: "helm"}
+	_, err = h.ToSpinnakerSettings(context.TODO())
+	assert.Equal(t, noRepositoryError, err)
+}
+
+func TestAccountsKeys(t *testing.T) {
+	types := []account.SpinnakerAccountType{
+		&GitHubAccountType{}, &GitLabAccountType{}, &S3AccountType{}, &GCSAccountType{}, &HelmAccountType{}, &HTTPAccountType{},
+	}
+	expected := []string{
+		"artifacts.github.accounts", "artifacts.gitlab.accounts", "artifacts.s3.accounts",
+		"artifacts.gcs.accounts", "artifacts.helm.accounts", "artifacts.http.accounts",
+	}
+	for i, tp := range types {
+		assert.Equal(t, expected[i], tp.GetAccountsKey())
+		assert.Equal(t, expected[i], tp.GetConfigAccountsKey())
+		assert.Equal(t, []string{"clouddriver"}, tp.GetServices())
+	}
+}
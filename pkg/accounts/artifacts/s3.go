@@ -0,0 +1,167 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/go-logr/logr"
+	"github.com/mitchellh/mapstructure"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ApiEndpointSettings        = "apiEndpoint"
+	ApiRegionSettings          = "apiRegion"
+	RegionSettings             = "region"
+	AwsAccessKeyIdSettings     = "awsAccessKeyId"
+	AwsSecretAccessKeySettings = "awsSecretAccessKey"
+	defaultS3Region            = "us-east-1"
+)
+
+var s3Kind = artifactKind{accountType: interfaces.S3ArtifactAccountType, name: "s3"}
+
+type S3AccountType struct{}
+
+func (k *S3AccountType) GetType() interfaces.AccountType {
+	return s3Kind.accountType
+}
+
+func (k *S3AccountType) GetAccountsKey() string {
+	return s3Kind.accountsKey()
+}
+
+func (k *S3AccountType) GetConfigAccountsKey() string {
+	return s3Kind.accountsKey()
+}
+
+func (k *S3AccountType) GetServices() []string {
+	return s3Kind.services()
+}
+
+// GetPrimaryAccountsKey returns an empty key, artifact accounts have no primary account
+func (k *S3AccountType) GetPrimaryAccountsKey() string {
+	return ""
+}
+
+func (k *S3AccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	return s3Kind.validationSettings(spinsvc)
+}
+
+func (k *S3AccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := &S3Account{Name: account.GetName(), Settings: account.GetSpec().Settings}
+	if account.GetSpec().S3Artifact == nil {
+		return nil, noS3DefinedError
+	}
+	account.GetSpec().S3Artifact.DeepCopyInto(&a.S3)
+	return a, nil
+}
+
+func (k *S3AccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	name, err := account.ParseName(k.GetType(), settings)
+	if err != nil {
+		return nil, err
+	}
+	a := &S3Account{Name: name, Settings: settings}
+	if err := mapstructure.Decode(settings, &a.S3); err != nil {
+		return nil, fmt.Errorf("Error reading s3 artifact settings for account \"%s\":\n  %w", name, err)
+	}
+	return a, nil
+}
+
+type S3Account struct {
+	*account.BaseAccount
+	Name     string                       `json:"name,omitempty"`
+	S3       interfaces.S3ArtifactAccount `json:"s3,omitempty"`
+	Settings interfaces.FreeForm          `json:"settings,omitempty"`
+}
+
+func (k *S3Account) GetType() interfaces.AccountType {
+	return s3Kind.accountType
+}
+
+func (k *S3Account) GetName() string {
+	return k.Name
+}
+
+func (k *S3Account) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *S3Account) NewValidator() account.AccountValidator {
+	return &s3AccountValidator{account: k, client: &s3ClientImpl{}}
+}
+
+func (k *S3Account) NewSettingsValidator() account.AccountValidator {
+	return &s3AccountValidator{account: k}
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
+func (k *S3Account) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	s := k.S3
+	if s.ApiEndpoint != "" {
+		m[ApiEndpointSettings] = s.ApiEndpoint
+	}
+	if s.ApiRegion != "" {
+		m[ApiRegionSettings] = s.ApiRegion
+	}
+	if s.Region != "" {
+		m[RegionSettings] = s.Region
+	}
+	if s.AwsAccessKeyId != "" {
+		m[AwsAccessKeyIdSettings] = s.AwsAccessKeyId
+	}
+	account.SetSecretReference(m, s.AwsSecretAccessKeySecret, AwsSecretAccessKeySettings)
+	return m, nil
+}
+
+type s3AccountValidator struct {
+	account *S3Account
+	// client is nil if only settings are validated
+	client s3Client
+}
+
+func (v *s3AccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	cfg, err := v.makeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if v.client == nil || v.account.S3.Bucket == "" {
+		// Accounts may only have access to some buckets, there's nothing to check without one
+		return nil
+	}
+	if err := v.client.HeadBucket(ctx, cfg, v.account.S3.Bucket); err != nil {
+		return fmt.Errorf("unable to access bucket \"%s\" with s3 artifact account \"%s\":\n  %w", v.account.S3.Bucket, v.account.Name, err)
+	}
+	return nil
+}
+
+// makeConfig returns the AWS configuration Clouddriver would use for the account
+func (v *s3AccountValidator) makeConfig(ctx context.Context) (*aws.Config, error) {
+	s := v.account.S3
+	region := defaultS3Region
+	if s.ApiRegion != "" {
+		region = s.ApiRegion
+	} else if s.Region != "" {
+		region = s.Region
+	}
+	cfg := aws.NewConfig().WithRegion(region)
+	if s.ApiEndpoint != "" {
+		cfg = cfg.WithEndpoint(s.ApiEndpoint).WithS3ForcePathStyle(true)
+	}
+	secret, err := account.GetSecretValue(ctx, s.AwsSecretAccessKeySecret, v.account.Settings, AwsSecretAccessKeySettings)
+	if err != nil {
+		return nil, err
+	}
+	if s.AwsAccessKeyId != "" && secret == "" {
+		return nil, fmt.Errorf("s3 artifact account \"%s\" has an access key id but no secret access key", v.account.Name)
+	}
+	if s.AwsAccessKeyId != "" {
+		cfg = cfg.WithCredentials(credentials.NewStaticCredentials(s.AwsAccessKeyId, secret, ""))
+	}
+	return cfg, nil
+}
@@ -0,0 +1,25 @@
+package artifacts
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// s3Client checks the bucket is accessible with the given configuration
+type s3Client interface {
+	HeadBucket(ctx context.Context, cfg *aws.Config, bucket string) error
+}
+
+type s3ClientImpl struct{}
+
+func (s *s3ClientImpl) HeadBucket(ctx context.Context, cfg *aws.Config, bucket string) error {
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = s3.New(sess).HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucket)})
+	return err
+}
@@ -0,0 +1,279 @@
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	logr "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type fakeGitHubClient struct {
+	err      error
+	called   bool
+	username string
+	password string
+	token    string
+}
+
+func (f *fakeGitHubClient) ListRepos(ctx context.Context, username, password, token string) error {
+	f.called = true
+	f.username = username
+	f.password = password
+	f.token = token
+	return f.err
+}
+
+type fakeGitLabClient struct {
+	err   error
+	token string
+}
+
+func (f *fakeGitLabClient) ListProjects(ctx context.Context, token string) error {
+	f.token = token
+	return f.err
+}
+
+type fakeHelmClient struct {
+	err        error
+	repository string
+}
+
+func (f *fakeHelmClient) FetchIndex(ctx context.Context, repository, username, password string) error {
+	f.repository = repository
+	return f.err
+}
+
+type fakeS3Client struct {
+	err    error
+	cfg    *aws.Config
+	bucket string
+}
+
+func (f *fakeS3Client) HeadBucket(ctx context.Context, cfg *aws.Config, bucket string) error {
+	f.cfg = cfg
+	f.bucket = bucket
+	return f.err
+}
+
+type fakeGCSClient struct {
+	err     error
+	project string
+}
+
+func (f *fakeGCSClient) ListBuckets(ctx context.Context, jsonKey []byte, project string) error {
+	f.project = project
+	return f.err
+}
+
+func TestGitHubValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		github   interfaces.GitHubArtifactAccount
+		settings interfaces.FreeForm
+		client   *fakeGitHubClient
+		expected func(t *testing.T, c *fakeGitHubClient, err error)
+	}{
+		{
+			name:     "token from settings",
+			settings: interfaces.FreeForm{TokenSettings: "my-token"},
+			client:   &fakeGitHubClient{},
+			expected: func(t *testing.T, c *fakeGitHubClient, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "my-token", c.token)
+			},
+		},
+		{
+			name:     "username and password",
+			github:   interfaces.GitHubArtifactAccount{Username: "admin"},
+			settings: interfaces.FreeForm{PasswordSettings: "secret"},
+			client:   &fakeGitHubClient{},
+			expected: func(t *testing.T, c *fakeGitHubClient, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "admin", c.username)
+				assert.Equal(t, "secret", c.password)
+			},
+		},
+		{
+			name:   "username without password",
+			github: interfaces.GitHubArtifactAccount{Username: "admin"},
+			client: &fakeGitHubClient{},
+			expected: func(t *testing.T, c *fakeGitHubClient, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "has a username but no password")
+				}
+				assert.False(t, c.called)
+			},
+		},
+		{
+			name:   "anonymous access is not checked",
+			client: &fakeGitHubClient{},
+			expected: func(t *testing.T, c *fakeGitHubClient, err error) {
+				assert.Nil(t, err)
+				assert.False(t, c.called)
+			},
+		},
+		{
+			name:     "bad credentials",
+			settings: interfaces.FreeForm{TokenSettings: "my-token"},
+			client:   &fakeGitHubClient{err: errors.New("GET returned 401: Bad credentials")},
+			expected: func(t *testing.T, c *fakeGitHubClient, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "Bad credentials")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+			defer secrets.Cleanup(ctx)
+			v := &gitHubAccountValidator{
+				account: &GitHubAccount{Name: "github", GitHub: tt.github, Settings: tt.settings},
+				client:  tt.client,
+			}
+			err := v.Validate(nil, nil, ctx, logr.Log)
+			tt.expected(t, tt.client, err)
+		})
+	}
+}
+
+func TestGitLabValidate(t *testing.T) {
+	ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+	defer secrets.Cleanup(ctx)
+	c := &fakeGitLabClient{}
+	v := &gitLabAccountValidator{
+		account: &GitLabAccount{Name: "gitlab", Settings: interfaces.FreeForm{TokenSettings: "my-token"}},
+		client:  c,
+	}
+	assert.Nil(t, v.Validate(nil, nil, ctx, logr.Log))
+	assert.Equal(t, "my-token", c.token)
+
+	c.err = errors.New("401 Unauthorized")
+	err := v.Validate(nil, nil, ctx, logr.Log)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unable to list projects with gitlab artifact account \"gitlab\"")
+	}
+}
+
+func TestHelmValidate(t *testing.T) {
+	ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+	defer secrets.Cleanup(ctx)
+	c := &fakeHelmClient{}
+	v := &helmAccountValidator{account: &HelmAccount{Name: "helm"}, client: c}
+	assert.Equal(t, noRepositoryError, v.Validate(nil, nil, ctx, logr.Log))
+
+	v.account.Helm.Repository = "https://charts.helm.sh/stable"
+	assert.Nil(t, v.Validate(nil, nil, ctx, logr.Log))
+	assert.Equal(t, "https://charts.helm.sh/stable", c.repository)
+
+	c.err = errors.New("GET returned 404")
+	assert.NotNil(t, v.Validate(nil, nil, ctx, logr.Log))
+}
+
+func TestHTTPValidate(t *testing.T) {
+	ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+	defer secrets.Cleanup(ctx)
+	v := &httpAccountValidator{account: &HTTPAccount{Name: "http", Settings: interfaces.FreeForm{PasswordSettings: "secret"}}}
+	err := v.Validate(nil, nil, ctx, logr.Log)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "has a password but no username")
+	}
+	v.account.HTTP.Username = "admin"
+	assert.Nil(t, v.Validate(nil, nil, ctx, logr.Log))
+}
+
+func TestS3Validate(t *testing.T) {
+	ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+	defer secrets.Cleanup(ctx)
+	c := &fakeS3Client{}
+	v := &s3AccountValidator{
+		account: &S3Account{
+			Name:     "minio",
+			S3:       interfaces.S3ArtifactAccount{ApiEndpoint: "http://minio:9000", AwsAccessKeyId: "AKIA"},
+			Settings: interfaces.FreeForm{AwsSecretAccessKeySettings: "secret"},
+		},
+		client: c,
+	}
+	// no bucket to check
+	if !assert.Nil(t, v.Validate(nil, nil, ctx, logr.Log)) {
+		return
+	}
+	assert.Nil(t, c.cfg)
+
+	v.account.S3.Bucket = "my-bucket"
+	if !assert.Nil(t, v.Validate(nil, nil, ctx, logr.Log)) {
+		return
+	}
+	assert.Equal(t, "my-bucket", c.bucket)
+	assert.Equal(t, "http://minio:9000", aws.StringValue(c.cfg.Endpoint))
+	assert.True(t, aws.BoolValue(c.cfg.S3ForcePathStyle))
+	assert.Equal(t, defaultS3Region, aws.StringValue(c.cfg.Region))
+	creds, err := c.cfg.Credentials.Get()
+	if assert.Nil(t, err) {
+		assert.Equal(t, "AKIA", creds.AccessKeyID)
+		assert.Equal(t, "secret", creds.SecretAccessKey)
+	}
+
+	c.err = errors.New("Forbidden: 403")
+	err = v.Validate(nil, nil, ctx, logr.Log)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "unable to access bucket \"my-bucket\"")
+	}
+	// settings are checked without calling S3
+	err = v.account.NewSettingsValidator().Validate(nil, nil, ctx, logr.Log)
+	assert.Nil(t, err)
+
+	v.account.Settings = nil
+	err = v.Validate(nil, nil, ctx, logr.Log)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "has an access key id but no secret access key")
+	}
+}
+
+func TestGCSValidate(t *testing.T) {
+	ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+	defer secrets.Cleanup(ctx)
+	c := &fakeGCSClient{}
+	v := &gcsAccountValidator{account: &GCSAccount{Name: "gcs"}, client: c}
+	// default credentials
+	assert.Nil(t, v.Validate(nil, nil, ctx, logr.Log))
+	assert.Equal(t, "", c.project)
+
+	v.account.Settings = interfaces.FreeForm{JsonPathSettings: "gcs.json"}
+	spinsvc := test.ManifestToSpinService(`
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: test
+spec:
+  spinnakerConfig:
+    files:
+      gcs.json: |
+        {"type": "service_account", "project_id": "my-project"}
+`, t)
+	assert.Nil(t, v.Validate(spinsvc, nil, ctx, logr.Log))
+	assert.Equal(t, "my-project", c.project)
+}
+
+func TestGetValidationSettings(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(`
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: test
+spec:
+  validation:
+    artifacts:
+      github:
+        enabled: false
+`, t)
+	assert.False(t, (&GitHubAccountType{}).GetValidationSettings(spinsvc).Enabled)
+	assert.True(t, (&GitLabAccountType{}).GetValidationSettings(spinsvc).Enabled)
+}
@@ -0,0 +1,100 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+)
+
+// AWS accounts are read from the `aws` section of the SpinnakerAccount or from Spinnaker settings
+// (`accountId`, `assumeRole`, `externalId`, `regions`, `lifecycleHooks` and `lambdaEnabled`).
+// Any additional setting (e.g. `defaultKeyPair`, `edda`, `discovery`) is passed as is to clouddriver.
+const (
+	AccountIdSettings      = "accountId"
+	AssumeRoleSettings     = "assumeRole"
+	ExternalIdSettings     = "externalId"
+	RegionsSettings        = "regions"
+	LifecycleHooksSettings = "lifecycleHooks"
+	LambdaEnabledSettings  = "lambdaEnabled"
+)
+
+var (
+	noAWSDefinedError = errors.New("aws needs to be defined")
+	noAccountIdError  = errors.New("aws accountId is required")
+)
+
+type AccountType struct{}
+
+func (k *AccountType) GetType() interfaces.AccountType {
+	return interfaces.AWSAccountType
+}
+
+func (k *AccountType) GetAccountsKey() string {
+	return "aws.accounts"
+}
+
+func (k *AccountType) GetConfigAccountsKey() string {
+	return "providers.aws.accounts"
+}
+
+func (k *AccountType) GetServices() []string {
+	return []string{"clouddriver"}
+}
+
+func (k *AccountType) GetPrimaryAccountsKey() string {
+	return "providers.aws.primaryAccount"
+}
+
+func (k *AccountType) GetEnabledKey() string {
+	return "providers.aws.enabled"
+}
+
+func (k *AccountType) newAccount() *Account {
+	return &Account{
+		AWS: interfaces.AWSAccount{},
+	}
+}
+
+func (k *AccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	v := spinsvc.GetSpinnakerValidation()
+	for n, s := range v.Providers {
+		if strings.ToLower(n) == strings.ToLower(string(interfaces.AWSAccountType)) {
+			return &s
+		}
+	}
+	return v.GetValidationSettings()
+}
+
+type Account struct {
+	*account.BaseAccount
+	Name     string                `json:"name,omitempty"`
+	AWS      interfaces.AWSAccount `json:"aws,omitempty"`
+	Settings interfaces.FreeForm   `json:"settings,omitempty"`
+}
+
+func (k *Account) GetType() interfaces.AccountType {
+	return interfaces.AWSAccountType
+}
+
+func (k *Account) GetName() string {
+	return k.Name
+}
+
+func (k *Account) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *Account) NewValidator() account.AccountValidator {
+	return &awsAccountValidator{account: k, client: &stsClientImpl{}}
+}
+
+func (k *Account) NewSettingsValidator() account.AccountValidator {
+	v := &awsAccountValidator{account: k}
+	return account.ValidatorFunc(func(interfaces.SpinnakerService, client.Client, context.Context, logr.Logger) error {
+		return v.validateLifecycleHooks()
+	})
+}
@@ -1,7 +1,8 @@
-package validate
+package aws
 
 import (
 	"fmt"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"regexp"
 )
 
@@ -12,17 +13,11 @@ const (
 
 var validLifecycleHookResults = []string{"ABANDON", "CONTINUE"}
 
-type AwsLifecycleHook struct {
-	DefaultResult         string `json:"defaultResult,omitempty"`
-	HeartbeatTimeout      int32  `json:"heartbeatTimeout,omitempty"`
-	LifecycleTransition   string `json:"lifecycleTransition,omitempty"`
-	NotificationTargetARN string `json:"notificationTargetARN,omitempty"`
-	RoleARN               string `json:"roleARN,omitempty"`
-}
-
-type awsLifecycleHookValidation struct{}
+// LifecycleHookValidation checks AWS lifecycle hooks, whether they're defined in
+// a SpinnakerAccount or inlined in Spinnaker settings
+type LifecycleHookValidation struct{}
 
-func (a *awsLifecycleHookValidation) validate(hook AwsLifecycleHook) []error {
+func (a *LifecycleHookValidation) Validate(hook interfaces.AWSLifecycleHook) []error {
 	var errors []error
 
 	if !a.isValidSnsArn(hook.NotificationTargetARN) {
@@ -43,25 +38,25 @@ func (a *awsLifecycleHookValidation) validate(hook AwsLifecycleHook) []error {
 	return errors
 }
 
-func (a *awsLifecycleHookValidation) isValidSnsArn(arn string) bool {
+func (a *LifecycleHookValidation) isValidSnsArn(arn string) bool {
 	if len(regexp.MustCompile(snsPattern).FindStringSubmatch(arn)) == 0 {
 		return false
 	}
 	return true
 }
 
-func (a *awsLifecycleHookValidation) isValidRoleArn(arn string) bool {
+func (a *LifecycleHookValidation) isValidRoleArn(arn string) bool {
 	if len(regexp.MustCompile(iamRolePattern).FindStringSubmatch(arn)) == 0 {
 		return false
 	}
 	return true
 }
 
-func (a *awsLifecycleHookValidation) isValidHeartbeatTimeout(timeout int32) bool {
+func (a *LifecycleHookValidation) isValidHeartbeatTimeout(timeout int32) bool {
 	return timeout != 0 && timeout >= 30 && timeout <= 7200
 }
 
-func (a *awsLifecycleHookValidation) isValidDefaultResult(defaultResult string) bool {
+func (a *LifecycleHookValidation) isValidDefaultResult(defaultResult string) bool {
 	for _, item := range validLifecycleHookResults {
 		if item == defaultResult {
 			return true
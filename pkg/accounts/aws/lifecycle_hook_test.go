@@ -1,11 +1,12 @@
-package validate
+package aws
 
 import (
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"reflect"
 	"testing"
 )
 
-func Test_awsLifecycleHookValidation_isValidDefaultResult(t *testing.T) {
+func Test_LifecycleHookValidation_isValidDefaultResult(t *testing.T) {
 	type args struct {
 		defaultResult string
 	}
@@ -38,7 +39,7 @@ func Test_awsLifecycleHookValidation_isValidDefaultResult(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := &awsLifecycleHookValidation{}
+			a := &LifecycleHookValidation{}
 			if got := a.isValidDefaultResult(tt.args.defaultResult); got != tt.want {
 				t.Errorf("isValidDefaultResult() = %v, want %v", got, tt.want)
 			}
@@ -46,7 +47,7 @@ func Test_awsLifecycleHookValidation_isValidDefaultResult(t *testing.T) {
 	}
 }
 
-func Test_awsLifecycleHookValidation_isValidHeartbeatTimeout(t *testing.T) {
+func Test_LifecycleHookValidation_isValidHeartbeatTimeout(t *testing.T) {
 	type args struct {
 		timeout int32
 	}
@@ -86,7 +87,7 @@ func Test_awsLifecycleHookValidation_isValidHeartbeatTimeout(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := &awsLifecycleHookValidation{}
+			a := &LifecycleHookValidation{}
 			if got := a.isValidHeartbeatTimeout(tt.args.timeout); got != tt.want {
 				t.Errorf("isValidHeartbeatTimeout() = %v, want %v", got, tt.want)
 			}
@@ -94,7 +95,7 @@ func Test_awsLifecycleHookValidation_isValidHeartbeatTimeout(t *testing.T) {
 	}
 }
 
-func Test_awsLifecycleHookValidation_isValidRoleArn(t *testing.T) {
+func Test_LifecycleHookValidation_isValidRoleArn(t *testing.T) {
 	type args struct {
 		arn string
 	}
@@ -120,7 +121,7 @@ func Test_awsLifecycleHookValidation_isValidRoleArn(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := &awsLifecycleHookValidation{}
+			a := &LifecycleHookValidation{}
 			if got := a.isValidRoleArn(tt.args.arn); got != tt.want {
 				t.Errorf("isValidRoleArn() = %v, want %v", got, tt.want)
 			}
@@ -128,7 +129,7 @@ func Test_awsLifecycleHookValidation_isValidRoleArn(t *testing.T) {
 	}
 }
 
-func Test_awsLifecycleHookValidation_isValidSnsArn(t *testing.T) {
+func Test_LifecycleHookValidation_isValidSnsArn(t *testing.T) {
 
 	type args struct {
 		arn string
@@ -155,7 +156,7 @@ func Test_awsLifecycleHookValidation_isValidSnsArn(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := &awsLifecycleHookValidation{}
+			a := &LifecycleHookValidation{}
 			if got := a.isValidSnsArn(tt.args.arn); got != tt.want {
 				t.Errorf("isValidSnsArn() = %v, want %v", got, tt.want)
 			}
@@ -163,8 +164,8 @@ func Test_awsLifecycleHookValidation_isValidSnsArn(t *testing.T) {
 	}
 }
 
-func Test_awsLifecycleHookValidation_validate(t *testing.T) {
-	awsHook := AwsLifecycleHook{
+func Test_LifecycleHookValidation_Validate(t *testing.T) {
+	awsHook := interfaces.AWSLifecycleHook{
 		DefaultResult:         "CONTINUE",
 		HeartbeatTimeout:      120,
 		LifecycleTransition:   "autoscaling:EC2_INSTANCE_TERMINATING",
@@ -172,7 +173,7 @@ func Test_awsLifecycleHookValidation_validate(t *testing.T) {
 		RoleARN:               "arn:aws:iam::11111111:role/test-aws-operator-validation-topic-role",
 	}
 	type args struct {
-		hook AwsLifecycleHook
+		hook interfaces.AWSLifecycleHook
 	}
 	tests := []struct {
 		name string
@@ -186,9 +187,9 @@ func Test_awsLifecycleHookValidation_validate(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := &awsLifecycleHookValidation{}
-			if got := a.validate(tt.args.hook); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("validate() = %v, want %v", got, tt.want)
+			a := &LifecycleHookValidation{}
+			if got := a.Validate(tt.args.hook); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,103 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/mitchellh/mapstructure"
+)
+
+func (k *AccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := k.newAccount()
+	a.Name = account.GetName()
+	a.Settings = account.GetSpec().Settings
+	if account.GetSpec().AWS == nil {
+		return nil, noAWSDefinedError
+	}
+	account.GetSpec().AWS.DeepCopyInto(&a.AWS)
+	return a, nil
+}
+
+func (k *AccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	a := k.newAccount()
+	n, ok := settings["name"]
+	if !ok {
+		return nil, fmt.Errorf("%s account missing name", a.GetType())
+	}
+	if name, ok := n.(string); ok {
+		a.Name = name
+	} else {
+		return nil, fmt.Errorf("name is not a string")
+	}
+	// Halyard accepts unquoted values (e.g. an accountId read as a number)
+	d, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{WeaklyTypedInput: true, Result: &a.AWS})
+	if err != nil {
+		return nil, err
+	}
+	if err := d.Decode(settings); err != nil {
+		return nil, fmt.Errorf("Error reading aws settings for account \"%s\":\n  %w", a.Name, err)
+	}
+	if l, ok := settings[LambdaEnabledSettings]; ok {
+		enabled, lok := l.(bool)
+		if !lok {
+			return nil, fmt.Errorf("%s is not a boolean: %v", LambdaEnabledSettings, l)
+		}
+		a.AWS.Lambda = &interfaces.AWSLambda{Enabled: enabled}
+	}
+	a.Settings = settings
+	return a, nil
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
+func (k *Account) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	if k.AWS.AccountId == "" {
+		return nil, noAccountIdError
+	}
+	m[AccountIdSettings] = k.AWS.AccountId
+	if k.AWS.AssumeRole != "" {
+		m[AssumeRoleSettings] = k.AWS.AssumeRole
+	}
+	if k.AWS.ExternalId != "" {
+		m[ExternalIdSettings] = k.AWS.ExternalId
+	}
+	if len(k.AWS.Regions) > 0 {
+		regions := make([]interface{}, 0)
+		for _, r := range k.AWS.Regions {
+			regions = append(regions, map[string]interface{}{"name": r.Name})
+		}
+		m[RegionsSettings] = regions
+	}
+	if len(k.AWS.LifecycleHooks) > 0 {
+		hooks := make([]interface{}, 0)
+		for _, h := range k.AWS.LifecycleHooks {
+			hooks = append(hooks, lifecycleHookToSpinnakerSettings(h))
+		}
+		m[LifecycleHooksSettings] = hooks
+	}
+	if k.AWS.Lambda != nil {
+		m[LambdaEnabledSettings] = k.AWS.Lambda.Enabled
+	}
+	return m, nil
+}
+
+func lifecycleHookToSpinnakerSettings(h interfaces.AWSLifecycleHook) map[string]interface{} {
+	m := make(map[string]interface{})
+	if h.DefaultResult != "" {
+		m["defaultResult"] = h.DefaultResult
+	}
+	if h.HeartbeatTimeout != 0 {
+		m["heartbeatTimeout"] = h.HeartbeatTimeout
+	}
+	if h.LifecycleTransition != "" {
+		m["lifecycleTransition"] = h.LifecycleTransition
+	}
+	if h.NotificationTargetARN != "" {
+		m["notificationTargetARN"] = h.NotificationTargetARN
+	}
+	if h.RoleARN != "" {
+		m["roleARN"] = h.RoleARN
+	}
+	return m
+}
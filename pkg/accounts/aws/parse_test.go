@@ -0,0 +1,184 @@
+package aws
+
+import (
+	"context"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+	"testing"
+)
+
+func TestFromCRD(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expected func(t *testing.T, a account.Account, err error)
+	}{
+		{
+			name: "no aws section in CRD",
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: AWS
+`,
+			expected: func(t *testing.T, _ account.Account, err error) {
+				assert.Equal(t, noAWSDefinedError, err)
+			},
+		},
+		{
+			name: "full aws config",
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: AWS
+  aws:
+    accountId: "123456789012"
+    assumeRole: role/spinnakerManaged
+    externalId: ext
+    regions:
+    - name: us-west-2
+    - name: us-east-1
+    lifecycleHooks:
+    - defaultResult: CONTINUE
+      heartbeatTimeout: 120
+      lifecycleTransition: autoscaling:EC2_INSTANCE_TERMINATING
+      notificationTargetARN: arn:aws:sns:us-west-2:123456789012:topic
+      roleARN: arn:aws:iam::123456789012:role/hook-role
+    lambda:
+      enabled: true
+  settings:
+    defaultKeyPair: mykeypair
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if !assert.Nil(t, err) {
+					return
+				}
+				aws := a.(*Account)
+				assert.Equal(t, "account1", aws.GetName())
+				assert.Equal(t, "123456789012", aws.AWS.AccountId)
+				assert.Equal(t, 2, len(aws.AWS.Regions))
+				assert.Equal(t, int32(120), aws.AWS.LifecycleHooks[0].HeartbeatTimeout)
+				assert.True(t, aws.AWS.Lambda.Enabled)
+				assert.Equal(t, "mykeypair", aws.Settings["defaultKeyPair"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sa := test.TypesFactory.NewAccount()
+			if !assert.Nil(t, yaml.Unmarshal([]byte(tt.manifest), sa)) {
+				return
+			}
+			k := &AccountType{}
+			a, err := k.FromCRD(sa)
+			tt.expected(t, a, err)
+		})
+	}
+}
+
+func TestFromSpinnakerSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		expected func(t *testing.T, a account.Account, err error)
+	}{
+		{
+			name: "full settings",
+			settings: map[string]interface{}{
+				"name":          "test",
+				"accountId":     "123456789012",
+				"assumeRole":    "role/spinnakerManaged",
+				"lambdaEnabled": true,
+				"regions": []interface{}{
+					map[string]interface{}{"name": "us-west-2"},
+				},
+				"lifecycleHooks": []interface{}{
+					map[string]interface{}{
+						"defaultResult":    "CONTINUE",
+						"heartbeatTimeout": float64(120),
+					},
+				},
+			},
+			expected: func(t *testing.T, a account.Account, err error) {
+				if !assert.Nil(t, err) {
+					return
+				}
+				aws := a.(*Account)
+				assert.Equal(t, "123456789012", aws.AWS.AccountId)
+				assert.Equal(t, "role/spinnakerManaged", aws.AWS.AssumeRole)
+				assert.Equal(t, "us-west-2", aws.AWS.Regions[0].Name)
+				assert.Equal(t, int32(120), aws.AWS.LifecycleHooks[0].HeartbeatTimeout)
+				assert.True(t, aws.AWS.Lambda.Enabled)
+			},
+		},
+		{
+			name: "lambdaEnabled must be a boolean",
+			settings: map[string]interface{}{
+				"name":          "test",
+				"accountId":     "123456789012",
+				"lambdaEnabled": "yes",
+			},
+			expected: func(t *testing.T, _ account.Account, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			name: "but names are still required",
+			settings: map[string]interface{}{
+				"accountId": "123456789012",
+			},
+			expected: func(t *testing.T, _ account.Account, err error) {
+				if assert.NotNil(t, err) {
+					assert.Equal(t, "AWS account missing name", err.Error())
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := &AccountType{}
+			a, err := k.FromSpinnakerConfig(context.TODO(), tt.settings)
+			tt.expected(t, a, err)
+		})
+	}
+}
+
+func TestToSpinnakerSettings(t *testing.T) {
+	a := &Account{
+		Name: "aws-1",
+		AWS: interfaces.AWSAccount{
+			AccountId:  "123456789012",
+			AssumeRole: "role/spinnakerManaged",
+			Regions:    []interfaces.AWSRegion{{Name: "us-west-2"}},
+			LifecycleHooks: []interfaces.AWSLifecycleHook{
+				{DefaultResult: "CONTINUE", HeartbeatTimeout: 120},
+			},
+			Lambda: &interfaces.AWSLambda{Enabled: true},
+		},
+		Settings: interfaces.FreeForm{
+			"defaultKeyPair": "mykeypair",
+		},
+	}
+	ss, err := a.ToSpinnakerSettings(context.TODO())
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "aws-1", ss["name"])
+	assert.Equal(t, "123456789012", ss[AccountIdSettings])
+	assert.Equal(t, "role/spinnakerManaged", ss[AssumeRoleSettings])
+	assert.Nil(t, ss[ExternalIdSettings])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "us-west-2"}}, ss[RegionsSettings])
+	assert.Equal(t, []interface{}{map[string]interface{}{"defaultResult": "CONTINUE", "heartbeatTimeout": int32(120)}}, ss[LifecycleHooksSettings])
+	assert.Equal(t, true, ss[LambdaEnabledSettings])
+	assert.Equal(t, "mykeypair", ss["defaultKeyPair"])
+}
@@ -0,0 +1,27 @@
+package aws
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// stsClient returns the id of the AWS account the given configuration authenticates to
+type stsClient interface {
+	GetCallerAccountId(ctx context.Context, cfg *aws.Config) (string, error)
+}
+
+type stsClientImpl struct{}
+
+func (s *stsClientImpl) GetCallerAccountId(ctx context.Context, cfg *aws.Config) (string, error) {
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return "", err
+	}
+	out, err := sts.New(sess).GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.Account), nil
+}
@@ -0,0 +1,123 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	accessKeyIdKey     = "providers.aws.accessKeyId"
+	secretAccessKeyKey = "providers.aws.secretAccessKey"
+	defaultRegionsKey  = "providers.aws.defaultRegions"
+	// Halyard's default region
+	defaultRegion = "us-west-2"
+)
+
+var accountIdPattern = regexp.MustCompile(`^\d{12}$`)
+
+type awsAccountValidator struct {
+	account *Account
+	client  stsClient
+}
+
+func (a *awsAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	if err := a.validateSettings(); err != nil {
+		return err
+	}
+	cfg, err := a.makeConfig(ctx, spinSvc)
+	if err != nil {
+		return err
+	}
+	return a.validateAccess(ctx, cfg)
+}
+
+func (a *awsAccountValidator) validateSettings() error {
+	if a.account.AWS.AccountId == "" {
+		return noAccountIdError
+	}
+	if !accountIdPattern.MatchString(a.account.AWS.AccountId) {
+		return fmt.Errorf("invalid aws accountId \"%s\", expected 12 digits", a.account.AWS.AccountId)
+	}
+	return a.validateLifecycleHooks()
+}
+
+// validateLifecycleHooks is the only check failing accounts of Spinnaker settings, other errors are reported
+func (a *awsAccountValidator) validateLifecycleHooks() error {
+	v := &LifecycleHookValidation{}
+	msgs := make([]string, 0)
+	for _, h := range a.account.AWS.LifecycleHooks {
+		for _, e := range v.Validate(h) {
+			msgs = append(msgs, e.Error())
+		}
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("invalid lifecycle hooks in account \"%s\":\n  %s", a.account.Name, strings.Join(msgs, "\n  "))
+	}
+	return nil
+}
+
+// makeConfig builds the AWS configuration clouddriver would use: the provider's access keys if any
+// (or the default credential chain), assuming the account's role if one is set.
+func (a *awsAccountValidator) makeConfig(ctx context.Context, spinSvc interfaces.SpinnakerService) (*aws.Config, error) {
+	cfg := aws.NewConfig().WithRegion(a.getRegion(ctx, spinSvc))
+	if spinSvc != nil {
+		// ignore errors, keys are optional
+		accessKey, _ := spinSvc.GetSpinnakerConfig().GetHalConfigPropString(ctx, accessKeyIdKey)
+		secretKey, _ := spinSvc.GetSpinnakerConfig().GetHalConfigPropString(ctx, secretAccessKeyKey)
+		if accessKey != "" && secretKey != "" {
+			cfg = cfg.WithCredentials(credentials.NewStaticCredentials(accessKey, secretKey, ""))
+		}
+	}
+	if a.account.AWS.AssumeRole == "" {
+		return cfg, nil
+	}
+
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to make aws session for account \"%s\":\n  %w", a.account.Name, err)
+	}
+	roleArn := fmt.Sprintf("arn:aws:iam::%s:%s", a.account.AWS.AccountId, a.account.AWS.AssumeRole)
+	creds := stscreds.NewCredentials(sess, roleArn, func(p *stscreds.AssumeRoleProvider) {
+		if a.account.AWS.ExternalId != "" {
+			p.ExternalID = aws.String(a.account.AWS.ExternalId)
+		}
+	})
+	return aws.NewConfig().WithRegion(aws.StringValue(cfg.Region)).WithCredentials(creds), nil
+}
+
+// getRegion returns the first region of the account, or the first default region of the provider
+func (a *awsAccountValidator) getRegion(ctx context.Context, spinSvc interfaces.SpinnakerService) string {
+	if len(a.account.AWS.Regions) > 0 && a.account.AWS.Regions[0].Name != "" {
+		return a.account.AWS.Regions[0].Name
+	}
+	if spinSvc != nil {
+		regions, err := spinSvc.GetSpinnakerConfig().GetHalConfigObjectArray(ctx, defaultRegionsKey)
+		if err == nil && len(regions) > 0 {
+			if n, ok := regions[0]["name"].(string); ok && n != "" {
+				return n
+			}
+		}
+	}
+	return defaultRegion
+}
+
+func (a *awsAccountValidator) validateAccess(ctx context.Context, cfg *aws.Config) error {
+	id, err := a.client.GetCallerAccountId(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("error getting caller identity in account \"%s\":\n  %w", a.account.Name, err)
+	}
+	if id != a.account.AWS.AccountId {
+		return fmt.Errorf("credentials of account \"%s\" resolve to aws account %s, expected %s", a.account.Name, id, a.account.AWS.AccountId)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	logr "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type fakeStsClient struct {
+	accountId string
+	err       error
+	cfg       *aws.Config
+}
+
+func (f *fakeStsClient) GetCallerAccountId(ctx context.Context, cfg *aws.Config) (string, error) {
+	f.cfg = cfg
+	return f.accountId, f.err
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		account  interfaces.AWSAccount
+		client   *fakeStsClient
+		expected func(t *testing.T, c *fakeStsClient, err error)
+	}{
+		{
+			name:    "valid account",
+			account: interfaces.AWSAccount{AccountId: "123456789012", Regions: []interfaces.AWSRegion{{Name: "us-east-1"}}},
+			client:  &fakeStsClient{accountId: "123456789012"},
+			expected: func(t *testing.T, c *fakeStsClient, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "us-east-1", aws.StringValue(c.cfg.Region))
+			},
+		},
+		{
+			name:    "credentials resolve to another account",
+			account: interfaces.AWSAccount{AccountId: "123456789012", AssumeRole: "role/spinnakerManaged"},
+			client:  &fakeStsClient{accountId: "999999999999"},
+			expected: func(t *testing.T, c *fakeStsClient, err error) {
+				assert.NotNil(t, err)
+				assert.NotNil(t, c.cfg.Credentials)
+				assert.Equal(t, defaultRegion, aws.StringValue(c.cfg.Region))
+			},
+		},
+		{
+			name:    "sts error",
+			account: interfaces.AWSAccount{AccountId: "123456789012"},
+			client:  &fakeStsClient{err: errors.New("AccessDenied")},
+			expected: func(t *testing.T, c *fakeStsClient, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			name:    "invalid account id",
+			account: interfaces.AWSAccount{AccountId: "1234"},
+			client:  &fakeStsClient{},
+			expected: func(t *testing.T, c *fakeStsClient, err error) {
+				assert.NotNil(t, err)
+				assert.Nil(t, c.cfg)
+			},
+		},
+		{
+			name: "invalid lifecycle hook",
+			account: interfaces.AWSAccount{
+				AccountId:      "123456789012",
+				LifecycleHooks: []interfaces.AWSLifecycleHook{{DefaultResult: "OTHER"}},
+			},
+			client: &fakeStsClient{},
+			expected: func(t *testing.T, c *fakeStsClient, err error) {
+				assert.NotNil(t, err)
+				assert.Nil(t, c.cfg)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &awsAccountValidator{
+				account: &Account{Name: "test", AWS: tt.account},
+				client:  tt.client,
+			}
+			err := v.Validate(test.TypesFactory.NewService(), nil, context.TODO(), logr.Log)
+			tt.expected(t, tt.client, err)
+		})
+	}
+}
+
+func TestGetRegionFromDefaultRegions(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(`
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: test
+spec:
+  spinnakerConfig:
+    config:
+      providers:
+        aws:
+          defaultRegions:
+          - name: eu-west-1
+`, t)
+	v := &awsAccountValidator{account: &Account{Name: "test"}}
+	assert.Equal(t, "eu-west-1", v.getRegion(context.TODO(), spinsvc))
+}
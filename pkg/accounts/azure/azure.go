@@ -0,0 +1,89 @@
+package azure
+
+import (
+	"errors"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"strings"
+)
+
+// Azure accounts are read from the `azure` section of the SpinnakerAccount or from Spinnaker settings.
+// They're rendered in both Clouddriver's and Rosco's `azure.accounts`: Rosco bakes images with
+// the account's credentials and packer settings (`packerResourceGroup`, `packerStorageAccount`).
+const (
+	ClientIdSettings             = "clientId"
+	AppKeySettings               = "appKey"
+	TenantIdSettings             = "tenantId"
+	SubscriptionIdSettings       = "subscriptionId"
+	ObjectIdSettings             = "objectId"
+	DefaultResourceGroupSettings = "defaultResourceGroup"
+	DefaultKeyVaultSettings      = "defaultKeyVault"
+	RegionsSettings              = "regions"
+	PackerResourceGroupSettings  = "packerResourceGroup"
+	PackerStorageAccountSettings = "packerStorageAccount"
+)
+
+var (
+	noAzureDefinedError = errors.New("azure needs to be defined")
+)
+
+type AccountType struct{}
+
+func (k *AccountType) GetType() interfaces.AccountType {
+	return interfaces.AzureAccountType
+}
+
+func (k *AccountType) GetAccountsKey() string {
+	return "azure.accounts"
+}
+
+func (k *AccountType) GetConfigAccountsKey() string {
+	return "providers.azure.accounts"
+}
+
+func (k *AccountType) GetServices() []string {
+	return []string{"clouddriver", "rosco"}
+}
+
+func (k *AccountType) GetPrimaryAccountsKey() string {
+	return "providers.azure.primaryAccount"
+}
+
+func (k *AccountType) newAccount() *Account {
+	return &Account{
+		Azure: interfaces.AzureAccount{},
+	}
+}
+
+func (k *AccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	v := spinsvc.GetSpinnakerValidation()
+	for n, s := range v.Providers {
+		if strings.ToLower(n) == strings.ToLower(string(interfaces.AzureAccountType)) {
+			return &s
+		}
+	}
+	return v.GetValidationSettings()
+}
+
+type Account struct {
+	*account.BaseAccount
+	Name     string                  `json:"name,omitempty"`
+	Azure    interfaces.AzureAccount `json:"azure,omitempty"`
+	Settings interfaces.FreeForm     `json:"settings,omitempty"`
+}
+
+func (k *Account) GetType() interfaces.AccountType {
+	return interfaces.AzureAccountType
+}
+
+func (k *Account) GetName() string {
+	return k.Name
+}
+
+func (k *Account) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *Account) NewValidator() account.AccountValidator {
+	return &azureAccountValidator{account: k, client: &tokenClientImpl{}}
+}
@@ -0,0 +1,97 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/mitchellh/mapstructure"
+)
+
+func (k *AccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := k.newAccount()
+	a.Name = account.GetName()
+	a.Settings = account.GetSpec().Settings
+	if account.GetSpec().Azure == nil {
+		return nil, noAzureDefinedError
+	}
+	account.GetSpec().Azure.DeepCopyInto(&a.Azure)
+	return a, nil
+}
+
+func (k *AccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	a := k.newAccount()
+	n, ok := settings["name"]
+	if !ok {
+		return nil, fmt.Errorf("%s account missing name", a.GetType())
+	}
+	if name, ok := n.(string); ok {
+		a.Name = name
+	} else {
+		return nil, fmt.Errorf("name is not a string")
+	}
+	if err := mapstructure.Decode(settings, &a.Azure); err != nil {
+		return nil, fmt.Errorf("Error reading azure settings for account \"%s\":\n  %w", a.Name, err)
+	}
+	p := &interfaces.AzurePacker{}
+	if err := mapstructure.Decode(map[string]interface{}{
+		"resourceGroup":  settings[PackerResourceGroupSettings],
+		"storageAccount": settings[PackerStorageAccountSettings],
+	}, p); err != nil {
+		return nil, fmt.Errorf("Error reading packer settings for account \"%s\":\n  %w", a.Name, err)
+	}
+	if *p != (interfaces.AzurePacker{}) {
+		a.Azure.Packer = p
+	}
+	a.Settings = settings
+	return a, nil
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
+func (k *Account) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	z := k.Azure
+	m[ClientIdSettings] = z.ClientId
+	m[TenantIdSettings] = z.TenantId
+	m[SubscriptionIdSettings] = z.SubscriptionId
+	m[DefaultResourceGroupSettings] = z.DefaultResourceGroup
+	m[DefaultKeyVaultSettings] = z.DefaultKeyVault
+	account.SetSecretReference(m, z.AppKeySecret, AppKeySettings)
+	if z.ObjectId != "" {
+		m[ObjectIdSettings] = z.ObjectId
+	}
+	if len(z.Regions) > 0 {
+		m[RegionsSettings] = z.Regions
+	}
+	if z.Packer != nil {
+		if z.Packer.ResourceGroup != "" {
+			m[PackerResourceGroupSettings] = z.Packer.ResourceGroup
+		}
+		if z.Packer.StorageAccount != "" {
+			m[PackerStorageAccountSettings] = z.Packer.StorageAccount
+		}
+	}
+	return m, nil
+}
+
+// getAppKey returns the service principal's app key.
+// App keys passed in settings are decoded if they're secret references.
+func (k *Account) getAppKey(ctx context.Context) (string, error) {
+	if k.Azure.AppKeySecret != nil {
+		return k.getSecretAppKey(ctx)
+	}
+	s, ok := k.Settings[AppKeySettings].(string)
+	if !ok || s == "" {
+		return "", nil
+	}
+	appKey, err := account.DecodeSecret(ctx, s)
+	if err != nil {
+		return "", fmt.Errorf("Error decoding appKey of azure account \"%s\":\n  %w", k.Name, err)
+	}
+	return appKey, nil
+}
+
+// getSecretAppKey reads the app key from the Kubernetes secret referenced by the account
+func (k *Account) getSecretAppKey(ctx context.Context) (string, error) {
+	return account.ReadSecret(ctx, k.Azure.AppKeySecret)
+}
@@ -0,0 +1,138 @@
+package azure
+
+import (
+	"context"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+	"testing"
+)
+
+func TestFromCRD(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expected func(t *testing.T, a account.Account, err error)
+	}{
+		{
+			name: "no azure section in CRD",
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: Azure
+`,
+			expected: func(t *testing.T, _ account.Account, err error) {
+				assert.Equal(t, noAzureDefinedError, err)
+			},
+		},
+		{
+			name: "full azure config",
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: Azure
+  azure:
+    clientId: 11111111-1111-1111-1111-111111111111
+    appKeySecret:
+      name: azure-secret
+      key: appKey
+    tenantId: 22222222-2222-2222-2222-222222222222
+    subscriptionId: 33333333-3333-3333-3333-333333333333
+    defaultResourceGroup: spinnaker
+    defaultKeyVault: spinnaker-vault
+    regions:
+    - westus
+    packer:
+      resourceGroup: packer
+      storageAccount: packerstorage
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if !assert.Nil(t, err) {
+					return
+				}
+				z := a.(*Account)
+				assert.Equal(t, "11111111-1111-1111-1111-111111111111", z.Azure.ClientId)
+				assert.Equal(t, "azure-secret", z.Azure.AppKeySecret.Name)
+				assert.Equal(t, "spinnaker-vault", z.Azure.DefaultKeyVault)
+				assert.Equal(t, []string{"westus"}, z.Azure.Regions)
+				assert.Equal(t, "packerstorage", z.Azure.Packer.StorageAccount)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sa := test.TypesFactory.NewAccount()
+			if !assert.Nil(t, yaml.Unmarshal([]byte(tt.manifest), sa)) {
+				return
+			}
+			k := &AccountType{}
+			a, err := k.FromCRD(sa)
+			tt.expected(t, a, err)
+		})
+	}
+}
+
+func TestFromSpinnakerSettings(t *testing.T) {
+	k := &AccountType{}
+	a, err := k.FromSpinnakerConfig(context.TODO(), map[string]interface{}{
+		"name":                 "azure",
+		"clientId":             "11111111-1111-1111-1111-111111111111",
+		"appKey":               "encrypted:s3!b:bucket!f:secrets.yml!k:azure.appKey",
+		"tenantId":             "22222222-2222-2222-2222-222222222222",
+		"subscriptionId":       "33333333-3333-3333-3333-333333333333",
+		"defaultResourceGroup": "spinnaker",
+		"defaultKeyVault":      "spinnaker-vault",
+		"regions":              []interface{}{"westus", "eastus"},
+		"packerResourceGroup":  "packer",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	z := a.(*Account)
+	assert.Equal(t, "22222222-2222-2222-2222-222222222222", z.Azure.TenantId)
+	assert.Equal(t, []string{"westus", "eastus"}, z.Azure.Regions)
+	if assert.NotNil(t, z.Azure.Packer) {
+		assert.Equal(t, "packer", z.Azure.Packer.ResourceGroup)
+		assert.Equal(t, "", z.Azure.Packer.StorageAccount)
+	}
+
+	ss, err := z.ToSpinnakerSettings(context.TODO())
+	if assert.Nil(t, err) {
+		assert.Equal(t, "encrypted:s3!b:bucket!f:secrets.yml!k:azure.appKey", ss[AppKeySettings])
+		assert.Equal(t, "packer", ss[PackerResourceGroupSettings])
+		assert.Nil(t, ss[PackerStorageAccountSettings])
+	}
+}
+
+func TestToSpinnakerSettings(t *testing.T) {
+	a := &Account{
+		Name: "azure",
+		Azure: interfaces.AzureAccount{
+			ClientId:             "11111111-1111-1111-1111-111111111111",
+			TenantId:             "22222222-2222-2222-2222-222222222222",
+			SubscriptionId:       "33333333-3333-3333-3333-333333333333",
+			DefaultResourceGroup: "spinnaker",
+			DefaultKeyVault:      "spinnaker-vault",
+			Packer:               &interfaces.AzurePacker{StorageAccount: "packerstorage"},
+		},
+	}
+	ss, err := a.ToSpinnakerSettings(context.TODO())
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "azure", ss["name"])
+	assert.Equal(t, "11111111-1111-1111-1111-111111111111", ss[ClientIdSettings])
+	assert.Equal(t, "spinnaker", ss[DefaultResourceGroupSettings])
+	assert.Equal(t, "packerstorage", ss[PackerStorageAccountSettings])
+	assert.Nil(t, ss[AppKeySettings])
+	assert.Nil(t, ss[RegionsSettings])
+}
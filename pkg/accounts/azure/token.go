@@ -0,0 +1,47 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	loginBaseUrl    = "https://login.microsoftonline.com"
+	managementScope = "https://management.azure.com/.default"
+)
+
+// tokenClient requests an access token for a service principal
+type tokenClient interface {
+	RequestToken(ctx context.Context, tenantId, clientId, appKey string) error
+}
+
+type tokenClientImpl struct{}
+
+func (t *tokenClientImpl) RequestToken(ctx context.Context, tenantId, clientId, appKey string) error {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {clientId},
+		"client_secret": {appKey},
+		"scope":         {managementScope},
+	}
+	u := fmt.Sprintf("%s/%s/oauth2/v2.0/token", loginBaseUrl, url.PathEscape(tenantId))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("token request returned %d: %s", resp.StatusCode, string(b))
+	}
+	return nil
+}
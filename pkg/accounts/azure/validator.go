@@ -0,0 +1,71 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var guidPattern = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+
+type azureAccountValidator struct {
+	account *Account
+	// client requests a token with the account's credentials, the check is skipped if nil
+	client tokenClient
+}
+
+func (z *azureAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	if err := z.validateFormat(); err != nil {
+		return err
+	}
+	appKey, err := z.account.getAppKey(ctx)
+	if err != nil {
+		return err
+	}
+	if appKey == "" {
+		return fmt.Errorf("azure account \"%s\" is missing appKey", z.account.Name)
+	}
+	if z.client == nil {
+		return nil
+	}
+	a := z.account.Azure
+	if err := z.client.RequestToken(ctx, a.TenantId, a.ClientId, appKey); err != nil {
+		return fmt.Errorf("unable to get a token for azure account \"%s\":\n  %w", z.account.Name, err)
+	}
+	return nil
+}
+
+// validateFormat checks required settings and the format of Azure identifiers
+func (z *azureAccountValidator) validateFormat() error {
+	a := z.account.Azure
+	ids := []struct {
+		name  string
+		value string
+	}{
+		{ClientIdSettings, a.ClientId},
+		{TenantIdSettings, a.TenantId},
+		{SubscriptionIdSettings, a.SubscriptionId},
+	}
+	for _, id := range ids {
+		if id.value == "" {
+			return fmt.Errorf("azure account \"%s\" is missing %s", z.account.Name, id.name)
+		}
+		if !guidPattern.MatchString(id.value) {
+			return fmt.Errorf("%s \"%s\" of azure account \"%s\" is not a valid GUID", id.name, id.value, z.account.Name)
+		}
+	}
+	if a.ObjectId != "" && !guidPattern.MatchString(a.ObjectId) {
+		return fmt.Errorf("%s \"%s\" of azure account \"%s\" is not a valid GUID", ObjectIdSettings, a.ObjectId, z.account.Name)
+	}
+	if a.DefaultResourceGroup == "" {
+		return fmt.Errorf("azure account \"%s\" is missing %s", z.account.Name, DefaultResourceGroupSettings)
+	}
+	if a.DefaultKeyVault == "" {
+		return fmt.Errorf("azure account \"%s\" is missing %s", z.account.Name, DefaultKeyVaultSettings)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	logr "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type fakeTokenClient struct {
+	err      error
+	tenantId string
+	clientId string
+	appKey   string
+}
+
+func (f *fakeTokenClient) RequestToken(ctx context.Context, tenantId, clientId, appKey string) error {
+	f.tenantId = tenantId
+	f.clientId = clientId
+	f.appKey = appKey
+	return f.err
+}
+
+func validAccount() interfaces.AzureAccount {
+	return interfaces.AzureAccount{
+		ClientId:             "11111111-1111-1111-1111-111111111111",
+		TenantId:             "22222222-2222-2222-2222-222222222222",
+		SubscriptionId:       "33333333-3333-3333-3333-333333333333",
+		DefaultResourceGroup: "spinnaker",
+		DefaultKeyVault:      "spinnaker-vault",
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		azure    func(a *interfaces.AzureAccount)
+		settings interfaces.FreeForm
+		client   *fakeTokenClient
+		expected func(t *testing.T, c *fakeTokenClient, err error)
+	}{
+		{
+			name:     "valid credentials",
+			settings: interfaces.FreeForm{AppKeySettings: "my-key"},
+			client:   &fakeTokenClient{},
+			expected: func(t *testing.T, c *fakeTokenClient, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "22222222-2222-2222-2222-222222222222", c.tenantId)
+				assert.Equal(t, "11111111-1111-1111-1111-111111111111", c.clientId)
+				assert.Equal(t, "my-key", c.appKey)
+			},
+		},
+		{
+			name:     "token request failure",
+			settings: interfaces.FreeForm{AppKeySettings: "my-key"},
+			client:   &fakeTokenClient{err: errors.New("AADSTS7000215: Invalid client secret")},
+			expected: func(t *testing.T, c *fakeTokenClient, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "AADSTS7000215")
+				}
+			},
+		},
+		{
+			name:     "clientId not a GUID",
+			azure:    func(a *interfaces.AzureAccount) { a.ClientId = "my-client" },
+			settings: interfaces.FreeForm{AppKeySettings: "my-key"},
+			client:   &fakeTokenClient{},
+			expected: func(t *testing.T, c *fakeTokenClient, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "clientId")
+				}
+				assert.Equal(t, "", c.appKey)
+			},
+		},
+		{
+			name:     "missing tenantId",
+			azure:    func(a *interfaces.AzureAccount) { a.TenantId = "" },
+			settings: interfaces.FreeForm{AppKeySettings: "my-key"},
+			client:   &fakeTokenClient{},
+			expected: func(t *testing.T, c *fakeTokenClient, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			name:     "missing key vault",
+			azure:    func(a *interfaces.AzureAccount) { a.DefaultKeyVault = "" },
+			settings: interfaces.FreeForm{AppKeySettings: "my-key"},
+			client:   &fakeTokenClient{},
+			expected: func(t *testing.T, c *fakeTokenClient, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			name:     "missing appKey",
+			settings: interfaces.FreeForm{},
+			client:   &fakeTokenClient{},
+			expected: func(t *testing.T, c *fakeTokenClient, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "appKey")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+			defer secrets.Cleanup(ctx)
+
+			a := validAccount()
+			if tt.azure != nil {
+				tt.azure(&a)
+			}
+			v := &azureAccountValidator{
+				account: &Account{Name: "azure", Azure: a, Settings: tt.settings},
+				client:  tt.client,
+			}
+			tt.expected(t, tt.client, v.Validate(nil, nil, ctx, logr.Log))
+		})
+	}
+}
+
+func TestValidateWithoutTokenClient(t *testing.T) {
+	ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+	defer secrets.Cleanup(ctx)
+	v := &azureAccountValidator{
+		account: &Account{Name: "azure", Azure: validAccount(), Settings: interfaces.FreeForm{AppKeySettings: "my-key"}},
+	}
+	assert.Nil(t, v.Validate(nil, nil, ctx, logr.Log))
+}
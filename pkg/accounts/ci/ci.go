@@ -0,0 +1,82 @@
+package ci
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+)
+
+// CI accounts are read from their section of the SpinnakerAccount (e.g. `jenkins`) or from Spinnaker settings under
+// `ci.<kind>.masters`. They're rendered in Igor's `<kind>.masters` along with the flag enabling the integration,
+// Echo only gets `igor.enabled` so that pipelines can be triggered by builds.
+const (
+	AddressSettings      = "address"
+	UsernameSettings     = "username"
+	PasswordSettings     = "password"
+	CsrfSettings         = "csrf"
+	OrganizationSettings = "organization"
+	BaseUrlSettings      = "baseUrl"
+	TokenSettings        = "token"
+	PrivateTokenSettings = "privateToken"
+
+	igorService = "igor"
+	echoService = "echo"
+)
+
+var (
+	noJenkinsDefinedError       = errors.New("jenkins needs to be defined")
+	noGitHubActionsDefinedError = errors.New("githubActions needs to be defined")
+	noGitLabCIDefinedError      = errors.New("gitlabCI needs to be defined")
+	noAddressError              = errors.New("address is required")
+	noOrganizationError         = errors.New("githubActions organization is required")
+)
+
+// ciKind holds what's common to the types of a kind of CI account
+type ciKind struct {
+	accountType interfaces.AccountType
+	// name of the kind in Igor's settings (e.g. jenkins in jenkins.masters)
+	igorName string
+	// name of the kind in Spinnaker's config and in spec.validation.ci (e.g. gitlabCi in ci.gitlabCi.masters)
+	configName string
+}
+
+func (k ciKind) accountsKey() string {
+	return fmt.Sprintf("%s.masters", k.igorName)
+}
+
+func (k ciKind) configAccountsKey() string {
+	return fmt.Sprintf("ci.%s.masters", k.configName)
+}
+
+func (k ciKind) services() []string {
+	return []string{igorService, echoService}
+}
+
+// serviceSettings enables the integration in Igor and Igor itself in Echo
+func (k ciKind) serviceSettings(svc string) map[string]interface{} {
+	switch svc {
+	case igorService:
+		return map[string]interface{}{fmt.Sprintf("%s.enabled", k.igorName): true}
+	case echoService:
+		return map[string]interface{}{"igor.enabled": true}
+	}
+	return nil
+}
+
+// rendersAccounts returns true for Igor, the only service that needs CI masters
+func (k ciKind) rendersAccounts(svc string) bool {
+	return svc == igorService
+}
+
+// validationSettings returns the validation settings of the kind in spec.validation.ci
+func (k ciKind) validationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	v := spinsvc.GetSpinnakerValidation()
+	for n, s := range v.CI {
+		if strings.ToLower(n) == strings.ToLower(k.configName) {
+			return &s
+		}
+	}
+	return v.GetValidationSettings()
+}
@@ -0,0 +1,33 @@
+package ci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/armory/spinnaker-operator/pkg/util"
+)
+
+// get sends a GET request with the given headers and fails if the response isn't a 200
+func get(ctx context.Context, url string, headers map[string]string, setAuth func(req *http.Request)) error {
+	s := &util.HttpService{}
+	req, err := s.Request(ctx, util.GET, url, nil, headers, nil)
+	if err != nil {
+		return err
+	}
+	if setAuth != nil {
+		setAuth(req)
+	}
+	resp, err := s.Execute(ctx, req)
+	if err != nil {
+		return err
+	}
+	b, err := s.ParseResponseBody(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %d: %s", url, resp.StatusCode, string(b))
+	}
+	return nil
+}
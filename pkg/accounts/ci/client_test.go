@@ -0,0 +1,63 @@
+package ci
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJenkinsClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/json", r.URL.Path)
+		u, p, ok := r.BasicAuth()
+		if !ok || u != "admin" || p != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"mode": "NORMAL"}`))
+	}))
+	defer srv.Close()
+
+	c := &jenkinsClientImpl{}
+	assert.Nil(t, c.GetInfo(context.TODO(), srv.URL+"/", "admin", "secret"))
+	err := c.GetInfo(context.TODO(), srv.URL, "admin", "wrong")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "returned 401")
+	}
+}
+
+func TestGitHubActionsClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orgs/my-org/repos" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, "token my-token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	c := &gitHubActionsClientImpl{}
+	assert.Nil(t, c.ListRepos(context.TODO(), srv.URL, "my-org", "my-token"))
+	assert.NotNil(t, c.ListRepos(context.TODO(), srv.URL, "other-org", "my-token"))
+}
+
+func TestGitLabCIClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/projects", r.URL.Path)
+		if r.Header.Get("PRIVATE-TOKEN") == "bad" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	c := &gitLabCIClientImpl{}
+	assert.Nil(t, c.ListProjects(context.TODO(), srv.URL, ""))
+	assert.Nil(t, c.ListProjects(context.TODO(), srv.URL, "good"))
+	assert.NotNil(t, c.ListProjects(context.TODO(), srv.URL, "bad"))
+}
@@ -0,0 +1,146 @@
+package ci
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"github.com/mitchellh/mapstructure"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const gitHubApiUrl = "https://api.github.com"
+
+var gitHubActionsKind = ciKind{accountType: interfaces.GitHubActionsAccountType, igorName: "github-actions", configName: "githubActions"}
+
+type GitHubActionsAccountType struct{}
+
+func (k *GitHubActionsAccountType) GetType() interfaces.AccountType {
+	return gitHubActionsKind.accountType
+}
+
+func (k *GitHubActionsAccountType) GetAccountsKey() string {
+	return gitHubActionsKind.accountsKey()
+}
+
+func (k *GitHubActionsAccountType) GetConfigAccountsKey() string {
+	return gitHubActionsKind.configAccountsKey()
+}
+
+func (k *GitHubActionsAccountType) GetServices() []string {
+	return gitHubActionsKind.services()
+}
+
+// GetPrimaryAccountsKey returns an empty key, CI accounts have no primary account
+func (k *GitHubActionsAccountType) GetPrimaryAccountsKey() string {
+	return ""
+}
+
+func (k *GitHubActionsAccountType) GetServiceSettings(svc string) map[string]interface{} {
+	return gitHubActionsKind.serviceSettings(svc)
+}
+
+func (k *GitHubActionsAccountType) RendersAccounts(svc string) bool {
+	return gitHubActionsKind.rendersAccounts(svc)
+}
+
+func (k *GitHubActionsAccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	return gitHubActionsKind.validationSettings(spinsvc)
+}
+
+func (k *GitHubActionsAccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := &GitHubActionsAccount{Name: account.GetName(), Settings: account.GetSpec().Settings}
+	if account.GetSpec().GitHubActions == nil {
+		return nil, noGitHubActionsDefinedError
+	}
+	account.GetSpec().GitHubActions.DeepCopyInto(&a.GitHubActions)
+	return a, nil
+}
+
+func (k *GitHubActionsAccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	name, err := account.ParseName(k.GetType(), settings)
+	if err != nil {
+		return nil, err
+	}
+	a := &GitHubActionsAccount{Name: name, Settings: settings}
+	if err := mapstructure.Decode(settings, &a.GitHubActions); err != nil {
+		return nil, fmt.Errorf("Error reading githubActions settings for account \"%s\":\n  %w", name, err)
+	}
+	return a, nil
+}
+
+type GitHubActionsAccount struct {
+	*account.BaseAccount
+	Name          string                          `json:"name,omitempty"`
+	GitHubActions interfaces.GitHubActionsAccount `json:"githubActions,omitempty"`
+	Settings      interfaces.FreeForm             `json:"settings,omitempty"`
+}
+
+func (k *GitHubActionsAccount) GetType() interfaces.AccountType {
+	return gitHubActionsKind.accountType
+}
+
+func (k *GitHubActionsAccount) GetName() string {
+	return k.Name
+}
+
+func (k *GitHubActionsAccount) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *GitHubActionsAccount) NewValidator() account.AccountValidator {
+	return &gitHubActionsAccountValidator{account: k, client: &gitHubActionsClientImpl{}}
+}
+
+func (k *GitHubActionsAccount) NewSettingsValidator() account.AccountValidator {
+	return &gitHubActionsAccountValidator{account: k}
+}
+
+func (k *GitHubActionsAccount) getBaseUrl() string {
+	if k.GitHubActions.BaseUrl != "" {
+		return k.GitHubActions.BaseUrl
+	}
+	return gitHubApiUrl
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
+func (k *GitHubActionsAccount) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	g := k.GitHubActions
+	if g.Organization == "" {
+		return nil, noOrganizationError
+	}
+	m[OrganizationSettings] = g.Organization
+	m[BaseUrlSettings] = k.getBaseUrl()
+	account.SetSecretReference(m, g.TokenSecret, TokenSettings)
+	return m, nil
+}
+
+type gitHubActionsAccountValidator struct {
+	account *GitHubActionsAccount
+	// client is nil if only settings are validated
+	client gitHubActionsClient
+}
+
+func (v *gitHubActionsAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	a := v.account
+	if a.GitHubActions.Organization == "" {
+		return noOrganizationError
+	}
+	token, err := account.GetSecretValue(ctx, a.GitHubActions.TokenSecret, a.Settings, TokenSettings)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return fmt.Errorf("githubActions account \"%s\" requires a token", a.Name)
+	}
+	if v.client == nil {
+		return nil
+	}
+	if err := v.client.ListRepos(ctx, a.getBaseUrl(), a.GitHubActions.Organization, token); err != nil {
+		return fmt.Errorf("unable to list repositories of organization \"%s\" with githubActions account \"%s\":\n  %w", a.GitHubActions.Organization, a.Name, err)
+	}
+	return nil
+}
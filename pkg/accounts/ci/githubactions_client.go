@@ -0,0 +1,23 @@
+package ci
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// gitHubActionsClient lists the repositories of an organization
+type gitHubActionsClient interface {
+	ListRepos(ctx context.Context, baseUrl, organization, token string) error
+}
+
+type gitHubActionsClientImpl struct{}
+
+func (g *gitHubActionsClientImpl) ListRepos(ctx context.Context, baseUrl, organization, token string) error {
+	u := fmt.Sprintf("%s/orgs/%s/repos?per_page=1", strings.TrimSuffix(baseUrl, "/"), url.PathEscape(organization))
+	return get(ctx, u, map[string]string{
+		"Accept":        "application/vnd.github.v3+json",
+		"Authorization": "token " + token,
+	}, nil)
+}
@@ -0,0 +1,137 @@
+package ci
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"github.com/mitchellh/mapstructure"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var gitLabCIKind = ciKind{accountType: interfaces.GitLabCIAccountType, igorName: "gitlab-ci", configName: "gitlabCi"}
+
+type GitLabCIAccountType struct{}
+
+func (k *GitLabCIAccountType) GetType() interfaces.AccountType {
+	return gitLabCIKind.accountType
+}
+
+func (k *GitLabCIAccountType) GetAccountsKey() string {
+	return gitLabCIKind.accountsKey()
+}
+
+func (k *GitLabCIAccountType) GetConfigAccountsKey() string {
+	return gitLabCIKind.configAccountsKey()
+}
+
+func (k *GitLabCIAccountType) GetServices() []string {
+	return gitLabCIKind.services()
+}
+
+// GetPrimaryAccountsKey returns an empty key, CI accounts have no primary account
+func (k *GitLabCIAccountType) GetPrimaryAccountsKey() string {
+	return ""
+}
+
+func (k *GitLabCIAccountType) GetServiceSettings(svc string) map[string]interface{} {
+	return gitLabCIKind.serviceSettings(svc)
+}
+
+func (k *GitLabCIAccountType) RendersAccounts(svc string) bool {
+	return gitLabCIKind.rendersAccounts(svc)
+}
+
+func (k *GitLabCIAccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	return gitLabCIKind.validationSettings(spinsvc)
+}
+
+func (k *GitLabCIAccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := &GitLabCIAccount{Name: account.GetName(), Settings: account.GetSpec().Settings}
+	if account.GetSpec().GitLabCI == nil {
+		return nil, noGitLabCIDefinedError
+	}
+	account.GetSpec().GitLabCI.DeepCopyInto(&a.GitLabCI)
+	return a, nil
+}
+
+func (k *GitLabCIAccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	name, err := account.ParseName(k.GetType(), settings)
+	if err != nil {
+		return nil, err
+	}
+	a := &GitLabCIAccount{Name: name, Settings: settings}
+	if err := mapstructure.Decode(settings, &a.GitLabCI); err != nil {
+		return nil, fmt.Errorf("Error reading gitlabCI settings for account \"%s\":\n  %w", name, err)
+	}
+	return a, nil
+}
+
+type GitLabCIAccount struct {
+	*account.BaseAccount
+	Name     string                     `json:"name,omitempty"`
+	GitLabCI interfaces.GitLabCIAccount `json:"gitlabCI,omitempty"`
+	Settings interfaces.FreeForm        `json:"settings,omitempty"`
+}
+
+func (k *GitLabCIAccount) GetType() interfaces.AccountType {
+	return gitLabCIKind.accountType
+}
+
+func (k *GitLabCIAccount) GetName() string {
+	return k.Name
+}
+
+func (k *GitLabCIAccount) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *GitLabCIAccount) NewValidator() account.AccountValidator {
+	return &gitLabCIAccountValidator{account: k, client: &gitLabCIClientImpl{}}
+}
+
+func (k *GitLabCIAccount) NewSettingsValidator() account.AccountValidator {
+	return &gitLabCIAccountValidator{account: k}
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
+func (k *GitLabCIAccount) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	g := k.GitLabCI
+	if g.Address == "" {
+		return nil, noAddressError
+	}
+	m[AddressSettings] = g.Address
+	account.SetSecretReference(m, g.PrivateTokenSecret, PrivateTokenSettings)
+	return m, nil
+}
+
+type gitLabCIAccountValidator struct {
+	account *GitLabCIAccount
+	// client is nil if only settings are validated
+	client gitLabCIClient
+}
+
+func (v *gitLabCIAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	a := v.account
+	if a.GitLabCI.Address == "" {
+		return noAddressError
+	}
+	if _, err := url.ParseRequestURI(a.GitLabCI.Address); err != nil {
+		return fmt.Errorf("invalid address for gitlabCI account \"%s\":\n  %w", a.Name, err)
+	}
+	token, err := account.GetSecretValue(ctx, a.GitLabCI.PrivateTokenSecret, a.Settings, PrivateTokenSettings)
+	if err != nil {
+		return err
+	}
+	if v.client == nil {
+		return nil
+	}
+	if err := v.client.ListProjects(ctx, a.GitLabCI.Address, token); err != nil {
+		return fmt.Errorf("unable to list projects with gitlabCI account \"%s\":\n  %w", a.Name, err)
+	}
+	return nil
+}
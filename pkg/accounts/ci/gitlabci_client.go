@@ -0,0 +1,21 @@
+package ci
+
+import (
+	"context"
+	"strings"
+)
+
+// gitLabCIClient lists the projects visible with a private token, anonymously if the token is empty
+type gitLabCIClient interface {
+	ListProjects(ctx context.Context, address, token string) error
+}
+
+type gitLabCIClientImpl struct{}
+
+func (g *gitLabCIClientImpl) ListProjects(ctx context.Context, address, token string) error {
+	headers := map[string]string{}
+	if token != "" {
+		headers["PRIVATE-TOKEN"] = token
+	}
+	return get(ctx, strings.TrimSuffix(address, "/")+"/api/v4/projects?membership=true&per_page=1", headers, nil)
+}
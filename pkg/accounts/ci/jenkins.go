@@ -0,0 +1,149 @@
+package ci
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"github.com/mitchellh/mapstructure"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var jenkinsKind = ciKind{accountType: interfaces.JenkinsAccountType, igorName: "jenkins", configName: "jenkins"}
+
+type JenkinsAccountType struct{}
+
+func (k *JenkinsAccountType) GetType() interfaces.AccountType {
+	return jenkinsKind.accountType
+}
+
+func (k *JenkinsAccountType) GetAccountsKey() string {
+	return jenkinsKind.accountsKey()
+}
+
+func (k *JenkinsAccountType) GetConfigAccountsKey() string {
+	return jenkinsKind.configAccountsKey()
+}
+
+func (k *JenkinsAccountType) GetServices() []string {
+	return jenkinsKind.services()
+}
+
+// GetPrimaryAccountsKey returns an empty key, CI accounts have no primary account
+func (k *JenkinsAccountType) GetPrimaryAccountsKey() string {
+	return ""
+}
+
+func (k *JenkinsAccountType) GetServiceSettings(svc string) map[string]interface{} {
+	return jenkinsKind.serviceSettings(svc)
+}
+
+func (k *JenkinsAccountType) RendersAccounts(svc string) bool {
+	return jenkinsKind.rendersAccounts(svc)
+}
+
+func (k *JenkinsAccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	return jenkinsKind.validationSettings(spinsvc)
+}
+
+func (k *JenkinsAccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := &JenkinsAccount{Name: account.GetName(), Settings: account.GetSpec().Settings}
+	if account.GetSpec().Jenkins == nil {
+		return nil, noJenkinsDefinedError
+	}
+	account.GetSpec().Jenkins.DeepCopyInto(&a.Jenkins)
+	return a, nil
+}
+
+func (k *JenkinsAccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	name, err := account.ParseName(k.GetType(), settings)
+	if err != nil {
+		return nil, err
+	}
+	a := &JenkinsAccount{Name: name, Settings: settings}
+	if err := mapstructure.Decode(settings, &a.Jenkins); err != nil {
+		return nil, fmt.Errorf("Error reading jenkins settings for account \"%s\":\n  %w", name, err)
+	}
+	return a, nil
+}
+
+type JenkinsAccount struct {
+	*account.BaseAccount
+	Name     string                    `json:"name,omitempty"`
+	Jenkins  interfaces.JenkinsAccount `json:"jenkins,omitempty"`
+	Settings interfaces.FreeForm       `json:"settings,omitempty"`
+}
+
+func (k *JenkinsAccount) GetType() interfaces.AccountType {
+	return jenkinsKind.accountType
+}
+
+func (k *JenkinsAccount) GetName() string {
+	return k.Name
+}
+
+func (k *JenkinsAccount) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *JenkinsAccount) NewValidator() account.AccountValidator {
+	return &jenkinsAccountValidator{account: k, client: &jenkinsClientImpl{}}
+}
+
+func (k *JenkinsAccount) NewSettingsValidator() account.AccountValidator {
+	return &jenkinsAccountValidator{account: k}
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
+func (k *JenkinsAccount) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	j := k.Jenkins
+	if j.Address == "" {
+		return nil, noAddressError
+	}
+	m[AddressSettings] = j.Address
+	if j.Username != "" {
+		m[UsernameSettings] = j.Username
+	}
+	if j.Csrf {
+		m[CsrfSettings] = true
+	}
+	account.SetSecretReference(m, j.PasswordSecret, PasswordSettings)
+	return m, nil
+}
+
+type jenkinsAccountValidator struct {
+	account *JenkinsAccount
+	// client is nil if only settings are validated
+	client jenkinsClient
+}
+
+func (v *jenkinsAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	a := v.account
+	if a.Jenkins.Address == "" {
+		return noAddressError
+	}
+	if _, err := url.ParseRequestURI(a.Jenkins.Address); err != nil {
+		return fmt.Errorf("invalid address for jenkins account \"%s\":\n  %w", a.Name, err)
+	}
+	password, err := account.GetSecretValue(ctx, a.Jenkins.PasswordSecret, a.Settings, PasswordSettings)
+	if err != nil {
+		return err
+	}
+	if a.Jenkins.Username != "" && password == "" {
+		return fmt.Errorf("jenkins account \"%s\" has a username but no password", a.Name)
+	}
+	if a.Jenkins.Username == "" && password != "" {
+		return fmt.Errorf("jenkins account \"%s\" has a password but no username", a.Name)
+	}
+	if v.client == nil {
+		return nil
+	}
+	if err := v.client.GetInfo(ctx, a.Jenkins.Address, a.Jenkins.Username, password); err != nil {
+		return fmt.Errorf("unable to reach jenkins account \"%s\":\n  %w", a.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package ci
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// jenkinsClient reads the top level information of a Jenkins master
+type jenkinsClient interface {
+	GetInfo(ctx context.Context, address, username, password string) error
+}
+
+type jenkinsClientImpl struct{}
+
+func (j *jenkinsClientImpl) GetInfo(ctx context.Context, address, username, password string) error {
+	return get(ctx, strings.TrimSuffix(address, "/")+"/api/json", nil, func(req *http.Request) {
+		if username != "" {
+			req.SetBasicAuth(username, password)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package ci
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func TestFromCRD(t *testing.T) {
+	tests := []struct {
+		name        string
+		accountType account.SpinnakerAccountType
+		manifest    string
+		expected    func(t *testing.T, a account.Account, err error)
+	}{
+		{
+			name:        "no jenkins section in CRD",
+			accountType: &JenkinsAccountType{},
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: Jenkins
+`,
+			expected: func(t *testing.T, _ account.Account, err error) {
+				assert.Equal(t, noJenkinsDefinedError, err)
+			},
+		},
+		{
+			name:        "jenkins",
+			accountType: &JenkinsAccountType{},
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: Jenkins
+  jenkins:
+    address: https://jenkins.example.com
+    username: admin
+    passwordSecret:
+      name: jenkins
+      key: password
+    csrf: true
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if assert.Nil(t, err) {
+					j := a.(*JenkinsAccount)
+					assert.Equal(t, "https://jenkins.example.com", j.Jenkins.Address)
+					assert.Equal(t, "admin", j.Jenkins.Username)
+					assert.Equal(t, "jenkins", j.Jenkins.PasswordSecret.Name)
+					assert.True(t, j.Jenkins.Csrf)
+				}
+			},
+		},
+		{
+			name:        "github actions",
+			accountType: &GitHubActionsAccountType{},
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: GitHubActions
+  githubActions:
+    organization: my-org
+    tokenSecret:
+      name: github
+      key: token
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if assert.Nil(t, err) {
+					g := a.(*GitHubActionsAccount)
+					assert.Equal(t, "my-org", g.GitHubActions.Organization)
+					assert.Equal(t, gitHubApiUrl, g.getBaseUrl())
+				}
+			},
+		},
+		{
+			name:        "no gitlabCI section in CRD",
+			accountType: &GitLabCIAccountType{},
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: GitLabCI
+`,
+			expected: func(t *testing.T, _ account.Account, err error) {
+				assert.Equal(t, noGitLabCIDefinedError, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sa := test.TypesFactory.NewAccount()
+			if !assert.Nil(t, yaml.Unmarshal([]byte(tt.manifest), sa)) {
+				return
+			}
+			a, err := tt.accountType.FromCRD(sa)
+			tt.expected(t, a, err)
+		})
+	}
+}
+
+func TestFromSpinnakerSettings(t *testing.T) {
+	k := &JenkinsAccountType{}
+	a, err := k.FromSpinnakerConfig(context.TODO(), map[string]interface{}{
+		"name":     "jenkins",
+		"address":  "https://jenkins.example.com",
+		"username": "admin",
+		"password": "encrypted:s3!b:bucket!f:secrets.yml!k:jenkins.password",
+		"csrf":     true,
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	j := a.(*JenkinsAccount)
+	assert.Equal(t, "https://jenkins.example.com", j.Jenkins.Address)
+	assert.True(t, j.Jenkins.Csrf)
+
+	ss, err := j.ToSpinnakerSettings(context.TODO())
+	if assert.Nil(t, err) {
+		assert.Equal(t, "encrypted:s3!b:bucket!f:secrets.yml!k:jenkins.password", ss[PasswordSettings])
+		assert.Equal(t, true, ss[CsrfSettings])
+	}
+}
+
+func TestToSpinnakerSettings(t *testing.T) {
+	g := &GitHubActionsAccount{
+		Name: "gha",
+		GitHubActions: interfaces.GitHubActionsAccount{
+			Organization: "my-org",
+			TokenSecret:  &interfaces.SecretInNamespaceReference{Name: "gha-secret", Key: "token"},
+		},
+	}
+	ss, err := g.ToSpinnakerSettings(context.TODO())
+	if assert.Nil(t, err) {
+		assert.Equal(t, "gha", ss["name"])
+		assert.Equal(t, "my-org", ss[OrganizationSettings])
+		assert.Equal(t, gitHubApiUrl, ss[BaseUrlSettings])
+		// the token is mapped to the service's environment when Spinnaker is deployed
+		assert.Equal(t, "encrypted:k8s!n:gha-secret!k:token", ss[TokenSettings])
+	}
+
+	l := &GitLabCIAccount{Name: "gitlab"}
+	_, err = l.ToSpinnakerSettings(context.TODO())
+	assert.Equal(t, noAddressError, err)
+}
+
+func TestKeys(t *testing.T) {
+	k := &GitLabCIAccountType{}
+	assert.Equal(t, "gitlab-ci.masters", k.GetAccountsKey())
+	assert.Equal(t, "ci.gitlabCi.masters", k.GetConfigAccountsKey())
+	assert.Equal(t, map[string]interface{}{"gitlab-ci.enabled": true}, k.GetServiceSettings("igor"))
+	assert.Equal(t, map[string]interface{}{"igor.enabled": true}, k.GetServiceSettings("echo"))
+	assert.True(t, k.RendersAccounts("igor"))
+	assert.False(t, k.RendersAccounts("echo"))
+}
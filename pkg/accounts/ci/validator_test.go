@@ -0,0 +1,189 @@
+package ci
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	logr "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type fakeJenkinsClient struct {
+	err      error
+	called   bool
+	address  string
+	username string
+	password string
+}
+
+func (f *fakeJenkinsClient) GetInfo(ctx context.Context, address, username, password string) error {
+	f.called = true
+	f.address = address
+	f.username = username
+	f.password = password
+	return f.err
+}
+
+type fakeGitHubActionsClient struct {
+	err          error
+	baseUrl      string
+	organization string
+}
+
+func (f *fakeGitHubActionsClient) ListRepos(ctx context.Context, baseUrl, organization, token string) error {
+	f.baseUrl = baseUrl
+	f.organization = organization
+	return f.err
+}
+
+type fakeGitLabCIClient struct {
+	err   error
+	token string
+}
+
+func (f *fakeGitLabCIClient) ListProjects(ctx context.Context, address, token string) error {
+	f.token = token
+	return f.err
+}
+
+func TestJenkinsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		jenkins  interfaces.JenkinsAccount
+		settings interfaces.FreeForm
+		client   *fakeJenkinsClient
+		expected func(t *testing.T, c *fakeJenkinsClient, err error)
+	}{
+		{
+			name:     "valid credentials",
+			jenkins:  interfaces.JenkinsAccount{Address: "https://jenkins.example.com", Username: "admin"},
+			settings: interfaces.FreeForm{PasswordSettings: "secret"},
+			client:   &fakeJenkinsClient{},
+			expected: func(t *testing.T, c *fakeJenkinsClient, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "https://jenkins.example.com", c.address)
+				assert.Equal(t, "admin", c.username)
+				assert.Equal(t, "secret", c.password)
+			},
+		},
+		{
+			name:   "no address",
+			client: &fakeJenkinsClient{},
+			expected: func(t *testing.T, c *fakeJenkinsClient, err error) {
+				assert.Equal(t, noAddressError, err)
+				assert.False(t, c.called)
+			},
+		},
+		{
+			name:    "invalid address",
+			jenkins: interfaces.JenkinsAccount{Address: "jenkins"},
+			client:  &fakeJenkinsClient{},
+			expected: func(t *testing.T, c *fakeJenkinsClient, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "invalid address")
+				}
+				assert.False(t, c.called)
+			},
+		},
+		{
+			name:    "username without password",
+			jenkins: interfaces.JenkinsAccount{Address: "https://jenkins.example.com", Username: "admin"},
+			client:  &fakeJenkinsClient{},
+			expected: func(t *testing.T, c *fakeJenkinsClient, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "has a username but no password")
+				}
+				assert.False(t, c.called)
+			},
+		},
+		{
+			name:    "unreachable",
+			jenkins: interfaces.JenkinsAccount{Address: "https://jenkins.example.com"},
+			client:  &fakeJenkinsClient{err: errors.New("connection refused")},
+			expected: func(t *testing.T, c *fakeJenkinsClient, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "connection refused")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+			defer secrets.Cleanup(ctx)
+			v := &jenkinsAccountValidator{
+				account: &JenkinsAccount{Name: "jenkins", Jenkins: tt.jenkins, Settings: tt.settings},
+				client:  tt.client,
+			}
+			err := v.Validate(nil, nil, ctx, logr.Log)
+			tt.expected(t, tt.client, err)
+		})
+	}
+}
+
+func TestGitHubActionsValidate(t *testing.T) {
+	ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+	defer secrets.Cleanup(ctx)
+	c := &fakeGitHubActionsClient{}
+	v := &gitHubActionsAccountValidator{
+		account: &GitHubActionsAccount{
+			Name:          "gha",
+			GitHubActions: interfaces.GitHubActionsAccount{Organization: "my-org", BaseUrl: "https://github.example.com/api/v3"},
+		},
+		client: c,
+	}
+	err := v.Validate(nil, nil, ctx, logr.Log)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "requires a token")
+	}
+
+	v.account.Settings = interfaces.FreeForm{TokenSettings: "my-token"}
+	assert.Nil(t, v.Validate(nil, nil, ctx, logr.Log))
+	assert.Equal(t, "https://github.example.com/api/v3", c.baseUrl)
+	assert.Equal(t, "my-org", c.organization)
+}
+
+func TestGitLabCIValidate(t *testing.T) {
+	ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+	defer secrets.Cleanup(ctx)
+	c := &fakeGitLabCIClient{}
+	v := &gitLabCIAccountValidator{
+		account: &GitLabCIAccount{
+			Name:     "gitlab",
+			GitLabCI: interfaces.GitLabCIAccount{Address: "https://gitlab.example.com"},
+			Settings: interfaces.FreeForm{PrivateTokenSettings: "my-token"},
+		},
+		client: c,
+	}
+	assert.Nil(t, v.Validate(nil, nil, ctx, logr.Log))
+	assert.Equal(t, "my-token", c.token)
+
+	c.err = errors.New("401 Unauthorized")
+	assert.NotNil(t, v.Validate(nil, nil, ctx, logr.Log))
+	// settings are checked without calling GitLab
+	assert.Nil(t, v.account.NewSettingsValidator().Validate(nil, nil, ctx, logr.Log))
+
+	v.account.GitLabCI.Address = ""
+	assert.Equal(t, noAddressError, v.account.NewSettingsValidator().Validate(nil, nil, ctx, logr.Log))
+}
+
+func TestGetValidationSettings(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(`
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: test
+spec:
+  validation:
+    ci:
+      jenkins:
+        enabled: false
+`, t)
+	assert.False(t, (&JenkinsAccountType{}).GetValidationSettings(spinsvc).Enabled)
+	assert.True(t, (&GitLabCIAccountType{}).GetValidationSettings(spinsvc).Enabled)
+}
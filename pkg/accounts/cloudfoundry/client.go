@@ -0,0 +1,9 @@
+package cloudfoundry
+
+import "github.com/armory/spinnaker-operator/pkg/util"
+
+// Client authenticates to a Cloud Foundry foundation and lists its organizations
+type Client interface {
+	RequestToken(api string, appsManagerUri string, user string, password string, skipHttps bool, httpService util.HttpService) (string, error)
+	GetOrganizations(token string, api string, appsManagerUri string, skipHttps bool) (bool, error)
+}
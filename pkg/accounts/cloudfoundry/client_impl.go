@@ -1,4 +1,4 @@
-package validate
+package cloudfoundry
 
 import (
 	"crypto/tls"
@@ -20,7 +20,7 @@ const (
 	apiOrganizations = "/v3/organizations/"
 )
 
-func NewCloudFoundryClient() CloudFoundryClient {
+func NewClient() Client {
 	return &cfClient{}
 }
 
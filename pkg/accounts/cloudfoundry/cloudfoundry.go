@@ -0,0 +1,85 @@
+package cloudfoundry
+
+import (
+	"errors"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"strings"
+)
+
+// Cloud Foundry accounts are read from the `cloudFoundry` section of the SpinnakerAccount or from Spinnaker settings.
+// The password referenced in a Kubernetes secret is read when the account is rendered to Clouddriver's settings.
+const (
+	ApiSettings               = "api"
+	AppsManagerUriSettings    = "appsManagerUri"
+	MetricsUriSettings        = "metricsUri"
+	UserSettings              = "user"
+	PasswordSettings          = "password"
+	EnvironmentSettings       = "environment"
+	SkipSslValidationSettings = "skipSslValidation"
+)
+
+var (
+	noCloudFoundryDefinedError = errors.New("cloudFoundry needs to be defined")
+)
+
+type AccountType struct{}
+
+func (k *AccountType) GetType() interfaces.AccountType {
+	return interfaces.CloudFoundryAccountType
+}
+
+func (k *AccountType) GetAccountsKey() string {
+	return "cloudfoundry.accounts"
+}
+
+func (k *AccountType) GetConfigAccountsKey() string {
+	return "providers.cloudfoundry.accounts"
+}
+
+func (k *AccountType) GetServices() []string {
+	return []string{"clouddriver"}
+}
+
+func (k *AccountType) GetPrimaryAccountsKey() string {
+	return "providers.cloudfoundry.primaryAccount"
+}
+
+func (k *AccountType) newAccount() *Account {
+	return &Account{
+		CloudFoundry: interfaces.CloudFoundryAccount{},
+	}
+}
+
+func (k *AccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	v := spinsvc.GetSpinnakerValidation()
+	for n, s := range v.Providers {
+		if strings.ToLower(n) == strings.ToLower(string(interfaces.CloudFoundryAccountType)) {
+			return &s
+		}
+	}
+	return v.GetValidationSettings()
+}
+
+type Account struct {
+	*account.BaseAccount
+	Name         string                         `json:"name,omitempty"`
+	CloudFoundry interfaces.CloudFoundryAccount `json:"cloudFoundry,omitempty"`
+	Settings     interfaces.FreeForm            `json:"settings,omitempty"`
+}
+
+func (k *Account) GetType() interfaces.AccountType {
+	return interfaces.CloudFoundryAccountType
+}
+
+func (k *Account) GetName() string {
+	return k.Name
+}
+
+func (k *Account) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *Account) NewValidator() account.AccountValidator {
+	return &cloudFoundryAccountValidator{account: k, client: NewClient()}
+}
@@ -0,0 +1,62 @@
+package cloudfoundry
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/armory/spinnaker-operator/pkg/util"
+)
+
+const (
+	namePattern = "^[a-z0-9]+([-a-z0-9]*[a-z0-9])?$"
+	apiPattern  = "^(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\\.)+[a-z0-9][a-z0-9-]{0,61}[a-z0-9]$"
+)
+
+// AccountConfig is a Cloud Foundry account as defined in Clouddriver's settings
+type AccountConfig struct {
+	Name                    string                 `json:"name,omitempty"`
+	Environment             string                 `json:"environment,omitempty"`
+	RequiredGroupMembership []string               `json:"requiredGroupMembership,omitempty"`
+	Permissions             map[string]interface{} `json:"permissions,omitempty"`
+	ProviderVersion         string                 `json:"providerVersion,omitempty"`
+	User                    string                 `json:"user,omitempty"`
+	Password                string                 `json:"password,omitempty"`
+	Api                     string                 `json:"api,omitempty"`
+	AppsManagerUri          string                 `json:"appsManagerUri,omitempty"`
+	MetricsUri              string                 `json:"metricsUri,omitempty"`
+	SkipSslValidation       bool                   `json:"skipSslValidation,omitempty"`
+}
+
+// ValidateAccountConfig checks the account's settings then authenticates to the foundation
+// and lists its organizations with the given client.
+func ValidateAccountConfig(cfAccount AccountConfig, cfClient Client) error {
+	if len(cfAccount.Name) == 0 {
+		return fmt.Errorf("error validating cloudFoundry account missing account name")
+	}
+
+	if len(regexp.MustCompile(namePattern).FindStringSubmatch(cfAccount.Name)) == 0 {
+		return fmt.Errorf("error validating cloudFoundry account \"%s\": Account name must match pattern %s\nIt must start and end with a lower-case character or number, and only contain lower-case characters, numbers, or dashes", cfAccount.Name, namePattern)
+	}
+
+	if len(cfAccount.User) == 0 || len(cfAccount.Password) == 0 {
+		return fmt.Errorf("error validating cloudFoundry You must provide a user and a password")
+	}
+
+	if len(regexp.MustCompile(apiPattern).FindStringSubmatch(cfAccount.Api)) == 0 {
+		return fmt.Errorf("error validating cloudFoundry account \"%s\": API must match pattern %s\nDomain format", cfAccount.Name, apiPattern)
+	}
+
+	token, err := cfClient.RequestToken(cfAccount.Api, cfAccount.AppsManagerUri, cfAccount.User, cfAccount.Password, cfAccount.SkipSslValidation, util.HttpService{})
+	if err != nil {
+		return err
+	}
+
+	ok, err := cfClient.GetOrganizations(token, cfAccount.Api, cfAccount.AppsManagerUri, cfAccount.SkipSslValidation)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("error validating cloudFoundry account \"%s\": unable to list organizations", cfAccount.Name)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package cloudfoundry
+
+import (
+	"context"
+	"fmt"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/mitchellh/mapstructure"
+)
+
+func (k *AccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := k.newAccount()
+	a.Name = account.GetName()
+	a.Settings = account.GetSpec().Settings
+	if account.GetSpec().CloudFoundry == nil {
+		return nil, noCloudFoundryDefinedError
+	}
+	account.GetSpec().CloudFoundry.DeepCopyInto(&a.CloudFoundry)
+	return a, nil
+}
+
+func (k *AccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	a := k.newAccount()
+	n, ok := settings["name"]
+	if !ok {
+		return nil, fmt.Errorf("%s account missing name", a.GetType())
+	}
+	if name, ok := n.(string); ok {
+		a.Name = name
+	} else {
+		return nil, fmt.Errorf("name is not a string")
+	}
+	if err := mapstructure.Decode(settings, &a.CloudFoundry); err != nil {
+		return nil, fmt.Errorf("Error reading cloudfoundry settings for account \"%s\":\n  %w", a.Name, err)
+	}
+	a.Settings = settings
+	return a, nil
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
+func (k *Account) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	c := k.CloudFoundry
+	m[ApiSettings] = c.Api
+	m[UserSettings] = c.User
+	account.SetSecretReference(m, c.PasswordSecret, PasswordSettings)
+	if c.AppsManagerUri != "" {
+		m[AppsManagerUriSettings] = c.AppsManagerUri
+	}
+	if c.MetricsUri != "" {
+		m[MetricsUriSettings] = c.MetricsUri
+	}
+	if c.Environment != "" {
+		m[EnvironmentSettings] = c.Environment
+	}
+	if c.SkipSslValidation {
+		m[SkipSslValidationSettings] = true
+	}
+	return m, nil
+}
+
+// getPassword returns the password of the account's user.
+// Passwords passed in settings are decoded if they're secret references.
+func (k *Account) getPassword(ctx context.Context) (string, error) {
+	if k.CloudFoundry.PasswordSecret != nil {
+		return k.getSecretPassword(ctx)
+	}
+	p, ok := k.Settings[PasswordSettings].(string)
+	if !ok || p == "" {
+		return "", nil
+	}
+	password, err := account.DecodeSecret(ctx, p)
+	if err != nil {
+		return "", fmt.Errorf("Error decoding password of cloudfoundry account \"%s\":\n  %w", k.Name, err)
+	}
+	return password, nil
+}
+
+// getSecretPassword reads the password from the Kubernetes secret referenced by the account
+func (k *Account) getSecretPassword(ctx context.Context) (string, error) {
+	return account.ReadSecret(ctx, k.CloudFoundry.PasswordSecret)
+}
+
+// toAccountConfig converts the account to the settings checked by ValidateAccountConfig
+func (k *Account) toAccountConfig(ctx context.Context) (AccountConfig, error) {
+	cfg := AccountConfig{}
+	password, err := k.getPassword(ctx)
+	if err != nil {
+		return cfg, err
+	}
+	c := k.CloudFoundry
+	cfg.Name = k.Name
+	cfg.Api = c.Api
+	cfg.AppsManagerUri = c.AppsManagerUri
+	cfg.MetricsUri = c.MetricsUri
+	cfg.User = c.User
+	cfg.Password = password
+	cfg.Environment = c.Environment
+	cfg.SkipSslValidation = c.SkipSslValidation
+	return cfg, nil
+}
@@ -0,0 +1,123 @@
+package cloudfoundry
+
+import (
+	"context"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+	"testing"
+)
+
+func TestFromCRD(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expected func(t *testing.T, a account.Account, err error)
+	}{
+		{
+			name: "no cloudFoundry section in CRD",
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: CloudFoundry
+`,
+			expected: func(t *testing.T, _ account.Account, err error) {
+				assert.Equal(t, noCloudFoundryDefinedError, err)
+			},
+		},
+		{
+			name: "full cloudFoundry config",
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: CloudFoundry
+  cloudFoundry:
+    api: api.sys.example.com
+    appsManagerUri: https://apps.sys.example.com
+    metricsUri: https://metrics.sys.example.com
+    user: admin
+    passwordSecret:
+      name: cf-secret
+      key: password
+    environment: dev
+    skipSslValidation: true
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if !assert.Nil(t, err) {
+					return
+				}
+				c := a.(*Account)
+				assert.Equal(t, "api.sys.example.com", c.CloudFoundry.Api)
+				assert.Equal(t, "admin", c.CloudFoundry.User)
+				assert.Equal(t, "cf-secret", c.CloudFoundry.PasswordSecret.Name)
+				assert.Equal(t, "dev", c.CloudFoundry.Environment)
+				assert.True(t, c.CloudFoundry.SkipSslValidation)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sa := test.TypesFactory.NewAccount()
+			if !assert.Nil(t, yaml.Unmarshal([]byte(tt.manifest), sa)) {
+				return
+			}
+			k := &AccountType{}
+			a, err := k.FromCRD(sa)
+			tt.expected(t, a, err)
+		})
+	}
+}
+
+func TestFromSpinnakerSettings(t *testing.T) {
+	k := &AccountType{}
+	a, err := k.FromSpinnakerConfig(context.TODO(), map[string]interface{}{
+		"name":           "cf",
+		"api":            "api.sys.example.com",
+		"appsManagerUri": "https://apps.sys.example.com",
+		"user":           "admin",
+		"password":       "encrypted:s3!b:bucket!f:secrets.yml!k:cf.password",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	c := a.(*Account)
+	assert.Equal(t, "api.sys.example.com", c.CloudFoundry.Api)
+	assert.Equal(t, "admin", c.CloudFoundry.User)
+
+	ss, err := c.ToSpinnakerSettings(context.TODO())
+	if assert.Nil(t, err) {
+		assert.Equal(t, "encrypted:s3!b:bucket!f:secrets.yml!k:cf.password", ss[PasswordSettings])
+		assert.Equal(t, "https://apps.sys.example.com", ss[AppsManagerUriSettings])
+		assert.Nil(t, ss[SkipSslValidationSettings])
+	}
+}
+
+func TestToSpinnakerSettings(t *testing.T) {
+	a := &Account{
+		Name: "cf",
+		CloudFoundry: interfaces.CloudFoundryAccount{
+			Api:               "api.sys.example.com",
+			User:              "admin",
+			SkipSslValidation: true,
+		},
+	}
+	ss, err := a.ToSpinnakerSettings(context.TODO())
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "cf", ss["name"])
+	assert.Equal(t, "api.sys.example.com", ss[ApiSettings])
+	assert.Equal(t, "admin", ss[UserSettings])
+	assert.Equal(t, true, ss[SkipSslValidationSettings])
+	assert.Nil(t, ss[PasswordSettings])
+	assert.Nil(t, ss[MetricsUriSettings])
+}
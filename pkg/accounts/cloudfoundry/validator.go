@@ -0,0 +1,22 @@
+package cloudfoundry
+
+import (
+	"context"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type cloudFoundryAccountValidator struct {
+	account *Account
+	client  Client
+}
+
+func (c *cloudFoundryAccountValidator) Validate(spinSvc interfaces.SpinnakerService, cl client.Client, ctx context.Context, log logr.Logger) error {
+	cfg, err := c.account.toAccountConfig(ctx)
+	if err != nil {
+		return err
+	}
+	return ValidateAccountConfig(cfg, c.client)
+}
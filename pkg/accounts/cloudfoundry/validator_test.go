@@ -0,0 +1,132 @@
+package cloudfoundry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/stretchr/testify/assert"
+	logr "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type fakeClient struct {
+	tokenErr error
+	orgs     bool
+	orgsErr  error
+	user     string
+	password string
+}
+
+func (f *fakeClient) RequestToken(api string, appsManagerUri string, user string, password string, skipHttps bool, httpService util.HttpService) (string, error) {
+	f.user = user
+	f.password = password
+	return "token", f.tokenErr
+}
+
+func (f *fakeClient) GetOrganizations(token string, api string, appsManagerUri string, skipHttps bool) (bool, error) {
+	return f.orgs, f.orgsErr
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		account  *Account
+		client   *fakeClient
+		expected func(t *testing.T, c *fakeClient, err error)
+	}{
+		{
+			name: "valid account",
+			account: &Account{
+				Name:         "dev",
+				CloudFoundry: interfaces.CloudFoundryAccount{Api: "api.sys.example.com", User: "admin"},
+				Settings:     interfaces.FreeForm{PasswordSettings: "123password"},
+			},
+			client: &fakeClient{orgs: true},
+			expected: func(t *testing.T, c *fakeClient, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "admin", c.user)
+				assert.Equal(t, "123password", c.password)
+			},
+		},
+		{
+			name: "authentication failure",
+			account: &Account{
+				Name:         "dev",
+				CloudFoundry: interfaces.CloudFoundryAccount{Api: "api.sys.example.com", User: "admin"},
+				Settings:     interfaces.FreeForm{PasswordSettings: "wrong"},
+			},
+			client: &fakeClient{tokenErr: errors.New("Unable to authenticate to cloudfoundry")},
+			expected: func(t *testing.T, c *fakeClient, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "Unable to authenticate")
+				}
+			},
+		},
+		{
+			name: "organizations not listed",
+			account: &Account{
+				Name:         "dev",
+				CloudFoundry: interfaces.CloudFoundryAccount{Api: "api.sys.example.com", User: "admin"},
+				Settings:     interfaces.FreeForm{PasswordSettings: "123password"},
+			},
+			client: &fakeClient{orgs: false},
+			expected: func(t *testing.T, c *fakeClient, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			name: "missing password",
+			account: &Account{
+				Name:         "dev",
+				CloudFoundry: interfaces.CloudFoundryAccount{Api: "api.sys.example.com", User: "admin"},
+			},
+			client: &fakeClient{orgs: true},
+			expected: func(t *testing.T, c *fakeClient, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "user and a password")
+				}
+				assert.Equal(t, "", c.user)
+			},
+		},
+		{
+			name: "invalid name",
+			account: &Account{
+				Name:         "Dev",
+				CloudFoundry: interfaces.CloudFoundryAccount{Api: "api.sys.example.com", User: "admin"},
+				Settings:     interfaces.FreeForm{PasswordSettings: "123password"},
+			},
+			client: &fakeClient{orgs: true},
+			expected: func(t *testing.T, c *fakeClient, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "Account name must match pattern")
+				}
+			},
+		},
+		{
+			name: "invalid api",
+			account: &Account{
+				Name:         "dev",
+				CloudFoundry: interfaces.CloudFoundryAccount{Api: "https://api.sys.example.com", User: "admin"},
+				Settings:     interfaces.FreeForm{PasswordSettings: "123password"},
+			},
+			client: &fakeClient{orgs: true},
+			expected: func(t *testing.T, c *fakeClient, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "API must match pattern")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+			defer secrets.Cleanup(ctx)
+			v := &cloudFoundryAccountValidator{account: tt.account, client: tt.client}
+			tt.expected(t, tt.client, v.Validate(nil, nil, ctx, logr.Log))
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package configserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The operator serves SpinnakerAccount settings through a Spring Cloud Config compatible endpoint over TLS when
+// started with --accounts-config-server and spec.accounts.dynamic is true. Clouddriver imports its accounts from that
+// endpoint and reloads them periodically instead of reading them from its config secret. Requests are authenticated
+// with a token generated for the SpinnakerService namespace.
+const (
+	Port       = 8989
+	PathPrefix = "/accounts"
+	// RefreshIntervalSeconds is how often Clouddriver refreshes its configuration from the endpoint
+	RefreshIntervalSeconds = 30
+	// TokenSecretName is the secret of the SpinnakerService namespace holding the token its services send
+	TokenSecretName = "spin-accounts-config"
+	TokenKey        = "token"
+	// TokenHeader is the header Spring Cloud Config clients send spring.cloud.config.token in
+	TokenHeader = "X-Config-Token"
+
+	certName   = "tls.crt"
+	keyName    = "tls.key"
+	tokenBytes = 32
+)
+
+var notStartedError = errors.New("accounts config server not started")
+var disabledError = errors.New("accounts config server disabled, use --accounts-config-server to enable it")
+
+var (
+	// Enabled is set with --accounts-config-server
+	Enabled bool
+	// CertsDir is the directory holding the certificate (tls.crt) and key (tls.key) of the config server
+	CertsDir string
+)
+
+// started is true once the config server was added to the manager
+var started bool
+
+// URL returns the URL Spinnaker services of the SpinnakerService use to read their accounts
+func URL(spinsvc interfaces.SpinnakerService) (string, error) {
+	if !started {
+		return "", notStartedError
+	}
+	ns, name, err := util.GetOperatorNamespaceAndName()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("https://%s.%s.svc:%d%s/%s/%s", serviceName(name), ns, Port, PathPrefix, spinsvc.GetNamespace(), spinsvc.GetName()), nil
+}
+
+// IsServed returns true if the service reads its accounts from the config server
+func IsServed(svc string) bool {
+	return util.IsServiceLike(svc, "clouddriver")
+}
+
+// TokenSecret returns the secret holding the token services of the SpinnakerService authenticate with. The token is
+// generated if the secret doesn't exist yet, and kept afterwards so that services aren't restarted.
+func TokenSecret(ctx context.Context, c client.Client, spinsvc interfaces.SpinnakerService) (*v1.Secret, error) {
+	sec := &v1.Secret{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: spinsvc.GetNamespace(), Name: TokenSecretName}, sec)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	token := sec.Data[TokenKey]
+	if len(token) == 0 {
+		b := make([]byte, tokenBytes)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		token = []byte(hex.EncodeToString(b))
+	}
+	return &v1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: TokenSecretName, Namespace: spinsvc.GetNamespace()},
+		Data:       map[string][]byte{TokenKey: token},
+	}, nil
+}
+
+// ClientSettings returns the settings for a service to import its accounts from the config server at url
+// and to reload the accounts of each account type. The token is mapped to an environment variable of the service.
+func ClientSettings(svc, url string) map[string]interface{} {
+	types := make(map[string]interface{})
+	for _, t := range accounts.Types {
+		if !accounts.RendersAccountsIn(t, svc) {
+			continue
+		}
+		k := t.GetAccountsKey()
+		if !strings.HasSuffix(k, ".accounts") {
+			continue
+		}
+		types[strings.TrimSuffix(k, ".accounts")] = map[string]interface{}{
+			"reloadFrequencyMs": RefreshIntervalSeconds * 1000,
+		}
+	}
+	return map[string]interface{}{
+		"spring": map[string]interface{}{
+			"config": map[string]interface{}{
+				"import": fmt.Sprintf("optional:configserver:%s", url),
+			},
+			"cloud": map[string]interface{}{
+				"config": map[string]interface{}{
+					"uri":   url,
+					"token": fmt.Sprintf("encrypted:k8s!n:%s!k:%s", TokenSecretName, TokenKey),
+				},
+			},
+		},
+		"cloud": map[string]interface{}{
+			"config": map[string]interface{}{
+				"refreshIntervalSeconds": RefreshIntervalSeconds,
+			},
+		},
+		"credentials": map[string]interface{}{
+			"poller": map[string]interface{}{
+				"enabled": true,
+				"types":   types,
+			},
+		},
+	}
+}
+
+func serviceName(operatorName string) string {
+	return fmt.Sprintf("%s-accounts", operatorName)
+}
@@ -0,0 +1,259 @@
+package configserver
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var log = logf.Log.WithName("accountsconfigserver")
+
+// Environment is the response of a Spring Cloud Config server
+type Environment struct {
+	Name            string           `json:"name"`
+	Profiles        []string         `json:"profiles"`
+	Label           *string          `json:"label"`
+	Version         string           `json:"version"`
+	State           *string          `json:"state"`
+	PropertySources []PropertySource `json:"propertySources"`
+}
+
+type PropertySource struct {
+	Name   string                 `json:"name"`
+	Source map[string]interface{} `json:"source"`
+}
+
+// Server serves the accounts of a SpinnakerService at /accounts/{namespace}/{name}/{application}/{profiles}[/{label}]
+type Server struct {
+	client     client.Client
+	restConfig *rest.Config
+	port       int
+	certsDir   string
+	log        logr.Logger
+}
+
+// Add creates the Kubernetes service exposing the config server and adds the server to the manager
+func Add(mgr manager.Manager) error {
+	if !Enabled {
+		return disabledError
+	}
+	for _, f := range []string{certName, keyName} {
+		if _, err := os.Stat(filepath.Join(CertsDir, f)); err != nil {
+			return fmt.Errorf("accounts config server requires a certificate in --accounts-config-server-certs-dir:\n  %w", err)
+		}
+	}
+	ns, name, err := util.GetOperatorNamespaceAndName()
+	if err != nil {
+		return err
+	}
+	rawClient := kubernetes.NewForConfigOrDie(mgr.GetConfig())
+	if err := deployService(ns, name, rawClient); err != nil {
+		return err
+	}
+	if err := mgr.Add(&Server{client: mgr.GetClient(), restConfig: mgr.GetConfig(), port: Port, certsDir: CertsDir, log: log}); err != nil {
+		return err
+	}
+	started = true
+	return nil
+}
+
+func deployService(ns, name string, rawClient *kubernetes.Clientset) error {
+	selectorLabels := map[string]string{"name": name}
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      serviceName(name),
+			Labels:    selectorLabels,
+		},
+		Spec: v1.ServiceSpec{
+			Selector: selectorLabels,
+			Ports: []v1.ServicePort{
+				{
+					Name:       "https",
+					Protocol:   "TCP",
+					Port:       Port,
+					TargetPort: intstr.FromInt(Port),
+				},
+			},
+		},
+	}
+	return util.CreateOrUpdateService(service, rawClient)
+}
+
+// Start serves requests until the context is done
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(PathPrefix+"/", s)
+	srv := &http.Server{Addr: fmt.Sprintf(":%d", s.port), Handler: mux}
+	go func() {
+		<-ctx.Done()
+		c, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(c); err != nil {
+			s.log.Error(err, "error shutting down accounts config server")
+		}
+	}()
+	s.log.Info(fmt.Sprintf("serving accounts on port %d", s.port))
+	if err := srv.ListenAndServeTLS(filepath.Join(s.certsDir, certName), filepath.Join(s.certsDir, keyName)); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, PathPrefix), "/"), "/")
+	if len(parts) < 4 || len(parts) > 5 {
+		http.Error(w, fmt.Sprintf("expected path %s/{namespace}/{name}/{application}/{profiles}[/{label}]", PathPrefix), http.StatusNotFound)
+		return
+	}
+	ns, name, app, profiles := parts[0], parts[1], parts[2], parts[3]
+	if !s.authenticate(r.Context(), r, ns) {
+		http.Error(w, "invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := secrets.NewContext(r.Context(), s.restConfig, ns)
+	defer secrets.Cleanup(ctx)
+
+	env, err := s.getEnvironment(ctx, ns, name, app, strings.Split(profiles, ","))
+	if err != nil {
+		if errors.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		s.log.Error(err, "error serving accounts", "namespace", ns, "name", name, "application", app)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	b, err := json.Marshal(env)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+// authenticate checks the token sent by the service against the token of the SpinnakerService namespace, so that
+// services only read accounts of SpinnakerServices of their namespace
+func (s *Server) authenticate(ctx context.Context, r *http.Request, ns string) bool {
+	token := r.Header.Get(TokenHeader)
+	if token == "" {
+		return false
+	}
+	sec := &v1.Secret{}
+	if err := s.client.Get(ctx, client.ObjectKey{Namespace: ns, Name: TokenSecretName}, sec); err != nil {
+		return false
+	}
+	expected := sec.Data[TokenKey]
+	return len(expected) > 0 && subtle.ConstantTimeCompare([]byte(token), expected) == 1
+}
+
+// getEnvironment returns the accounts of the application in the given SpinnakerService.
+// No property is returned if dynamic accounts are disabled. Serving accounts doesn't update them.
+func (s *Server) getEnvironment(ctx context.Context, ns, name, app string, profiles []string) (*Environment, error) {
+	spinsvc := accounts.TypesFactory.NewService()
+	if err := s.client.Get(ctx, client.ObjectKey{Namespace: ns, Name: name}, spinsvc); err != nil {
+		return nil, err
+	}
+	props := make(map[string]interface{})
+	if cfg := spinsvc.GetAccountConfig(); cfg.Enabled && cfg.Dynamic {
+		accs, err := accounts.IncludedCRDAccounts(ctx, s.client, spinsvc)
+		if err != nil {
+			return nil, err
+		}
+		settings, err := accounts.PrepareSettings(ctx, app, accs)
+		if err != nil {
+			return nil, err
+		}
+		if settings, err = accounts.DecodeSecrets(ctx, settings); err != nil {
+			return nil, err
+		}
+		if props, err = flatten(settings); err != nil {
+			return nil, err
+		}
+	}
+	v, err := hash(props)
+	if err != nil {
+		return nil, err
+	}
+	return &Environment{
+		Name:     app,
+		Profiles: profiles,
+		Version:  v,
+		PropertySources: []PropertySource{
+			{Name: fmt.Sprintf("spinnakeraccounts:%s/%s", ns, name), Source: props},
+		},
+	}, nil
+}
+
+// flatten converts settings to Spring properties, e.g. {"a": {"b": [1]}} to {"a.b[0]": 1}
+func flatten(settings map[string]interface{}) (map[string]interface{}, error) {
+	// Normalize settings to maps and slices of interface{}
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	props := make(map[string]interface{})
+	flattenValue("", m, props)
+	return props, nil
+}
+
+func flattenValue(prefix string, v interface{}, props map[string]interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if prefix == "" {
+				flattenValue(k, val, props)
+			} else {
+				flattenValue(fmt.Sprintf("%s.%s", prefix, k), val, props)
+			}
+		}
+	case []interface{}:
+		for i, val := range t {
+			flattenValue(fmt.Sprintf("%s[%d]", prefix, i), val, props)
+		}
+	default:
+		props[prefix] = v
+	}
+}
+
+// hash returns a hash of the properties, used as the version of the environment
+func hash(props map[string]interface{}) (string, error) {
+	data, err := json.Marshal(props)
+	if err != nil {
+		return "", err
+	}
+	m := md5.Sum(data)
+	return hex.EncodeToString(m[:]), nil
+}
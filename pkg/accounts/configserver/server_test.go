@@ -0,0 +1,212 @@
+package configserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	accounts.TypesFactory = test.TypesFactory
+}
+
+func newServer(t *testing.T, objs ...client.Object) *Server {
+	s := runtime.NewScheme()
+	if err := v1alpha2.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+	return &Server{client: c, log: log}
+}
+
+func newSpinnakerService(dynamic bool) *v1alpha2.SpinnakerService {
+	return &v1alpha2.SpinnakerService{
+		ObjectMeta: metav1.ObjectMeta{Name: "spinnaker", Namespace: "ns1"},
+		Spec:       interfaces.SpinnakerServiceSpec{Accounts: interfaces.AccountConfig{Enabled: true, Dynamic: dynamic}},
+	}
+}
+
+func newTokenSecret(ns, token string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: TokenSecretName, Namespace: ns},
+		Data:       map[string][]byte{TokenKey: []byte(token)},
+	}
+}
+
+func newKubernetesAccount(name string) *v1alpha2.SpinnakerAccount {
+	return &v1alpha2.SpinnakerAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1"},
+		Spec: interfaces.SpinnakerAccountSpec{
+			Type:       interfaces.KubernetesAccountType,
+			Enabled:    true,
+			Kubernetes: &interfaces.KubernetesAuth{KubeconfigFile: "/tmp/kubeconfig.yml"},
+		},
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		token    string
+		objs     []client.Object
+		status   int
+		expected map[string]interface{}
+	}{
+		{
+			name:   "accounts of the application",
+			path:   "/accounts/ns1/spinnaker/clouddriver/accounts,local",
+			token:  "secret",
+			objs:   []client.Object{newTokenSecret("ns1", "secret"), newSpinnakerService(true), newKubernetesAccount("kube1")},
+			status: http.StatusOK,
+			expected: map[string]interface{}{
+				"kubernetes.accounts[0].name":            "kube1",
+				"kubernetes.accounts[0].kubeconfigFile":  "/tmp/kubeconfig.yml",
+				"kubernetes.accounts[0].providerVersion": "V2",
+			},
+		},
+		{
+			name:     "no accounts if not dynamic",
+			path:     "/accounts/ns1/spinnaker/clouddriver/default/master",
+			token:    "secret",
+			objs:     []client.Object{newTokenSecret("ns1", "secret"), newSpinnakerService(false), newKubernetesAccount("kube1")},
+			status:   http.StatusOK,
+			expected: map[string]interface{}{},
+		},
+		{
+			name:   "unknown SpinnakerService",
+			path:   "/accounts/ns1/other/clouddriver/default",
+			token:  "secret",
+			objs:   []client.Object{newTokenSecret("ns1", "secret"), newSpinnakerService(true)},
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "missing token",
+			path:   "/accounts/ns1/spinnaker/clouddriver/default",
+			objs:   []client.Object{newTokenSecret("ns1", "secret"), newSpinnakerService(true), newKubernetesAccount("kube1")},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "token of another namespace",
+			path:   "/accounts/ns1/spinnaker/clouddriver/default",
+			token:  "other",
+			objs:   []client.Object{newTokenSecret("ns1", "secret"), newTokenSecret("ns2", "other"), newSpinnakerService(true), newKubernetesAccount("kube1")},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "no token secret",
+			path:   "/accounts/ns1/spinnaker/clouddriver/default",
+			token:  "secret",
+			objs:   []client.Object{newSpinnakerService(true), newKubernetesAccount("kube1")},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "invalid path",
+			path:   "/accounts/ns1/spinnaker",
+			status: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer(t, tt.objs...)
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set(TokenHeader, tt.token)
+			}
+			s.ServeHTTP(rec, req)
+			if !assert.Equal(t, tt.status, rec.Code) || tt.status != http.StatusOK {
+				return
+			}
+			env := &Environment{}
+			if assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), env)) && assert.Equal(t, 1, len(env.PropertySources)) {
+				assert.Equal(t, "clouddriver", env.Name)
+				assert.NotEmpty(t, env.Version)
+				assert.Equal(t, "spinnakeraccounts:ns1/spinnaker", env.PropertySources[0].Name)
+				assert.Equal(t, tt.expected, env.PropertySources[0].Source)
+			}
+		})
+	}
+}
+
+func TestServeHTTPReadOnly(t *testing.T) {
+	// ECS account without its AWS account is excluded
+	ecs := &v1alpha2.SpinnakerAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "ecs1", Namespace: "ns1"},
+		Spec:       interfaces.SpinnakerAccountSpec{Type: interfaces.ECSAccountType, Enabled: true, ECS: &interfaces.ECSAccount{AwsAccount: "aws1"}},
+	}
+	s := newServer(t, newTokenSecret("ns1", "secret"), newSpinnakerService(true), newKubernetesAccount("kube1"), ecs)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/accounts/ns1/spinnaker/clouddriver/default", nil)
+	req.Header.Set(TokenHeader, "secret")
+	s.ServeHTTP(rec, req)
+	if !assert.Equal(t, http.StatusOK, rec.Code) {
+		return
+	}
+	a := &v1alpha2.SpinnakerAccount{}
+	if assert.Nil(t, s.client.Get(context.TODO(), client.ObjectKey{Namespace: "ns1", Name: "ecs1"}, a)) {
+		assert.Equal(t, "", a.Status.ExcludedReason)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	m, err := flatten(map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": []map[string]interface{}{{"c": "d", "e": []string{"f", "g"}}},
+			"h": true,
+		},
+	})
+	if assert.Nil(t, err) {
+		assert.Equal(t, map[string]interface{}{
+			"a.b[0].c":    "d",
+			"a.b[0].e[0]": "f",
+			"a.b[0].e[1]": "g",
+			"a.h":         true,
+		}, m)
+	}
+}
+
+func TestClientSettings(t *testing.T) {
+	s := ClientSettings("clouddriver", "http://operator")
+	assert.Equal(t, "optional:configserver:http://operator", s["spring"].(map[string]interface{})["config"].(map[string]interface{})["import"])
+	cloudConfig := s["spring"].(map[string]interface{})["cloud"].(map[string]interface{})["config"].(map[string]interface{})
+	assert.Equal(t, "encrypted:k8s!n:spin-accounts-config!k:token", cloudConfig["token"])
+	types := s["credentials"].(map[string]interface{})["poller"].(map[string]interface{})["types"].(map[string]interface{})
+	assert.Contains(t, types, "kubernetes")
+	assert.Contains(t, types, "aws")
+	assert.NotContains(t, types, "slack")
+}
+
+func TestTokenSecret(t *testing.T) {
+	spinsvc := newSpinnakerService(true)
+	s := newServer(t)
+	sec, err := TokenSecret(context.TODO(), s.client, spinsvc)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "ns1", sec.Namespace)
+	assert.Equal(t, 2*tokenBytes, len(sec.Data[TokenKey]))
+
+	// existing tokens are kept
+	s = newServer(t, newTokenSecret("ns1", "secret"))
+	sec, err = TokenSecret(context.TODO(), s.client, spinsvc)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "secret", string(sec.Data[TokenKey]))
+	}
+}
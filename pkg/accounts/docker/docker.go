@@ -0,0 +1,102 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"strings"
+)
+
+// Docker registry accounts are read from the `dockerRegistry` section of the SpinnakerAccount or from Spinnaker settings.
+// Credentials can be read from a Kubernetes secret holding the password or a dockerconfigjson. They can also
+// be passed in settings as `password` (possibly as an encrypted secret).
+const (
+	AddressSettings  = "address"
+	UsernameSettings = "username"
+	PasswordSettings = "password"
+	EmailSettings    = "email"
+)
+
+var (
+	noDockerRegistryDefinedError = errors.New("dockerRegistry needs to be defined")
+	noAddressError               = errors.New("docker registry address is required")
+)
+
+// validationKeys are the keys under which docker validation can be configured in spec.validation.providers
+var validationKeys = []string{"docker", strings.ToLower(string(interfaces.DockerRegistryAccountType))}
+
+type AccountType struct{}
+
+func (k *AccountType) GetType() interfaces.AccountType {
+	return interfaces.DockerRegistryAccountType
+}
+
+func (k *AccountType) GetAccountsKey() string {
+	return "dockerRegistry.accounts"
+}
+
+func (k *AccountType) GetConfigAccountsKey() string {
+	return "providers.dockerRegistry.accounts"
+}
+
+func (k *AccountType) GetServices() []string {
+	return []string{"clouddriver"}
+}
+
+func (k *AccountType) GetPrimaryAccountsKey() string {
+	return "providers.dockerRegistry.primaryAccount"
+}
+
+func (k *AccountType) newAccount() *Account {
+	return &Account{
+		DockerRegistry: interfaces.DockerRegistryAccount{},
+	}
+}
+
+func (k *AccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	v := spinsvc.GetSpinnakerValidation()
+	for n, s := range v.Providers {
+		for _, key := range validationKeys {
+			if strings.ToLower(n) == key {
+				return &s
+			}
+		}
+	}
+	return v.GetValidationSettings()
+}
+
+type Account struct {
+	*account.BaseAccount
+	Name           string                           `json:"name,omitempty"`
+	DockerRegistry interfaces.DockerRegistryAccount `json:"dockerRegistry,omitempty"`
+	Settings       interfaces.FreeForm              `json:"settings,omitempty"`
+}
+
+func (k *Account) GetType() interfaces.AccountType {
+	return interfaces.DockerRegistryAccountType
+}
+
+func (k *Account) GetName() string {
+	return k.Name
+}
+
+func (k *Account) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *Account) NewValidator() account.AccountValidator {
+	return &dockerRegistryAccountValidator{account: k}
+}
+
+// getAddress returns the registry URL, insecure registries are reached over http
+func (k *Account) getAddress() string {
+	a := k.DockerRegistry.Address
+	if strings.HasPrefix(a, "https://") || strings.HasPrefix(a, "http://") {
+		return a
+	}
+	if k.DockerRegistry.InsecureRegistry {
+		return fmt.Sprintf("http://%s", a)
+	}
+	return fmt.Sprintf("https://%s", a)
+}
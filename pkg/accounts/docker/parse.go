@@ -0,0 +1,174 @@
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/mitchellh/mapstructure"
+	"strings"
+)
+
+func (k *AccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := k.newAccount()
+	a.Name = account.GetName()
+	a.Settings = account.GetSpec().Settings
+	if account.GetSpec().DockerRegistry == nil {
+		return nil, noDockerRegistryDefinedError
+	}
+	account.GetSpec().DockerRegistry.DeepCopyInto(&a.DockerRegistry)
+	return a, nil
+}
+
+func (k *AccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	a := k.newAccount()
+	n, ok := settings["name"]
+	if !ok {
+		return nil, fmt.Errorf("%s account missing name", a.GetType())
+	}
+	if name, ok := n.(string); ok {
+		a.Name = name
+	} else {
+		return nil, fmt.Errorf("name is not a string")
+	}
+	if err := mapstructure.Decode(settings, &a.DockerRegistry); err != nil {
+		return nil, fmt.Errorf("Error reading docker registry settings for account \"%s\":\n  %w", a.Name, err)
+	}
+	a.Settings = settings
+	return a, nil
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
+func (k *Account) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	d := k.DockerRegistry
+	if d.Address == "" {
+		return nil, noAddressError
+	}
+	m[AddressSettings] = d.Address
+	if d.DockerconfigSecret != nil {
+		// Credentials of the registry are extracted from the dockerconfig
+		username, password, err := k.getSecretCredentials(ctx)
+		if err != nil {
+			return nil, err
+		}
+		m[UsernameSettings] = username
+		m[PasswordSettings] = password
+	} else if d.PasswordSecret != nil {
+		m[UsernameSettings] = d.Username
+		m[PasswordSettings] = account.SecretReference(d.PasswordSecret)
+	} else if d.Username != "" {
+		m[UsernameSettings] = d.Username
+	}
+	if d.Email != "" {
+		m[EmailSettings] = d.Email
+	}
+	if len(d.Repositories) > 0 {
+		m["repositories"] = d.Repositories
+	}
+	if d.CacheIntervalSeconds != 0 {
+		m["cacheIntervalSeconds"] = d.CacheIntervalSeconds
+	}
+	if d.CacheThreads != 0 {
+		m["cacheThreads"] = d.CacheThreads
+	}
+	if d.ClientTimeoutMillis != 0 {
+		m["clientTimeoutMillis"] = d.ClientTimeoutMillis
+	}
+	if d.PaginateSize != 0 {
+		m["paginateSize"] = d.PaginateSize
+	}
+	if d.SortTagsByDate {
+		m["sortTagsByDate"] = true
+	}
+	if d.TrackDigests {
+		m["trackDigests"] = true
+	}
+	if d.InsecureRegistry {
+		m["insecureRegistry"] = true
+	}
+	return m, nil
+}
+
+// getCredentials returns the username and password to authenticate to the registry.
+// Passwords passed in settings are decoded if they're secret references.
+func (k *Account) getCredentials(ctx context.Context) (string, string, error) {
+	d := k.DockerRegistry
+	if d.PasswordSecret != nil || d.DockerconfigSecret != nil {
+		return k.getSecretCredentials(ctx)
+	}
+	p, ok := k.Settings[PasswordSettings].(string)
+	if !ok || p == "" {
+		return d.Username, "", nil
+	}
+	password, err := account.DecodeSecret(ctx, p)
+	if err != nil {
+		return "", "", fmt.Errorf("Error decoding password of docker registry account \"%s\":\n  %w", k.Name, err)
+	}
+	return d.Username, password, nil
+}
+
+// getSecretCredentials reads credentials from the Kubernetes secrets referenced by the account
+func (k *Account) getSecretCredentials(ctx context.Context) (string, string, error) {
+	d := k.DockerRegistry
+	if d.DockerconfigSecret != nil {
+		c, err := account.ReadSecret(ctx, d.DockerconfigSecret)
+		if err != nil {
+			return "", "", err
+		}
+		return credentialsFromDockerconfig([]byte(c), d.Address)
+	}
+	password, err := account.ReadSecret(ctx, d.PasswordSecret)
+	if err != nil {
+		return "", "", err
+	}
+	return d.Username, password, nil
+}
+
+type dockerconfig struct {
+	Auths map[string]dockerconfigAuth `json:"auths"`
+}
+
+type dockerconfigAuth struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// credentialsFromDockerconfig finds the credentials matching the registry address in a dockerconfigjson
+func credentialsFromDockerconfig(content []byte, address string) (string, string, error) {
+	cfg := dockerconfig{}
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return "", "", fmt.Errorf("unable to parse dockerconfigjson:\n  %w", err)
+	}
+	host := registryHost(address)
+	for k, a := range cfg.Auths {
+		if registryHost(k) != host {
+			continue
+		}
+		if a.Username != "" || a.Password != "" {
+			return a.Username, a.Password, nil
+		}
+		b, err := base64.StdEncoding.DecodeString(a.Auth)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to decode auth of registry %s in dockerconfigjson:\n  %w", k, err)
+		}
+		p := strings.SplitN(string(b), ":", 2)
+		if len(p) != 2 {
+			return "", "", fmt.Errorf("auth of registry %s in dockerconfigjson is not in the username:password format", k)
+		}
+		return p[0], p[1], nil
+	}
+	return "", "", fmt.Errorf("no credentials found for registry %s in dockerconfigjson", host)
+}
+
+// registryHost returns the host of a registry address (e.g. https://index.docker.io/v1/ -> index.docker.io)
+func registryHost(address string) string {
+	h := strings.TrimPrefix(strings.TrimPrefix(address, "https://"), "http://")
+	if i := strings.Index(h, "/"); i > -1 {
+		h = h[:i]
+	}
+	return h
+}
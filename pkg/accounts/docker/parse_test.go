@@ -0,0 +1,172 @@
+package docker
+
+import (
+	"context"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+	"testing"
+)
+
+func TestFromCRD(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expected func(t *testing.T, a account.Account, err error)
+	}{
+		{
+			name: "no dockerRegistry section in CRD",
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: DockerRegistry
+`,
+			expected: func(t *testing.T, _ account.Account, err error) {
+				assert.Equal(t, noDockerRegistryDefinedError, err)
+			},
+		},
+		{
+			name: "full docker registry config",
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: DockerRegistry
+  dockerRegistry:
+    address: index.docker.io
+    username: user
+    passwordSecret:
+      name: registry-secret
+      key: password
+    repositories:
+    - armory/spinnaker-operator
+    cacheIntervalSeconds: 60
+    trackDigests: true
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if !assert.Nil(t, err) {
+					return
+				}
+				d := a.(*Account)
+				assert.Equal(t, "index.docker.io", d.DockerRegistry.Address)
+				assert.Equal(t, "registry-secret", d.DockerRegistry.PasswordSecret.Name)
+				assert.Equal(t, []string{"armory/spinnaker-operator"}, d.DockerRegistry.Repositories)
+				assert.Equal(t, int64(60), d.DockerRegistry.CacheIntervalSeconds)
+				assert.True(t, d.DockerRegistry.TrackDigests)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sa := test.TypesFactory.NewAccount()
+			if !assert.Nil(t, yaml.Unmarshal([]byte(tt.manifest), sa)) {
+				return
+			}
+			k := &AccountType{}
+			a, err := k.FromCRD(sa)
+			tt.expected(t, a, err)
+		})
+	}
+}
+
+func TestFromSpinnakerSettings(t *testing.T) {
+	k := &AccountType{}
+	a, err := k.FromSpinnakerConfig(context.TODO(), map[string]interface{}{
+		"name":         "dockerhub",
+		"address":      "index.docker.io",
+		"username":     "user",
+		"password":     "encrypted:noop!pass",
+		"repositories": []interface{}{"library/nginx"},
+		"cacheThreads": float64(2),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	d := a.(*Account)
+	assert.Equal(t, "index.docker.io", d.DockerRegistry.Address)
+	assert.Equal(t, "user", d.DockerRegistry.Username)
+	assert.Equal(t, []string{"library/nginx"}, d.DockerRegistry.Repositories)
+	assert.Equal(t, int32(2), d.DockerRegistry.CacheThreads)
+
+	// Passwords in settings are passed as is
+	ss, err := d.ToSpinnakerSettings(context.TODO())
+	if assert.Nil(t, err) {
+		assert.Equal(t, "encrypted:noop!pass", ss[PasswordSettings])
+	}
+	// but are decoded to validate
+	ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+	defer secrets.Cleanup(ctx)
+	u, p, err := d.getCredentials(ctx)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "user", u)
+		assert.Equal(t, "pass", p)
+	}
+}
+
+func TestToSpinnakerSettings(t *testing.T) {
+	a := &Account{
+		Name: "registry",
+		DockerRegistry: interfaces.DockerRegistryAccount{
+			Address:          "my.registry.io",
+			Username:         "user",
+			Repositories:     []string{"team/app"},
+			PaginateSize:     50,
+			InsecureRegistry: true,
+		},
+		Settings: interfaces.FreeForm{},
+	}
+	ss, err := a.ToSpinnakerSettings(context.TODO())
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "registry", ss["name"])
+	assert.Equal(t, "my.registry.io", ss[AddressSettings])
+	assert.Equal(t, "user", ss[UsernameSettings])
+	assert.Nil(t, ss[PasswordSettings])
+	assert.Equal(t, []string{"team/app"}, ss["repositories"])
+	assert.Equal(t, int32(50), ss["paginateSize"])
+	assert.Equal(t, true, ss["insecureRegistry"])
+	assert.Nil(t, ss["trackDigests"])
+	assert.Equal(t, "http://my.registry.io", a.getAddress())
+}
+
+func TestCredentialsFromDockerconfig(t *testing.T) {
+	cfg := `{
+  "auths": {
+    "https://index.docker.io/v1/": {"auth": "dXNlcjE6cGFzczE="},
+    "my.registry.io": {"username": "user2", "password": "pass2"}
+  }
+}`
+	tests := []struct {
+		address  string
+		username string
+		password string
+		err      bool
+	}{
+		{address: "index.docker.io", username: "user1", password: "pass1"},
+		{address: "https://my.registry.io", username: "user2", password: "pass2"},
+		{address: "other.registry.io", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			u, p, err := credentialsFromDockerconfig([]byte(cfg), tt.address)
+			if tt.err {
+				assert.NotNil(t, err)
+				return
+			}
+			if assert.Nil(t, err) {
+				assert.Equal(t, tt.username, u)
+				assert.Equal(t, tt.password, p)
+			}
+		})
+	}
+}
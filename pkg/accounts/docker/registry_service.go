@@ -1,4 +1,4 @@
-package validate
+package docker
 
 import (
 	"context"
@@ -11,7 +11,8 @@ import (
 	"strings"
 )
 
-type dockerRegistryService struct {
+// RegistryService queries a Docker registry using the V2 API
+type RegistryService struct {
 	address  string
 	username string
 	password string
@@ -20,14 +21,24 @@ type dockerRegistryService struct {
 	httpService util.HttpService
 }
 
-func (s *dockerRegistryService) GetBase() (bool, error) {
+func NewRegistryService(ctx context.Context, address, username, password string) *RegistryService {
+	return &RegistryService{
+		address:     address,
+		username:    username,
+		password:    password,
+		ctx:         ctx,
+		httpService: util.HttpService{},
+	}
+}
+
+func (s *RegistryService) GetBase() (bool, error) {
 	if _, err := s.client("/v2/", nil); err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-func (s *dockerRegistryService) GetTagsCount(image string) (int, error) {
+func (s *RegistryService) GetTagsCount(image string) (int, error) {
 	// Pagination is not working currently, It'll work once https://github.com/docker/distribution/pull/3143 be merged
 	params := make(map[string]string)
 	params["n"] = "1"
@@ -44,11 +55,14 @@ func (s *dockerRegistryService) GetTagsCount(image string) (int, error) {
 		return 0, err
 	}
 
-	tags := body["tags"].([]interface{})
+	tags, ok := body["tags"].([]interface{})
+	if !ok {
+		return 0, nil
+	}
 	return len(tags), nil
 }
 
-func (s *dockerRegistryService) client(path string, params map[string]string) (*http.Response, error) {
+func (s *RegistryService) client(path string, params map[string]string) (*http.Response, error) {
 	url := fmt.Sprintf("%s%s", s.address, path)
 
 	headers := make(map[string]string)
@@ -102,7 +116,7 @@ func (s *dockerRegistryService) client(path string, params map[string]string) (*
 /*
  * Implements token request flow described here https://docs.docker.com/registry/spec/auth/token/
  */
-func (s *dockerRegistryService) requestToken(authenticateDetails map[string]string) (string, error) {
+func (s *RegistryService) requestToken(authenticateDetails map[string]string) (string, error) {
 	headers := make(map[string]string)
 	requestParams := make(map[string]string)
 
@@ -159,7 +173,7 @@ func basicAuth(username, password string) string {
 }
 
 // This function parses the Www-Authenticate header provided in the challenge
-func (s *dockerRegistryService) parseBearerAuthenticateHeader(bearer []string) map[string]string {
+func (s *RegistryService) parseBearerAuthenticateHeader(bearer []string) map[string]string {
 	out := make(map[string]string)
 	for _, b := range bearer {
 		for _, s := range strings.Split(b, " ") {
@@ -1,4 +1,4 @@
-package validate
+package docker
 
 import (
 	"context"
@@ -7,7 +7,7 @@ import (
 	"testing"
 )
 
-func Test_dockerRegistryService_requestToken(t *testing.T) {
+func Test_RegistryService_requestToken(t *testing.T) {
 	type fields struct {
 		address     string
 		username    string
@@ -45,7 +45,7 @@ func Test_dockerRegistryService_requestToken(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := &dockerRegistryService{
+			s := &RegistryService{
 				address:     tt.fields.address,
 				username:    tt.fields.username,
 				password:    tt.fields.password,
@@ -0,0 +1,58 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var namePattern = regexp.MustCompile("^[a-z0-9]+([-a-z0-9]*[a-z0-9])?$")
+
+type dockerRegistryAccountValidator struct {
+	account *Account
+}
+
+func (d *dockerRegistryAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	if !namePattern.MatchString(d.account.Name) {
+		return fmt.Errorf("docker registry account name \"%s\" must match pattern %s", d.account.Name, namePattern.String())
+	}
+	if d.account.DockerRegistry.Address == "" {
+		return noAddressError
+	}
+	username, password, err := d.account.getCredentials(ctx)
+	if err != nil {
+		return err
+	}
+	if username != "" && password == "" {
+		return fmt.Errorf("docker registry account \"%s\" has a username but no password", d.account.Name)
+	}
+	if username == "" && password != "" {
+		return fmt.Errorf("docker registry account \"%s\" has a password but no username", d.account.Name)
+	}
+
+	service := NewRegistryService(ctx, d.account.getAddress(), username, password)
+	if username != "" {
+		if _, err := service.GetBase(); err != nil {
+			return fmt.Errorf("registry check failed for docker registry account \"%s\":\n  %w", d.account.Name, err)
+		}
+	}
+	return d.validateRepositories(service)
+}
+
+// validateRepositories checks that each repository has at least one tag
+func (d *dockerRegistryAccountValidator) validateRepositories(service *RegistryService) error {
+	for _, r := range d.account.DockerRegistry.Repositories {
+		count, err := service.GetTagsCount(r)
+		if err != nil {
+			return fmt.Errorf("tag check failed for repository \"%s\" in docker registry account \"%s\":\n  %w", r, d.account.Name, err)
+		}
+		if count == 0 {
+			return fmt.Errorf("tag check failed for repository \"%s\" in docker registry account \"%s\": no tags found", r, d.account.Name)
+		}
+	}
+	return nil
+}
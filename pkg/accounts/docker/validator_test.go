@@ -0,0 +1,104 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/stretchr/testify/assert"
+	logr "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func newRegistryServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			if u, p, ok := r.BasicAuth(); !ok || u != "user" || p != "pass" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, "{}")
+		case "/v2/team/app/tags/list":
+			fmt.Fprint(w, `{"name": "team/app", "tags": ["1.0.0"]}`)
+		case "/v2/team/empty/tags/list":
+			fmt.Fprint(w, `{"name": "team/empty", "tags": []}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestValidate(t *testing.T) {
+	s := newRegistryServer(t)
+	defer s.Close()
+
+	tests := []struct {
+		name     string
+		account  *Account
+		expected func(t *testing.T, err error)
+	}{
+		{
+			name: "valid anonymous registry",
+			account: &Account{
+				Name:           "registry",
+				DockerRegistry: interfaces.DockerRegistryAccount{Address: s.URL, Repositories: []string{"team/app"}},
+			},
+			expected: func(t *testing.T, err error) {
+				assert.Nil(t, err)
+			},
+		},
+		{
+			name: "repository without tags",
+			account: &Account{
+				Name:           "registry",
+				DockerRegistry: interfaces.DockerRegistryAccount{Address: s.URL, Repositories: []string{"team/empty"}},
+			},
+			expected: func(t *testing.T, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "tag check failed")
+				}
+			},
+		},
+		{
+			name: "unknown repository",
+			account: &Account{
+				Name:           "registry",
+				DockerRegistry: interfaces.DockerRegistryAccount{Address: s.URL, Repositories: []string{"team/unknown"}},
+			},
+			expected: func(t *testing.T, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			name: "username without password",
+			account: &Account{
+				Name:           "registry",
+				DockerRegistry: interfaces.DockerRegistryAccount{Address: s.URL, Username: "user"},
+			},
+			expected: func(t *testing.T, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			name: "invalid account name",
+			account: &Account{
+				Name:           "Registry.1",
+				DockerRegistry: interfaces.DockerRegistryAccount{Address: s.URL},
+			},
+			expected: func(t *testing.T, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tt.account.NewValidator()
+			err := v.Validate(nil, nil, context.TODO(), logr.Log)
+			tt.expected(t, err)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package ecs
+
+import (
+	"errors"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"strings"
+)
+
+// ECS accounts are read from the `ecs` section of the SpinnakerAccount or from Spinnaker settings.
+// They don't hold credentials but reference an AWS account by name.
+const (
+	AwsAccountSettings = "awsAccount"
+)
+
+// awsAccountsKey is where AWS accounts are defined in Spinnaker's config
+const awsAccountsKey = "providers.aws.accounts"
+
+var TypesFactory interfaces.TypesFactory
+
+var (
+	noECSDefinedError = errors.New("ecs needs to be defined")
+	noAwsAccountError = errors.New("ecs awsAccount is required")
+)
+
+type AccountType struct{}
+
+func (k *AccountType) GetType() interfaces.AccountType {
+	return interfaces.ECSAccountType
+}
+
+func (k *AccountType) GetAccountsKey() string {
+	return "ecs.accounts"
+}
+
+func (k *AccountType) GetConfigAccountsKey() string {
+	return "providers.ecs.accounts"
+}
+
+func (k *AccountType) GetServices() []string {
+	return []string{"clouddriver"}
+}
+
+func (k *AccountType) GetPrimaryAccountsKey() string {
+	return "providers.ecs.primaryAccount"
+}
+
+func (k *AccountType) newAccount() *Account {
+	return &Account{
+		ECS: interfaces.ECSAccount{},
+	}
+}
+
+func (k *AccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	v := spinsvc.GetSpinnakerValidation()
+	for n, s := range v.Providers {
+		if strings.ToLower(n) == strings.ToLower(string(interfaces.ECSAccountType)) {
+			return &s
+		}
+	}
+	return v.GetValidationSettings()
+}
+
+type Account struct {
+	*account.BaseAccount
+	Name string `json:"name,omitempty"`
+	// Namespace of the SpinnakerAccount, empty if the account is read from Spinnaker settings
+	Namespace string                `json:"-"`
+	ECS       interfaces.ECSAccount `json:"ecs,omitempty"`
+	Settings  interfaces.FreeForm   `json:"settings,omitempty"`
+}
+
+func (k *Account) GetType() interfaces.AccountType {
+	return interfaces.ECSAccountType
+}
+
+func (k *Account) GetName() string {
+	return k.Name
+}
+
+func (k *Account) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *Account) NewValidator() account.AccountValidator {
+	return &ecsAccountValidator{account: k}
+}
+
+// GetDependencies returns the AWS account referenced by the ECS account
+func (k *Account) GetDependencies() []account.Dependency {
+	if k.ECS.AwsAccount == "" {
+		return nil
+	}
+	return []account.Dependency{{Type: interfaces.AWSAccountType, Name: k.ECS.AwsAccount}}
+}
@@ -0,0 +1,49 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/mitchellh/mapstructure"
+)
+
+func (k *AccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := k.newAccount()
+	a.Name = account.GetName()
+	a.Namespace = account.GetNamespace()
+	a.Settings = account.GetSpec().Settings
+	if account.GetSpec().ECS == nil {
+		return nil, noECSDefinedError
+	}
+	account.GetSpec().ECS.DeepCopyInto(&a.ECS)
+	return a, nil
+}
+
+func (k *AccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	a := k.newAccount()
+	n, ok := settings["name"]
+	if !ok {
+		return nil, fmt.Errorf("%s account missing name", a.GetType())
+	}
+	if name, ok := n.(string); ok {
+		a.Name = name
+	} else {
+		return nil, fmt.Errorf("name is not a string")
+	}
+	if err := mapstructure.Decode(settings, &a.ECS); err != nil {
+		return nil, fmt.Errorf("Error reading ecs settings for account \"%s\":\n  %w", a.Name, err)
+	}
+	a.Settings = settings
+	return a, nil
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
+func (k *Account) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	if k.ECS.AwsAccount == "" {
+		return nil, noAwsAccountError
+	}
+	m[AwsAccountSettings] = k.ECS.AwsAccount
+	return m, nil
+}
@@ -0,0 +1,95 @@
+package ecs
+
+import (
+	"context"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+	"testing"
+)
+
+func init() {
+	TypesFactory = test.TypesFactory
+}
+
+func TestFromCRD(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expected func(t *testing.T, a account.Account, err error)
+	}{
+		{
+			name: "no ecs section in CRD",
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: ECS
+`,
+			expected: func(t *testing.T, _ account.Account, err error) {
+				assert.Equal(t, noECSDefinedError, err)
+			},
+		},
+		{
+			name: "ecs referencing aws account",
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+  namespace: ns1
+spec:
+  type: ECS
+  ecs:
+    awsAccount: aws1
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if !assert.Nil(t, err) {
+					return
+				}
+				e := a.(*Account)
+				assert.Equal(t, "aws1", e.ECS.AwsAccount)
+				assert.Equal(t, "ns1", e.Namespace)
+				assert.Equal(t, []account.Dependency{{Type: interfaces.AWSAccountType, Name: "aws1"}}, e.GetDependencies())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sa := TypesFactory.NewAccount()
+			if !assert.Nil(t, yaml.Unmarshal([]byte(tt.manifest), sa)) {
+				return
+			}
+			k := &AccountType{}
+			a, err := k.FromCRD(sa)
+			tt.expected(t, a, err)
+		})
+	}
+}
+
+func TestFromSpinnakerSettings(t *testing.T) {
+	k := &AccountType{}
+	a, err := k.FromSpinnakerConfig(context.TODO(), map[string]interface{}{
+		"name":       "ecs1",
+		"awsAccount": "aws1",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	ss, err := a.ToSpinnakerSettings(context.TODO())
+	if assert.Nil(t, err) {
+		assert.Equal(t, "ecs1", ss["name"])
+		assert.Equal(t, "aws1", ss[AwsAccountSettings])
+	}
+}
+
+func TestToSpinnakerSettingsWithoutAwsAccount(t *testing.T) {
+	a := &Account{Name: "ecs1"}
+	_, err := a.ToSpinnakerSettings(context.TODO())
+	assert.Equal(t, noAwsAccountError, err)
+}
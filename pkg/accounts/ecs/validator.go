@@ -0,0 +1,69 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type ecsAccountValidator struct {
+	account *Account
+}
+
+func (e *ecsAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	if e.account.ECS.AwsAccount == "" {
+		return noAwsAccountError
+	}
+	ns := e.account.Namespace
+	if ns == "" && spinSvc != nil {
+		ns = spinSvc.GetNamespace()
+	}
+	if c != nil && ns != "" {
+		found, err := e.validateAwsSpinnakerAccount(ctx, c, ns)
+		if err != nil || found {
+			return err
+		}
+	}
+	if spinSvc != nil && e.hasAwsConfigAccount(ctx, spinSvc) {
+		return nil
+	}
+	return fmt.Errorf("AWS account \"%s\" referenced by ecs account \"%s\" not found", e.account.ECS.AwsAccount, e.account.Name)
+}
+
+// validateAwsSpinnakerAccount looks up the referenced AWS account as a SpinnakerAccount.
+// It returns false if there's no SpinnakerAccount by that name.
+func (e *ecsAccountValidator) validateAwsSpinnakerAccount(ctx context.Context, c client.Client, ns string) (bool, error) {
+	name := e.account.ECS.AwsAccount
+	aws := TypesFactory.NewAccount()
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ns, Name: name}, aws); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if aws.GetSpec().Type != interfaces.AWSAccountType {
+		return true, fmt.Errorf("account \"%s\" referenced by ecs account \"%s\" is of type %s, not %s", name, e.account.Name, aws.GetSpec().Type, interfaces.AWSAccountType)
+	}
+	if !aws.GetSpec().Enabled {
+		return true, fmt.Errorf("AWS account \"%s\" referenced by ecs account \"%s\" is not enabled", name, e.account.Name)
+	}
+	return true, nil
+}
+
+// hasAwsConfigAccount checks if the referenced AWS account is defined in Spinnaker's config
+func (e *ecsAccountValidator) hasAwsConfigAccount(ctx context.Context, spinSvc interfaces.SpinnakerService) bool {
+	arr, err := spinSvc.GetSpinnakerConfig().GetHalConfigObjectArray(ctx, awsAccountsKey)
+	if err != nil {
+		return false
+	}
+	for _, a := range arr {
+		if a["name"] == e.account.ECS.AwsAccount {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logr "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func fakeClient(t *testing.T, objs ...client.Object) client.Client {
+	s := runtime.NewScheme()
+	if err := v1alpha2.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func spinnakerAccount(name string, tp interfaces.AccountType, enabled bool) *v1alpha2.SpinnakerAccount {
+	return &v1alpha2.SpinnakerAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1"},
+		Spec:       interfaces.SpinnakerAccountSpec{Type: tp, Enabled: enabled},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(`
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: test
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config:
+      providers:
+        aws:
+          accounts:
+          - name: aws-config
+            accountId: "123456789012"
+`, t)
+
+	tests := []struct {
+		name       string
+		awsAccount string
+		namespace  string
+		spinsvc    interfaces.SpinnakerService
+		objs       []client.Object
+		expected   string
+	}{
+		{
+			name:       "aws SpinnakerAccount enabled",
+			awsAccount: "aws1",
+			namespace:  "ns1",
+			objs:       []client.Object{spinnakerAccount("aws1", interfaces.AWSAccountType, true)},
+		},
+		{
+			name:       "aws SpinnakerAccount disabled",
+			awsAccount: "aws1",
+			namespace:  "ns1",
+			objs:       []client.Object{spinnakerAccount("aws1", interfaces.AWSAccountType, false)},
+			expected:   "is not enabled",
+		},
+		{
+			name:       "referenced SpinnakerAccount is not AWS",
+			awsAccount: "aws1",
+			namespace:  "ns1",
+			objs:       []client.Object{spinnakerAccount("aws1", interfaces.KubernetesAccountType, true)},
+			expected:   "is of type Kubernetes",
+		},
+		{
+			name:       "aws account missing",
+			awsAccount: "aws1",
+			namespace:  "ns1",
+			expected:   "not found",
+		},
+		{
+			name:       "aws account in Spinnaker config",
+			awsAccount: "aws-config",
+			spinsvc:    spinsvc,
+		},
+		{
+			name:     "no aws account",
+			expected: "awsAccount is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &ecsAccountValidator{account: &Account{
+				Name:      "ecs1",
+				Namespace: tt.namespace,
+				ECS:       interfaces.ECSAccount{AwsAccount: tt.awsAccount},
+			}}
+			err := v.Validate(tt.spinsvc, fakeClient(t, tt.objs...), context.TODO(), logr.Log)
+			if tt.expected == "" {
+				assert.Nil(t, err)
+			} else if assert.NotNil(t, err) {
+				assert.Contains(t, err.Error(), tt.expected)
+			}
+		})
+	}
+}
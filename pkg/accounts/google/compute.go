@@ -0,0 +1,46 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2"
+	googleoauth "golang.org/x/oauth2/google"
+)
+
+const (
+	computeScope   = "https://www.googleapis.com/auth/compute.readonly"
+	computeBaseUrl = "https://compute.googleapis.com/compute/v1"
+)
+
+// computeClient checks that a service account key has access to a project
+type computeClient interface {
+	GetProject(ctx context.Context, jsonKey []byte, project string) error
+}
+
+type computeClientImpl struct{}
+
+func (c *computeClientImpl) GetProject(ctx context.Context, jsonKey []byte, project string) error {
+	creds, err := googleoauth.CredentialsFromJSON(ctx, jsonKey, computeScope)
+	if err != nil {
+		return err
+	}
+	client := oauth2.NewClient(ctx, creds.TokenSource)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/projects/%s", computeBaseUrl, url.PathEscape(project)), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("compute API returned %d for project %s: %s", resp.StatusCode, project, string(b))
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package google
+
+import (
+	"errors"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"strings"
+)
+
+// Google accounts are read from the `google` section of the SpinnakerAccount or from Spinnaker settings.
+// The service account key referenced in a Kubernetes secret is passed to Clouddriver as an `encryptedFile:k8s!...`
+// reference in `jsonPath`: the file is mounted in Clouddriver's pods when Spinnaker is deployed.
+const (
+	ProjectSettings                 = "project"
+	JsonPathSettings                = "jsonPath"
+	RegionsSettings                 = "regions"
+	RequiredGroupMembershipSettings = "requiredGroupMembership"
+	ImageProjectsSettings           = "imageProjects"
+)
+
+var (
+	noGoogleDefinedError = errors.New("google needs to be defined")
+	noProjectError       = errors.New("google project is required")
+)
+
+type AccountType struct{}
+
+func (k *AccountType) GetType() interfaces.AccountType {
+	return interfaces.GoogleAccountType
+}
+
+func (k *AccountType) GetAccountsKey() string {
+	return "google.accounts"
+}
+
+func (k *AccountType) GetConfigAccountsKey() string {
+	return "providers.google.accounts"
+}
+
+func (k *AccountType) GetServices() []string {
+	return []string{"clouddriver"}
+}
+
+func (k *AccountType) GetPrimaryAccountsKey() string {
+	return "providers.google.primaryAccount"
+}
+
+func (k *AccountType) newAccount() *Account {
+	return &Account{
+		Google: interfaces.GoogleAccount{},
+	}
+}
+
+func (k *AccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	v := spinsvc.GetSpinnakerValidation()
+	for n, s := range v.Providers {
+		if strings.ToLower(n) == strings.ToLower(string(interfaces.GoogleAccountType)) {
+			return &s
+		}
+	}
+	return v.GetValidationSettings()
+}
+
+type Account struct {
+	*account.BaseAccount
+	Name     string                   `json:"name,omitempty"`
+	Google   interfaces.GoogleAccount `json:"google,omitempty"`
+	Settings interfaces.FreeForm      `json:"settings,omitempty"`
+}
+
+func (k *Account) GetType() interfaces.AccountType {
+	return interfaces.GoogleAccountType
+}
+
+func (k *Account) GetName() string {
+	return k.Name
+}
+
+func (k *Account) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *Account) NewValidator() account.AccountValidator {
+	return &googleAccountValidator{account: k, client: &computeClientImpl{}}
+}
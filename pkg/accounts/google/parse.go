@@ -0,0 +1,61 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/mitchellh/mapstructure"
+)
+
+func (k *AccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := k.newAccount()
+	a.Name = account.GetName()
+	a.Settings = account.GetSpec().Settings
+	if account.GetSpec().Google == nil {
+		return nil, noGoogleDefinedError
+	}
+	account.GetSpec().Google.DeepCopyInto(&a.Google)
+	return a, nil
+}
+
+func (k *AccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	a := k.newAccount()
+	n, ok := settings["name"]
+	if !ok {
+		return nil, fmt.Errorf("%s account missing name", a.GetType())
+	}
+	if name, ok := n.(string); ok {
+		a.Name = name
+	} else {
+		return nil, fmt.Errorf("name is not a string")
+	}
+	if err := mapstructure.Decode(settings, &a.Google); err != nil {
+		return nil, fmt.Errorf("Error reading google settings for account \"%s\":\n  %w", a.Name, err)
+	}
+	a.Settings = settings
+	return a, nil
+}
+
+// ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
+func (k *Account) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	g := k.Google
+	if g.Project == "" {
+		return nil, noProjectError
+	}
+	m[ProjectSettings] = g.Project
+	if g.JsonKeySecret != nil {
+		m[JsonPathSettings] = account.SecretFileReference(g.JsonKeySecret)
+	}
+	if len(g.Regions) > 0 {
+		m[RegionsSettings] = g.Regions
+	}
+	if len(g.RequiredGroupMembership) > 0 {
+		m[RequiredGroupMembershipSettings] = g.RequiredGroupMembership
+	}
+	if len(g.ImageProjects) > 0 {
+		m[ImageProjectsSettings] = g.ImageProjects
+	}
+	return m, nil
+}
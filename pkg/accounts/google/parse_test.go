@@ -0,0 +1,120 @@
+package google
+
+import (
+	"context"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+	"testing"
+)
+
+func TestFromCRD(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expected func(t *testing.T, a account.Account, err error)
+	}{
+		{
+			name: "no google section in CRD",
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: Google
+`,
+			expected: func(t *testing.T, _ account.Account, err error) {
+				assert.Equal(t, noGoogleDefinedError, err)
+			},
+		},
+		{
+			name: "full google config",
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: account1
+spec:
+  type: Google
+  google:
+    project: my-project
+    jsonKeySecret:
+      name: gce-secret
+      key: key.json
+    regions:
+    - us-central1
+    requiredGroupMembership:
+    - admins
+    imageProjects:
+    - ubuntu-os-cloud
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if !assert.Nil(t, err) {
+					return
+				}
+				g := a.(*Account)
+				assert.Equal(t, "my-project", g.Google.Project)
+				assert.Equal(t, "gce-secret", g.Google.JsonKeySecret.Name)
+				assert.Equal(t, []string{"us-central1"}, g.Google.Regions)
+				assert.Equal(t, []string{"admins"}, g.Google.RequiredGroupMembership)
+				assert.Equal(t, []string{"ubuntu-os-cloud"}, g.Google.ImageProjects)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sa := test.TypesFactory.NewAccount()
+			if !assert.Nil(t, yaml.Unmarshal([]byte(tt.manifest), sa)) {
+				return
+			}
+			k := &AccountType{}
+			a, err := k.FromCRD(sa)
+			tt.expected(t, a, err)
+		})
+	}
+}
+
+func TestFromSpinnakerSettings(t *testing.T) {
+	k := &AccountType{}
+	a, err := k.FromSpinnakerConfig(context.TODO(), map[string]interface{}{
+		"name":     "gce",
+		"project":  "my-project",
+		"jsonPath": "encryptedFile:s3!b:bucket!f:key.json",
+		"regions":  []interface{}{"us-central1", "us-east1"},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	g := a.(*Account)
+	assert.Equal(t, "my-project", g.Google.Project)
+	assert.Equal(t, []string{"us-central1", "us-east1"}, g.Google.Regions)
+
+	ss, err := g.ToSpinnakerSettings(context.TODO())
+	if assert.Nil(t, err) {
+		assert.Equal(t, "encryptedFile:s3!b:bucket!f:key.json", ss[JsonPathSettings])
+	}
+}
+
+func TestToSpinnakerSettings(t *testing.T) {
+	a := &Account{
+		Name: "gce",
+		Google: interfaces.GoogleAccount{
+			Project:       "my-project",
+			JsonKeySecret: &interfaces.SecretInNamespaceReference{Name: "gce-secret", Key: "key.json"},
+			ImageProjects: []string{"ubuntu-os-cloud"},
+		},
+	}
+	ss, err := a.ToSpinnakerSettings(context.TODO())
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "gce", ss["name"])
+	assert.Equal(t, "my-project", ss[ProjectSettings])
+	assert.Equal(t, "encryptedFile:k8s!n:gce-secret!k:key.json", ss[JsonPathSettings])
+	assert.Equal(t, []string{"ubuntu-os-cloud"}, ss[ImageProjectsSettings])
+	assert.Nil(t, ss[RegionsSettings])
+}
@@ -0,0 +1,62 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type googleAccountValidator struct {
+	account *Account
+	client  computeClient
+}
+
+type serviceAccountKey struct {
+	Type        string `json:"type"`
+	ProjectId   string `json:"project_id"`
+	PrivateKey  string `json:"private_key"`
+	ClientEmail string `json:"client_email"`
+}
+
+func (g *googleAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	if g.account.Google.Project == "" {
+		return noProjectError
+	}
+	key, err := account.ReadJsonKey(ctx, g.account.Google.JsonKeySecret, g.account.Settings, spinSvc)
+	if err != nil {
+		return fmt.Errorf("error reading service account key of account \"%s\":\n  %w", g.account.Name, err)
+	}
+	if key == nil {
+		// Clouddriver will use its default credentials, nothing more we can check
+		return nil
+	}
+	if err := validateJsonKey(key); err != nil {
+		return fmt.Errorf("invalid service account key in account \"%s\":\n  %w", g.account.Name, err)
+	}
+	if err := g.client.GetProject(ctx, key, g.account.Google.Project); err != nil {
+		return fmt.Errorf("error reading project \"%s\" in account \"%s\":\n  %w", g.account.Google.Project, g.account.Name, err)
+	}
+	return nil
+}
+
+func validateJsonKey(key []byte) error {
+	k := serviceAccountKey{}
+	if err := json.Unmarshal(key, &k); err != nil {
+		return fmt.Errorf("key is not valid JSON: %w", err)
+	}
+	if k.Type != "service_account" {
+		return fmt.Errorf("expected key of type \"service_account\", found \"%s\"", k.Type)
+	}
+	if k.ClientEmail == "" {
+		return fmt.Errorf("key is missing client_email")
+	}
+	if k.PrivateKey == "" {
+		return fmt.Errorf("key is missing private_key")
+	}
+	return nil
+}
@@ -73,10 +73,14 @@ type CustomKubernetesResource struct {
 
 type Account struct {
 	*account.BaseAccount
-	Name     string `json:"name,omitempty"`
-	Auth     *interfaces.KubernetesAuth
-	Env      Env                 `json:"env,omitempty"`
-	Settings interfaces.FreeForm `json:"settings,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Namespace of the SpinnakerAccount, where provisioned resources live
+	Namespace string `json:"-"`
+	Auth      *interfaces.KubernetesAuth
+	Env       Env                 `json:"env,omitempty"`
+	Settings  interfaces.FreeForm `json:"settings,omitempty"`
+	// Permissions of the SpinnakerAccount, permissions read from Spinnaker settings stay in Settings
+	Permissions interfaces.AccountPermissions `json:"permissions,omitempty"`
 }
 
 func (k *Account) GetType() interfaces.AccountType {
@@ -0,0 +1,100 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	yamlsecrets "github.com/armory/go-yaml-tools/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// MigratedKubeconfigKey is the key of the kubeconfig in secrets created when migrating accounts
+const MigratedKubeconfigKey = "kubeconfig"
+
+// Migrate converts a Kubernetes account of the halconfig to a SpinnakerAccount. Kubeconfig files and contents
+// are moved to a secret referenced by kubeconfigSecret, encrypted kubeconfig files are referenced as is.
+// Permissions are moved to spec.permissions and the remaining settings to spec.settings.
+func (k *AccountType) Migrate(ctx context.Context, settings map[string]interface{}, readFile account.FileReader) (interfaces.SpinnakerAccount, []*corev1.Secret, error) {
+	name, ok := settings["name"].(string)
+	if !ok || name == "" {
+		return nil, nil, fmt.Errorf("%s account missing name", k.GetType())
+	}
+	// The account name is the name of the SpinnakerAccount
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return nil, nil, fmt.Errorf("account name is not a valid SpinnakerAccount name: %s", strings.Join(errs, ", "))
+	}
+
+	acc := TypesFactory.NewAccount()
+	acc.SetName(name)
+	spec := acc.GetSpec()
+	spec.Type = interfaces.KubernetesAccountType
+	spec.Enabled = true
+	spec.Settings = interfaces.FreeForm{}
+	for key, v := range settings {
+		switch key {
+		case "name", "providerVersion", "permissions", KubeconfigFileSettings, KubeconfigFileContentSettings, UseServiceAccount:
+		default:
+			spec.Settings[key] = v
+		}
+	}
+	if p, ok := settings["permissions"]; ok {
+		if err := inspect.Convert(p, &spec.Permissions); err != nil {
+			return nil, nil, fmt.Errorf("unable to read permissions: %w", err)
+		}
+	}
+
+	auth, contents, err := k.migrateAuth(settings, readFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	spec.Kubernetes = auth
+	if contents == nil {
+		return acc, nil, nil
+	}
+	sec := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-kubeconfig", name)},
+		Data:       map[string][]byte{MigratedKubeconfigKey: contents},
+	}
+	auth.KubeconfigSecret = &interfaces.SecretInNamespaceReference{Name: sec.Name, Key: MigratedKubeconfigKey}
+	return acc, []*corev1.Secret{sec}, nil
+}
+
+// migrateAuth returns the auth of the account and the kubeconfig to move to a secret, if any
+func (k *AccountType) migrateAuth(settings map[string]interface{}, readFile account.FileReader) (*interfaces.KubernetesAuth, []byte, error) {
+	if f, ok := settings[KubeconfigFileSettings]; ok {
+		file, ok := f.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("kubeconfigFile is not a string: %s", f)
+		}
+		if yamlsecrets.IsEncryptedSecret(file) {
+			return &interfaces.KubernetesAuth{KubeconfigFile: file}, nil, nil
+		}
+		b, err := readFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to read kubeconfigFile %s: %w", file, err)
+		}
+		return &interfaces.KubernetesAuth{}, b, nil
+	}
+	if c, ok := settings[KubeconfigFileContentSettings]; ok {
+		contents, ok := c.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("kubeconfigContents is not a string: %s", c)
+		}
+		return &interfaces.KubernetesAuth{}, []byte(contents), nil
+	}
+	if sa, ok := settings[UseServiceAccount]; ok {
+		b, ok := sa.(bool)
+		if !ok {
+			return nil, nil, fmt.Errorf("serviceAccount is not a boolean: %s", sa)
+		}
+		return &interfaces.KubernetesAuth{UseServiceAccount: b}, nil, nil
+	}
+	return nil, nil, fmt.Errorf("expected one of \"kubeconfigFile\", \"serviceAccount\" or \"kubeconfigContents\", but none was found")
+}
@@ -0,0 +1,85 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrate(t *testing.T) {
+	readFile := func(name string) ([]byte, error) {
+		if name == "/home/spinnaker/.kube/config" {
+			return []byte("kubeconfig-content"), nil
+		}
+		return nil, errors.New("not found")
+	}
+	k := &AccountType{}
+
+	acc, secs, err := k.Migrate(context.TODO(), map[string]interface{}{
+		"name":            "prod",
+		"providerVersion": "V2",
+		"kubeconfigFile":  "/home/spinnaker/.kube/config",
+		"namespaces":      []interface{}{"ns1"},
+		"permissions": map[string]interface{}{
+			"READ":  []interface{}{"dev", "ops"},
+			"WRITE": []interface{}{"ops"},
+		},
+	}, readFile)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "prod", acc.GetName())
+	spec := acc.GetSpec()
+	assert.Equal(t, interfaces.KubernetesAccountType, spec.Type)
+	assert.True(t, spec.Enabled)
+	assert.Equal(t, interfaces.FreeForm{"namespaces": []interface{}{"ns1"}}, spec.Settings)
+	assert.Equal(t, interfaces.AccountPermissions{"READ": {"dev", "ops"}, "WRITE": {"ops"}}, spec.Permissions)
+	assert.Equal(t, &interfaces.SecretInNamespaceReference{Name: "prod-kubeconfig", Key: MigratedKubeconfigKey}, spec.Kubernetes.KubeconfigSecret)
+	if assert.Equal(t, 1, len(secs)) {
+		assert.Equal(t, "prod-kubeconfig", secs[0].Name)
+		assert.Equal(t, []byte("kubeconfig-content"), secs[0].Data[MigratedKubeconfigKey])
+	}
+
+	// Permissions are rendered from the SpinnakerAccount
+	a, err := k.FromCRD(acc)
+	if assert.Nil(t, err) {
+		a.(*Account).Auth = &interfaces.KubernetesAuth{UseServiceAccount: true}
+		m, err := a.ToSpinnakerSettings(context.TODO())
+		if assert.Nil(t, err) {
+			assert.Equal(t, spec.Permissions, m["permissions"])
+		}
+	}
+}
+
+func TestMigrateAuth(t *testing.T) {
+	k := &AccountType{}
+	readFile := func(string) ([]byte, error) { return nil, errors.New("not found") }
+
+	acc, secs, err := k.Migrate(context.TODO(), map[string]interface{}{
+		"name":           "encrypted",
+		"kubeconfigFile": "encryptedFile:k8s!n:spinnaker-secrets!k:kubeconfig",
+	}, readFile)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "encryptedFile:k8s!n:spinnaker-secrets!k:kubeconfig", acc.GetSpec().Kubernetes.KubeconfigFile)
+		assert.Empty(t, secs)
+	}
+
+	acc, secs, err = k.Migrate(context.TODO(), map[string]interface{}{"name": "inline", "kubeconfigContents": "content"}, readFile)
+	if assert.Nil(t, err) && assert.Equal(t, 1, len(secs)) {
+		assert.Equal(t, "inline-kubeconfig", acc.GetSpec().Kubernetes.KubeconfigSecret.Name)
+		assert.Equal(t, []byte("content"), secs[0].Data[MigratedKubeconfigKey])
+	}
+
+	acc, _, err = k.Migrate(context.TODO(), map[string]interface{}{"name": "sa", "serviceAccount": true}, readFile)
+	if assert.Nil(t, err) {
+		assert.True(t, acc.GetSpec().Kubernetes.UseServiceAccount)
+	}
+
+	_, _, err = k.Migrate(context.TODO(), map[string]interface{}{"name": "missing", "kubeconfigFile": "/missing"}, readFile)
+	assert.NotNil(t, err)
+	_, _, err = k.Migrate(context.TODO(), map[string]interface{}{"name": "Invalid_Name", "serviceAccount": true}, readFile)
+	assert.NotNil(t, err)
+}
@@ -18,7 +18,9 @@ import (
 func (k *AccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
 	a := k.newAccount()
 	a.Name = account.GetName()
+	a.Namespace = account.GetNamespace()
 	a.Settings = account.GetSpec().Settings
+	a.Permissions = account.GetSpec().Permissions
 	a.Auth = account.GetSpec().Kubernetes
 	if a.Auth == nil {
 		return nil, noKubernetesDefinedError
@@ -99,6 +101,9 @@ func (k *AccountType) authFromSpinnakerConfig(ctx context.Context, name string,
 // ToSpinnakerSettings outputs an account (either parsed from CRD or from settings) to Spinnaker settings
 func (k *Account) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
 	m := k.BaseAccount.BaseToSpinnakerSettings(k)
+	if len(k.Permissions) > 0 {
+		m["permissions"] = k.Permissions
+	}
 	if k.Auth != nil {
 		m["providerVersion"] = "V2"
 		if err := k.kubeAuthToSpinnakerSettings(ctx, m); err != nil {
@@ -125,15 +130,32 @@ func (k *Account) kubeAuthToSpinnakerSettings(ctx context.Context, settings map[
 		return nil
 	}
 	if k.Auth.KubeconfigSecret != nil {
+		// Mounted as a file when Spinnaker is deployed, decoded by the accounts config server otherwise
+		if account.MountedFiles(ctx) {
+			settings[KubeconfigFileSettings] = account.SecretFileReference(k.Auth.KubeconfigSecret)
+		} else {
+			settings[KubeconfigFileContentSettings] = account.SecretReference(k.Auth.KubeconfigSecret)
+		}
+		return nil
+	}
+	if k.Auth.Provision != nil {
+		if _, ok := settings["namespaces"]; !ok {
+			settings["namespaces"] = k.Auth.Provision.Namespaces
+		}
+		// Provisioned kubeconfig lives in the SpinnakerAccount namespace. When mounted, rotated tokens are read
+		// from the file by kubectl without restarting Clouddriver.
+		if account.MountedFiles(ctx) {
+			settings[KubeconfigFileSettings] = provisionedKubeconfigReference(k.Namespace, k.Name)
+			return nil
+		}
 		sc, err := secrets.FromContextWithError(ctx)
 		if err != nil {
 			return err
 		}
-		config, err := util.GetSecretContent(sc.RestConfig, sc.Namespace, k.Auth.KubeconfigSecret.Name, k.Auth.KubeconfigSecret.Key)
+		config, err := util.GetSecretContent(sc.RestConfig, k.Namespace, KubeconfigSecretName(k.Name), ProvisionedKubeconfigKey)
 		if err != nil {
 			return err
 		}
-		// TODO change to a file, track it and add to secret
 		settings[KubeconfigFileContentSettings] = config
 		return nil
 	}
@@ -0,0 +1,181 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Clouddriver needs to read, watch and deploy every kind it manages. Permissions are checked with
+// SelfSubjectAccessReviews for each kind, verb and namespace of the account.
+var (
+	// requiredVerbs are the verbs Spinnaker needs on each kind of the account
+	requiredVerbs = []string{"list", "watch", "create", "patch", "delete"}
+	// defaultKinds are checked when the account doesn't restrict kinds
+	defaultKinds = []string{
+		"configMap", "cronJob", "daemonSet", "deployment", "horizontalPodAutoscaler", "ingress", "job",
+		"networkPolicy", "persistentVolumeClaim", "pod", "replicaSet", "secret", "service", "serviceAccount",
+		"statefulSet",
+	}
+)
+
+// withPermissionsRateLimit returns a copy of the config allowing the many access reviews of an account to be sent quickly
+func withPermissionsRateLimit(cc *rest.Config) *rest.Config {
+	c := rest.CopyConfig(cc)
+	c.QPS, c.Burst = 50, 100
+	return c
+}
+
+// resource is a Kubernetes resource a kind of the account maps to
+type resource struct {
+	kind       string
+	group      string
+	name       string
+	namespaced bool
+}
+
+// validatePermissions checks that the account can use each of its kinds in each of its namespaces.
+// All missing permissions are reported at once.
+func (k *kubernetesAccountValidator) validatePermissions(ctx context.Context, clientset kubernetes.Interface, env Env, namespaces []string) error {
+	resourceLists, err := discovery.ServerPreferredResources(clientset.Discovery())
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return fmt.Errorf("error discovering resources in account \"%s\":\n  %w", k.account.Name, err)
+	}
+	resources, unknown := resolveKinds(resourceLists, env)
+	if len(unknown) > 0 {
+		return fmt.Errorf("kinds not found in account \"%s\": %s", k.account.Name, strings.Join(unknown, ", "))
+	}
+	if len(namespaces) == 0 {
+		// All namespaces
+		namespaces = []string{""}
+	}
+
+	missing := make(map[string]map[string][]string)
+	for _, ns := range namespaces {
+		for _, r := range resources {
+			if !r.namespaced && ns != namespaces[0] {
+				// Cluster scoped resources are only checked once
+				continue
+			}
+			for _, verb := range requiredVerbs {
+				attrs := &authorizationv1.ResourceAttributes{Verb: verb, Group: r.group, Resource: r.name}
+				if r.namespaced {
+					attrs.Namespace = ns
+				}
+				review := &authorizationv1.SelfSubjectAccessReview{
+					Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: attrs},
+				}
+				res, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+				if err != nil {
+					return fmt.Errorf("error checking permissions in account \"%s\":\n  %w", k.account.Name, err)
+				}
+				if !res.Status.Allowed {
+					if missing[attrs.Namespace] == nil {
+						missing[attrs.Namespace] = make(map[string][]string)
+					}
+					missing[attrs.Namespace][r.kind] = append(missing[attrs.Namespace][r.kind], verb)
+				}
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing permissions in account \"%s\":\n%s", k.account.Name, formatMissingPermissions(missing))
+	}
+	return nil
+}
+
+// resolveKinds maps the kinds of the account to discovered resources and returns kinds not found
+func resolveKinds(resourceLists []*metav1.APIResourceList, env Env) ([]resource, []string) {
+	kinds := env.Kinds
+	if len(kinds) == 0 {
+		kinds = defaultKinds
+	}
+	resources := make([]resource, 0)
+	unknown := make([]string, 0)
+	for _, kind := range kinds {
+		if containsIgnoreCase(env.OmitKinds, kind) {
+			continue
+		}
+		// Built-in kinds may not be served by every cluster (e.g. cronJob in old versions), they're just skipped
+		if r, ok := findResource(resourceLists, kind, "", false); ok {
+			resources = append(resources, r)
+		}
+	}
+	for _, cr := range env.CustomResources {
+		// Custom resources kinds are written as kind.group
+		kind, group := cr.KubernetesKind, ""
+		if i := strings.Index(kind, "."); i >= 0 {
+			kind, group = kind[:i], kind[i+1:]
+		}
+		if r, ok := findResource(resourceLists, kind, group, group != ""); ok {
+			r.kind = cr.KubernetesKind
+			resources = append(resources, r)
+		} else {
+			unknown = append(unknown, cr.KubernetesKind)
+		}
+	}
+	return resources, unknown
+}
+
+func findResource(resourceLists []*metav1.APIResourceList, kind, group string, matchGroup bool) (resource, bool) {
+	for _, l := range resourceLists {
+		gv, err := schema.ParseGroupVersion(l.GroupVersion)
+		if err != nil || (matchGroup && gv.Group != group) {
+			continue
+		}
+		for _, r := range l.APIResources {
+			// Skip subresources
+			if strings.Contains(r.Name, "/") || !strings.EqualFold(r.Kind, kind) {
+				continue
+			}
+			return resource{kind: kind, group: gv.Group, name: r.Name, namespaced: r.Namespaced}, true
+		}
+	}
+	return resource{}, false
+}
+
+// formatMissingPermissions lists missing verbs by namespace and kind, e.g.
+//
+//	namespace ns1: deployment (create, delete), secret (watch)
+func formatMissingPermissions(missing map[string]map[string][]string) string {
+	nss := make([]string, 0, len(missing))
+	for ns := range missing {
+		nss = append(nss, ns)
+	}
+	sort.Strings(nss)
+	lines := make([]string, 0, len(nss))
+	for _, ns := range nss {
+		kinds := make([]string, 0, len(missing[ns]))
+		for kind := range missing[ns] {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		perms := make([]string, 0, len(kinds))
+		for _, kind := range kinds {
+			perms = append(perms, fmt.Sprintf("%s (%s)", kind, strings.Join(missing[ns][kind], ", ")))
+		}
+		scope := fmt.Sprintf("namespace %s", ns)
+		if ns == "" {
+			scope = "cluster"
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s", scope, strings.Join(perms, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func containsIgnoreCase(l []string, s string) bool {
+	for _, e := range l {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+var testResourceLists = []*metav1.APIResourceList{
+	{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{
+			{Name: "pods", Kind: "Pod", Namespaced: true},
+			{Name: "pods/log", Kind: "Pod", Namespaced: true},
+			{Name: "secrets", Kind: "Secret", Namespaced: true},
+		},
+	},
+	{
+		GroupVersion: "apps/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "deployments", Kind: "Deployment", Namespaced: true},
+		},
+	},
+	{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "widgets", Kind: "Widget", Namespaced: false},
+		},
+	},
+}
+
+// newAPIServer serves discovery and denies the verbs of resources in denied
+func newAPIServer(t *testing.T, denied map[string][]string) *httptest.Server {
+	write := func(w http.ResponseWriter, o interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		assert.Nil(t, json.NewEncoder(w).Encode(o))
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api":
+			write(w, &metav1.APIVersions{Versions: []string{"v1"}})
+		case "/apis":
+			write(w, &metav1.APIGroupList{Groups: []metav1.APIGroup{
+				{Name: "apps", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "apps/v1", Version: "v1"}}},
+				{Name: "example.com", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "example.com/v1", Version: "v1"}}},
+			}})
+		case "/api/v1":
+			write(w, testResourceLists[0])
+		case "/apis/apps/v1":
+			write(w, testResourceLists[1])
+		case "/apis/example.com/v1":
+			write(w, testResourceLists[2])
+		case "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews":
+			review := &authorizationv1.SelfSubjectAccessReview{}
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(review))
+			review.Status.Allowed = true
+			attrs := review.Spec.ResourceAttributes
+			for _, v := range denied[attrs.Namespace+"/"+attrs.Resource] {
+				if v == attrs.Verb {
+					review.Status.Allowed = false
+				}
+			}
+			write(w, review)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestValidatePermissions(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        Env
+		namespaces []string
+		denied     map[string][]string
+		expected   string
+	}{
+		{
+			name:       "all permissions",
+			env:        Env{Kinds: []string{"deployment", "pod"}},
+			namespaces: []string{"ns1", "ns2"},
+		},
+		{
+			name:       "missing permissions",
+			env:        Env{Kinds: []string{"deployment", "pod", "secret"}, OmitKinds: []string{"Pod"}},
+			namespaces: []string{"ns1", "ns2"},
+			denied: map[string][]string{
+				"ns1/deployments": {"create", "delete"},
+				"ns1/secrets":     {"watch"},
+				"ns2/secrets":     {"list"},
+				"ns1/pods":        {"list"},
+			},
+			expected: "missing permissions in account \"test\":\n" +
+				"  namespace ns1: deployment (create, delete), secret (watch)\n" +
+				"  namespace ns2: secret (list)",
+		},
+		{
+			name:       "cluster scoped custom resource",
+			env:        Env{Kinds: []string{"pod"}, CustomResources: []CustomKubernetesResource{{KubernetesKind: "Widget.example.com"}}},
+			namespaces: []string{"ns1"},
+			denied:     map[string][]string{"/widgets": {"patch"}},
+			expected:   "missing permissions in account \"test\":\n  cluster: Widget.example.com (patch)",
+		},
+		{
+			name:     "unknown custom resource",
+			env:      Env{CustomResources: []CustomKubernetesResource{{KubernetesKind: "Widget.other.com"}}},
+			expected: "kinds not found in account \"test\": Widget.other.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newAPIServer(t, tt.denied)
+			defer srv.Close()
+			clientset, err := kubernetes.NewForConfig(withPermissionsRateLimit(&rest.Config{Host: srv.URL}))
+			if !assert.Nil(t, err) {
+				return
+			}
+			v := &kubernetesAccountValidator{account: &Account{Name: "test"}}
+			err = v.validatePermissions(context.TODO(), clientset, tt.env, tt.namespaces)
+			if tt.expected == "" {
+				assert.Nil(t, err)
+			} else if assert.NotNil(t, err) {
+				assert.Equal(t, tt.expected, err.Error())
+			}
+		})
+	}
+}
+
+func TestResolveKinds(t *testing.T) {
+	resources, unknown := resolveKinds(testResourceLists, Env{OmitKinds: []string{"secret"}})
+	assert.Empty(t, unknown)
+	// Default kinds not served by the cluster are skipped
+	assert.Equal(t, []resource{
+		{kind: "deployment", group: "apps", name: "deployments", namespaced: true},
+		{kind: "pod", group: "", name: "pods", namespaced: true},
+	}, resources)
+}
@@ -0,0 +1,280 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Accounts with kubernetes.provision get a service account in the SpinnakerAccount namespace bound to a ClusterRole
+// in each namespace listed. A time-bound token of the service account is requested with the TokenRequest API and
+// turned into a kubeconfig stored in a secret owned by the SpinnakerAccount. The token is rotated before it expires.
+// Role bindings live in other namespaces and can't be owned by the SpinnakerAccount: they're labeled and deleted
+// when the account is deleted. Only roles of ProvisionRoles are bound, in namespaces selected by the SpinnakerService.
+const (
+	// DefaultProvisionRole is the ClusterRole bound to provisioned service accounts
+	DefaultProvisionRole = "edit"
+	// ProvisionedKubeconfigKey is the key of the kubeconfig in the provisioned secret
+	ProvisionedKubeconfigKey = "kubeconfig"
+	// ProvisionedTokenExpirationSeconds is the lifetime of tokens requested for provisioned accounts.
+	// Tokens are rotated after two thirds of their lifetime.
+	ProvisionedTokenExpirationSeconds = 3600
+
+	accountNameLabel      = "spinnaker.io/account-name"
+	accountNamespaceLabel = "spinnaker.io/account-namespace"
+)
+
+// ProvisionRoles are the ClusterRoles provisioned accounts can be bound to, set with --provision-roles. The bind rule
+// of the operator's role lists them in resourceNames.
+var ProvisionRoles = []string{DefaultProvisionRole}
+
+func (k *AccountType) NeedsProvisioning(acc interfaces.SpinnakerAccount) bool {
+	auth := acc.GetSpec().Kubernetes
+	return auth != nil && auth.Provision != nil
+}
+
+// ProvisionedNamespaces returns the namespaces listed in kubernetes.provision.namespaces if its role is allowed
+func (k *AccountType) ProvisionedNamespaces(acc interfaces.SpinnakerAccount) ([]string, error) {
+	p := acc.GetSpec().Kubernetes.Provision
+	if len(p.Namespaces) == 0 {
+		return nil, fmt.Errorf("kubernetes.provision.namespaces must list at least one namespace")
+	}
+	if role := provisionRole(p); !contains(ProvisionRoles, role) {
+		return nil, fmt.Errorf("kubernetes.provision.role %s is not allowed, allowed roles: %v", role, ProvisionRoles)
+	}
+	return p.Namespaces, nil
+}
+
+// Provision creates or updates the service account and its role bindings. The kubeconfig secret is written
+// when missing, when its token is due for rotation or when the first namespace of provision.namespaces, the namespace
+// of its context, changed. The account status then records the new rotation schedule.
+func (k *AccountType) Provision(ctx context.Context, c client.Client, acc interfaces.SpinnakerAccount) error {
+	if _, err := k.ProvisionedNamespaces(acc); err != nil {
+		return err
+	}
+	p := acc.GetSpec().Kubernetes.Provision
+	sa := &corev1.ServiceAccount{ObjectMeta: provisionedMeta(acc, serviceAccountName(acc))}
+	if err := k.createOrUpdateOwned(ctx, c, acc, sa, nil); err != nil {
+		return err
+	}
+	if err := provisionRoleBindings(ctx, c, acc, p); err != nil {
+		return err
+	}
+
+	sec := &corev1.Secret{ObjectMeta: provisionedMeta(acc, KubeconfigSecretName(acc.GetName()))}
+	err := c.Get(ctx, client.ObjectKey{Namespace: sec.Namespace, Name: sec.Name}, sec)
+	if err == nil && !NeedsRotation(acc.GetStatus(), time.Now()) && kubeconfigNamespace(sec.Data[ProvisionedKubeconfigKey]) == p.Namespaces[0] {
+		return nil
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	kubeconfig, expireAt, err := makeKubeconfig(ctx, c, acc, p)
+	if err != nil {
+		return err
+	}
+	if err = k.createOrUpdateOwned(ctx, c, acc, sec, func() {
+		sec.Data = map[string][]byte{ProvisionedKubeconfigKey: kubeconfig}
+	}); err != nil {
+		return err
+	}
+	status := acc.GetStatus()
+	status.CredentialsExpireAt = &metav1.Timestamp{Seconds: expireAt.Unix()}
+	status.CredentialsRefreshAt = &metav1.Timestamp{Seconds: expireAt.Add(-time.Until(expireAt) / 3).Unix()}
+	return nil
+}
+
+// NeedsRotation returns true if credentials minted for the account are due for rotation
+func NeedsRotation(status *interfaces.SpinnakerAccountStatus, now time.Time) bool {
+	return status.CredentialsRefreshAt == nil || !now.Before(time.Unix(status.CredentialsRefreshAt.Seconds, 0))
+}
+
+// Deprovision deletes role bindings, the service account and the secret provisioned for the account
+func (k *AccountType) Deprovision(ctx context.Context, c client.Client, acc interfaces.SpinnakerAccount) error {
+	if err := deleteRoleBindings(ctx, c, acc, nil); err != nil {
+		return err
+	}
+	objs := []client.Object{
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: acc.GetNamespace(), Name: KubeconfigSecretName(acc.GetName())}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: acc.GetNamespace(), Name: serviceAccountName(acc)}},
+	}
+	for _, o := range objs {
+		if err := c.Delete(ctx, o); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k *AccountType) createOrUpdateOwned(ctx context.Context, c client.Client, acc interfaces.SpinnakerAccount, obj client.Object, mutate func()) error {
+	_, err := controllerutil.CreateOrUpdate(ctx, c, obj, func() error {
+		if mutate != nil {
+			mutate()
+		}
+		obj.SetLabels(provisionedLabels(acc))
+		return controllerutil.SetControllerReference(acc, obj, c.Scheme())
+	})
+	return err
+}
+
+// provisionRoleBindings binds the role in each namespace and removes bindings of namespaces no longer listed
+func provisionRoleBindings(ctx context.Context, c client.Client, acc interfaces.SpinnakerAccount, p *interfaces.KubernetesProvision) error {
+	roleRef := rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: provisionRole(p)}
+	for _, ns := range p.Namespaces {
+		rb := &rbacv1.RoleBinding{}
+		err := c.Get(ctx, client.ObjectKey{Namespace: ns, Name: roleBindingName(acc)}, rb)
+		if err == nil && rb.RoleRef != roleRef {
+			// Role reference is immutable
+			if err = c.Delete(ctx, rb); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		} else if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		rb = &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: roleBindingName(acc)}}
+		if _, err = controllerutil.CreateOrUpdate(ctx, c, rb, func() error {
+			rb.Labels = provisionedLabels(acc)
+			rb.RoleRef = roleRef
+			rb.Subjects = []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Namespace: acc.GetNamespace(), Name: serviceAccountName(acc)}}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return deleteRoleBindings(ctx, c, acc, p.Namespaces)
+}
+
+// provisionRole returns the ClusterRole bound to the provisioned service account
+func provisionRole(p *interfaces.KubernetesProvision) string {
+	if p.Role == "" {
+		return DefaultProvisionRole
+	}
+	return p.Role
+}
+
+// deleteRoleBindings deletes role bindings provisioned for the account outside of the given namespaces
+func deleteRoleBindings(ctx context.Context, c client.Client, acc interfaces.SpinnakerAccount, keep []string) error {
+	l := &rbacv1.RoleBindingList{}
+	if err := c.List(ctx, l, client.MatchingLabels(provisionedLabels(acc))); err != nil {
+		return err
+	}
+	for i := range l.Items {
+		if contains(keep, l.Items[i].Namespace) {
+			continue
+		}
+		if err := c.Delete(ctx, &l.Items[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// makeKubeconfig returns a kubeconfig authenticating to the operator's cluster with a new token of the service account
+// and the token's expiration time
+func makeKubeconfig(ctx context.Context, c client.Client, acc interfaces.SpinnakerAccount, p *interfaces.KubernetesProvision) ([]byte, time.Time, error) {
+	sc, err := secrets.FromContextWithError(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	token, expireAt, err := util.RequestServiceAccountToken(ctx, sc.RestConfig, acc.GetNamespace(), serviceAccountName(acc), ProvisionedTokenExpirationSeconds)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	ca, err := util.GetRootCA(ctx, c, acc.GetNamespace())
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	host, err := getAPIServerHost()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	name := acc.GetName()
+	cfg := clientcmdapi.NewConfig()
+	cfg.Clusters[name] = &clientcmdapi.Cluster{
+		Server:                   host,
+		CertificateAuthorityData: ca,
+	}
+	cfg.AuthInfos[name] = &clientcmdapi.AuthInfo{Token: token}
+	cfg.Contexts[name] = &clientcmdapi.Context{Cluster: name, AuthInfo: name, Namespace: p.Namespaces[0]}
+	cfg.CurrentContext = name
+	b, err := clientcmd.Write(*cfg)
+	return b, expireAt, err
+}
+
+// kubeconfigNamespace returns the namespace of the current context of a kubeconfig, empty if it can't be read
+func kubeconfigNamespace(kubeconfig []byte) string {
+	cfg, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return ""
+	}
+	if c, ok := cfg.Contexts[cfg.CurrentContext]; ok {
+		return c.Namespace
+	}
+	return ""
+}
+
+// KubeconfigSecretName is the name of the secret holding the kubeconfig of a provisioned account
+func KubeconfigSecretName(accountName string) string {
+	return fmt.Sprintf("spinnaker-account-%s-kubeconfig", accountName)
+}
+
+// RotatedFiles returns the reference to the provisioned kubeconfig, whose token is rotated
+func (k *AccountType) RotatedFiles(acc interfaces.SpinnakerAccount) []string {
+	if !k.NeedsProvisioning(acc) {
+		return nil
+	}
+	return []string{provisionedKubeconfigReference(acc.GetNamespace(), acc.GetName())}
+}
+
+// provisionedKubeconfigReference returns the file reference to the kubeconfig provisioned for the account, explicit
+// to the namespace of the account
+func provisionedKubeconfigReference(ns, accountName string) string {
+	return fmt.Sprintf("encryptedFile:%s!ns:%s!n:%s!k:%s", secrets.KubernetesSecretEngine, ns, KubeconfigSecretName(accountName), ProvisionedKubeconfigKey)
+}
+
+// IsProvisionedKubeconfig returns true if the secret key holds a provisioned kubeconfig, whose token is rotated in place
+func IsProvisionedKubeconfig(secretName, key string) bool {
+	return key == ProvisionedKubeconfigKey && strings.HasPrefix(secretName, "spinnaker-account-") && strings.HasSuffix(secretName, "-kubeconfig")
+}
+
+func serviceAccountName(acc interfaces.SpinnakerAccount) string {
+	return fmt.Sprintf("spinnaker-account-%s", acc.GetName())
+}
+
+// roleBindingName includes the account namespace as bindings of accounts from several namespaces can share a namespace
+func roleBindingName(acc interfaces.SpinnakerAccount) string {
+	return fmt.Sprintf("spinnaker-account-%s-%s", acc.GetNamespace(), acc.GetName())
+}
+
+func provisionedMeta(acc interfaces.SpinnakerAccount, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Namespace: acc.GetNamespace(), Name: name}
+}
+
+func provisionedLabels(acc interfaces.SpinnakerAccount) map[string]string {
+	return map[string]string{
+		accountNameLabel:      acc.GetName(),
+		accountNamespaceLabel: acc.GetNamespace(),
+	}
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newProvisionedAccount(role string, namespaces ...string) *v1alpha2.SpinnakerAccount {
+	return &v1alpha2.SpinnakerAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube", Namespace: "spinnaker", UID: "1234"},
+		Spec: interfaces.SpinnakerAccountSpec{
+			Type:    interfaces.KubernetesAccountType,
+			Enabled: true,
+			Kubernetes: &interfaces.KubernetesAuth{
+				Provision: &interfaces.KubernetesProvision{Namespaces: namespaces, Role: role},
+			},
+		},
+	}
+}
+
+func newProvisionClient(t *testing.T, objs ...client.Object) client.Client {
+	s := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{corev1.AddToScheme, rbacv1.AddToScheme, v1alpha2.SchemeBuilder.AddToScheme} {
+		if err := add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+// newTokenServer serves TokenRequests of the spinnaker-account-kube service account with an increasing token
+func newTokenServer(t *testing.T) *httptest.Server {
+	n := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/namespaces/spinnaker/serviceaccounts/spinnaker-account-kube/token" {
+			http.NotFound(w, r)
+			return
+		}
+		tr := &authenticationv1.TokenRequest{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(tr))
+		n++
+		tr.Status.Token = fmt.Sprintf("token%d", n)
+		tr.Status.ExpirationTimestamp = metav1.NewTime(time.Now().Add(time.Duration(*tr.Spec.ExpirationSeconds) * time.Second))
+		w.Header().Set("Content-Type", "application/json")
+		assert.Nil(t, json.NewEncoder(w).Encode(tr))
+	}))
+}
+
+func TestProvision(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "443")
+	srv := newTokenServer(t)
+	defer srv.Close()
+	ctx := secrets.NewContext(context.TODO(), &rest.Config{Host: srv.URL}, "spinnaker")
+	defer secrets.Cleanup(ctx)
+
+	acc := newProvisionedAccount("", "ns1", "ns2")
+	rootCA := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "spinnaker", Name: util.RootCAConfigMap},
+		Data:       map[string]string{corev1.ServiceAccountRootCAKey: "ca"},
+	}
+	c := newProvisionClient(t, acc, rootCA)
+	k := &AccountType{}
+
+	assert.True(t, k.NeedsProvisioning(acc))
+	if !assert.Nil(t, k.Provision(ctx, c, acc)) {
+		return
+	}
+	sa := &corev1.ServiceAccount{}
+	if assert.Nil(t, c.Get(ctx, client.ObjectKey{Namespace: "spinnaker", Name: "spinnaker-account-kube"}, sa)) {
+		assert.Equal(t, "kube", sa.OwnerReferences[0].Name)
+	}
+	for _, ns := range []string{"ns1", "ns2"} {
+		rb := &rbacv1.RoleBinding{}
+		if assert.Nil(t, c.Get(ctx, client.ObjectKey{Namespace: ns, Name: "spinnaker-account-spinnaker-kube"}, rb)) {
+			assert.Equal(t, DefaultProvisionRole, rb.RoleRef.Name)
+			assert.Equal(t, "spinnaker-account-kube", rb.Subjects[0].Name)
+			assert.Equal(t, "spinnaker", rb.Subjects[0].Namespace)
+		}
+	}
+	assertToken := func(token string, ns ...string) {
+		if len(ns) == 0 {
+			ns = []string{"ns1"}
+		}
+		sec := &corev1.Secret{}
+		if assert.Nil(t, c.Get(ctx, client.ObjectKey{Namespace: "spinnaker", Name: KubeconfigSecretName("kube")}, sec)) {
+			cfg, err := clientcmd.Load(sec.Data[ProvisionedKubeconfigKey])
+			if assert.Nil(t, err) {
+				assert.Equal(t, "https://10.0.0.1:443", cfg.Clusters["kube"].Server)
+				assert.Equal(t, []byte("ca"), cfg.Clusters["kube"].CertificateAuthorityData)
+				assert.Equal(t, token, cfg.AuthInfos["kube"].Token)
+				assert.Equal(t, ns[0], cfg.Contexts["kube"].Namespace)
+			}
+		}
+	}
+	assertToken("token1")
+	status := acc.GetStatus()
+	if assert.NotNil(t, status.CredentialsExpireAt) && assert.NotNil(t, status.CredentialsRefreshAt) {
+		assert.InDelta(t, time.Now().Add(time.Hour).Unix(), status.CredentialsExpireAt.Seconds, 5)
+		assert.InDelta(t, time.Now().Add(40*time.Minute).Unix(), status.CredentialsRefreshAt.Seconds, 5)
+	}
+
+	// Token not rotated before refresh time
+	assert.Nil(t, k.Provision(ctx, c, acc))
+	assertToken("token1")
+	status.CredentialsRefreshAt = &metav1.Timestamp{Seconds: time.Now().Add(-time.Minute).Unix()}
+	assert.Nil(t, k.Provision(ctx, c, acc))
+	assertToken("token2")
+
+	// The kubeconfig is written again when the namespace of its context changes
+	acc.Spec.Kubernetes.Provision.Namespaces = []string{"ns2", "ns1"}
+	assert.Nil(t, k.Provision(ctx, c, acc))
+	assertToken("token3", "ns2")
+	assert.Nil(t, k.Provision(ctx, c, acc))
+	assertToken("token3", "ns2")
+
+	// Namespace removed and role changed
+	ProvisionRoles = []string{DefaultProvisionRole, "view"}
+	defer func() { ProvisionRoles = []string{DefaultProvisionRole} }()
+	acc.Spec.Kubernetes.Provision = &interfaces.KubernetesProvision{Namespaces: []string{"ns1"}, Role: "view"}
+	if assert.Nil(t, k.Provision(ctx, c, acc)) {
+		rb := &rbacv1.RoleBinding{}
+		if assert.Nil(t, c.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "spinnaker-account-spinnaker-kube"}, rb)) {
+			assert.Equal(t, "view", rb.RoleRef.Name)
+		}
+		err := c.Get(ctx, client.ObjectKey{Namespace: "ns2", Name: "spinnaker-account-spinnaker-kube"}, rb)
+		assert.True(t, errors.IsNotFound(err))
+	}
+
+	if assert.Nil(t, k.Deprovision(ctx, c, acc)) {
+		rbs := &rbacv1.RoleBindingList{}
+		assert.Nil(t, c.List(ctx, rbs))
+		assert.Empty(t, rbs.Items)
+		secs := &corev1.SecretList{}
+		assert.Nil(t, c.List(ctx, secs))
+		assert.Empty(t, secs.Items)
+		err := c.Get(ctx, client.ObjectKey{Namespace: "spinnaker", Name: "spinnaker-account-kube"}, sa)
+		assert.True(t, errors.IsNotFound(err))
+	}
+}
+
+func TestNeedsRotation(t *testing.T) {
+	now := time.Now()
+	assert.True(t, NeedsRotation(&interfaces.SpinnakerAccountStatus{}, now))
+	assert.False(t, NeedsRotation(&interfaces.SpinnakerAccountStatus{CredentialsRefreshAt: &metav1.Timestamp{Seconds: now.Add(time.Minute).Unix()}}, now))
+	assert.True(t, NeedsRotation(&interfaces.SpinnakerAccountStatus{CredentialsRefreshAt: &metav1.Timestamp{Seconds: now.Unix()}}, now))
+}
+
+func TestProvisionNoNamespace(t *testing.T) {
+	acc := newProvisionedAccount("")
+	assert.NotNil(t, (&AccountType{}).Provision(context.TODO(), newProvisionClient(t, acc), acc))
+}
+
+func TestProvisionRoleNotAllowed(t *testing.T) {
+	acc := newProvisionedAccount("cluster-admin", "ns1")
+	k := &AccountType{}
+	_, err := k.ProvisionedNamespaces(acc)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "kubernetes.provision.role cluster-admin is not allowed")
+	}
+	c := newProvisionClient(t, acc)
+	assert.NotNil(t, k.Provision(context.TODO(), c, acc))
+	rbs := &rbacv1.RoleBindingList{}
+	assert.Nil(t, c.List(context.TODO(), rbs))
+	assert.Empty(t, rbs.Items)
+}
+
+func TestProvisionedKubeconfigMounted(t *testing.T) {
+	a, err := (&AccountType{}).FromCRD(newProvisionedAccount("", "ns1"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	m, err := a.ToSpinnakerSettings(account.WithMountedFiles(context.TODO()))
+	if assert.Nil(t, err) {
+		assert.Equal(t, "encryptedFile:k8s!ns:spinnaker!n:spinnaker-account-kube-kubeconfig!k:kubeconfig", m[KubeconfigFileSettings])
+		assert.Nil(t, m[KubeconfigFileContentSettings])
+		assert.Equal(t, []string{"ns1"}, m["namespaces"])
+	}
+	assert.Equal(t, []string{m[KubeconfigFileSettings].(string)}, (&AccountType{}).RotatedFiles(newProvisionedAccount("", "ns1")))
+	assert.True(t, IsProvisionedKubeconfig(KubeconfigSecretName("kube"), ProvisionedKubeconfigKey))
+	assert.False(t, IsProvisionedKubeconfig("kube", ProvisionedKubeconfigKey))
+}
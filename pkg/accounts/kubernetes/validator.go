@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	tools "github.com/armory/go-yaml-tools/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/armory/spinnaker-operator/pkg/inspect"
 	"github.com/armory/spinnaker-operator/pkg/secrets"
@@ -33,8 +34,12 @@ var (
 	noKubernetesDefinedError = fmt.Errorf("kubernetes needs to be defined")
 	noValidKubeconfigError   = fmt.Errorf("no valid kubeconfig file, kubeconfig content or service account information found")
 	noServiceAccountName     = fmt.Errorf("no service account name configured in SpinnakerService for clouddriver")
+	noSpinnakerServiceError  = fmt.Errorf("no SpinnakerService found to read clouddriver's service account from")
 )
 
+// validationTokenExpirationSeconds is the lifetime of tokens requested to validate accounts, the minimum allowed
+const validationTokenExpirationSeconds = 600
+
 type kubernetesAccountValidator struct {
 	account *Account
 }
@@ -59,13 +64,18 @@ func (k *kubernetesAccountValidator) makeClient(ctx context.Context, spinSvc int
 		return nil, err
 	}
 
+	// Accounts may be validated before being assigned to a SpinnakerService
+	var spinCfg *interfaces.SpinnakerConfig
+	if spinSvc != nil {
+		spinCfg = spinSvc.GetSpinnakerConfig()
+	}
 	auth := k.account.Auth
 	if auth == nil {
 		// Attempt from settings
-		return makeClientFromSettings(ctx, aSettings, spinSvc.GetSpinnakerConfig())
+		return makeClientFromSettings(ctx, aSettings, spinCfg)
 	}
 	if auth.KubeconfigFile != "" {
-		return makeClientFromFile(ctx, auth.KubeconfigFile, aSettings, spinSvc.GetSpinnakerConfig())
+		return makeClientFromFile(ctx, auth.KubeconfigFile, aSettings, spinCfg)
 	}
 	if auth.Kubeconfig != nil {
 		// checking this
@@ -74,6 +84,10 @@ func (k *kubernetesAccountValidator) makeClient(ctx context.Context, spinSvc int
 	if auth.KubeconfigSecret != nil {
 		return makeClientFromSecretRef(ctx, auth.KubeconfigSecret, aSettings)
 	}
+	if auth.Provision != nil {
+		ref := &interfaces.SecretInNamespaceReference{Name: KubeconfigSecretName(k.account.Name), Key: ProvisionedKubeconfigKey}
+		return makeClientFromSecret(ctx, k.account.Namespace, ref, aSettings)
+	}
 	if auth.UseServiceAccount {
 		return makeClientFromServiceAccount(ctx, spinSvc, c)
 	}
@@ -86,7 +100,7 @@ func makeClientFromFile(ctx context.Context, file string, settings authSettings,
 	var kubeconfigBytes []byte
 	var err error
 	if tools.IsEncryptedSecret(file) {
-		f, err := secrets.DecodeAsFile(ctx, file)
+		f, err := account.DecodeSecretAsFile(ctx, file)
 		if err != nil {
 			return nil, fmt.Errorf("error decoding kubeconfigFile from secret reference \"%s\":\n  %w", file, err)
 		}
@@ -102,6 +116,9 @@ func makeClientFromFile(ctx context.Context, file string, settings authSettings,
 		}
 	} else {
 		// we're taking relative file paths as files defined inside spec.spinnakerConfig.files
+		if spinCfg == nil {
+			return nil, fmt.Errorf("kubeconfigFile \"%s\" is read from spec.spinnakerConfig.files but no SpinnakerService was found", file)
+		}
 		kubeconfigBytes = spinCfg.GetFileContent(file)
 	}
 	cfg, err = clientcmd.Load(kubeconfigBytes)
@@ -115,16 +132,30 @@ func makeClientFromFile(ctx context.Context, file string, settings authSettings,
 	return restCfg, nil
 }
 
-// makeClientFromSecretRef reads the client config from a Kubernetes secret in the current context's namespace
+// makeClientFromSecretRef reads the client config from a Kubernetes secret in the account's namespace
 func makeClientFromSecretRef(ctx context.Context, ref *interfaces.SecretInNamespaceReference, settings authSettings) (*rest.Config, error) {
+	str, err := account.ReadSecret(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return makeClientFromKubeconfig(str, settings)
+}
+
+// makeClientFromSecret reads the client config from a Kubernetes secret in the given namespace
+func makeClientFromSecret(ctx context.Context, ns string, ref *interfaces.SecretInNamespaceReference, settings authSettings) (*rest.Config, error) {
 	sc, err := secrets.FromContextWithError(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to make kubeconfig file")
 	}
-	str, err := util.GetSecretContent(sc.RestConfig, sc.Namespace, ref.Name, ref.Key)
+	str, err := util.GetSecretContent(sc.RestConfig, ns, ref.Name, ref.Key)
 	if err != nil {
 		return nil, err
 	}
+	return makeClientFromKubeconfig(str, settings)
+}
+
+// makeClientFromKubeconfig makes a client config from the contents of a kubeconfig file
+func makeClientFromKubeconfig(str string, settings authSettings) (*rest.Config, error) {
 	config, err := clientcmd.NewClientConfigFromBytes([]byte(str))
 	if err != nil {
 		return nil, err
@@ -169,13 +200,20 @@ func makeClientFromServiceAccount(ctx context.Context, spinSvc interfaces.Spinna
 	if err != nil {
 		return nil, err
 	}
+	if spinSvc == nil {
+		return nil, noSpinnakerServiceError
+	}
 	an, err := spinSvc.GetSpinnakerConfig().GetServiceSettingsPropString(ctx, util.ClouddriverName, "kubernetes.serviceAccountName")
 	if err != nil {
 		return nil, noServiceAccountName
 	}
 	token, caPath, err := util.GetServiceAccountData(ctx, an, spinSvc.GetNamespace(), c)
 	if err != nil {
-		return nil, err
+		// Token secrets are no longer created for service accounts, request a short-lived token instead
+		token, caPath, err = requestServiceAccountData(ctx, an, spinSvc.GetNamespace(), c)
+		if err != nil {
+			return nil, err
+		}
 	}
 	tlsClientConfig := rest.TLSClientConfig{}
 	if _, err := certutil.NewPool(caPath); err != nil {
@@ -194,6 +232,27 @@ func makeClientFromServiceAccount(ctx context.Context, spinSvc interfaces.Spinna
 	}, nil
 }
 
+// requestServiceAccountData returns a token requested for the service account and the path of the cluster's root CA
+func requestServiceAccountData(ctx context.Context, name, ns string, c client.Client) (string, string, error) {
+	sc, err := secrets.FromContextWithError(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	token, _, err := util.RequestServiceAccountToken(ctx, sc.RestConfig, ns, name, validationTokenExpirationSeconds)
+	if err != nil {
+		return "", "", err
+	}
+	ca, err := util.GetRootCA(ctx, c, ns)
+	if err != nil {
+		return "", "", err
+	}
+	caPath, err := tools.ToTempFile(ca)
+	if err != nil {
+		return "", "", err
+	}
+	return token, caPath, nil
+}
+
 func ensureSpinSvc(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context) (interfaces.SpinnakerService, error) {
 	if spinSvc != nil {
 		return spinSvc, nil
@@ -278,12 +337,16 @@ type authSettings struct {
 }
 
 func (k *kubernetesAccountValidator) validateAccess(ctx context.Context, cc *rest.Config) error {
-	clientset, err := kubernetes.NewForConfig(cc)
+	clientset, err := kubernetes.NewForConfig(withPermissionsRateLimit(cc))
 	if err != nil {
 		return fmt.Errorf("unable to build kubernetes clientset from rest config: %w", err)
 	}
-	// We want to keep the validation short (ideally just one request), so any improvement should remain short (e.g. not a request per namespace)
+	// First check that the cluster answers with a single request, permissions are only checked if it does
 	ns, err := inspect.GetStringArray(k.account.Settings, "namespaces")
+	if (err != nil || len(ns) == 0) && k.account.Auth != nil && k.account.Auth.Provision != nil {
+		// Provisioned accounts only have access to the namespaces they were provisioned for
+		ns, err = k.account.Auth.Provision.Namespaces, nil
+	}
 	if err != nil || len(ns) == 0 {
 		// If namespaces are not defined, a list namespaces call should be successful
 		// The test is analogous to what is done in Halyard
@@ -298,7 +361,11 @@ func (k *kubernetesAccountValidator) validateAccess(ctx context.Context, cc *res
 			return fmt.Errorf("error listing pods in account \"%s\", namespace \"%s\":\n  %w", k.account.Name, ns[0], err)
 		}
 	}
-	return nil
+	env := Env{}
+	if err := inspect.Source(&env, k.account.Settings); err != nil {
+		return err
+	}
+	return k.validatePermissions(ctx, clientset, env, ns)
 }
 
 func (k *kubernetesAccountValidator) validateSettings(ctx context.Context, log logr.Logger) error {
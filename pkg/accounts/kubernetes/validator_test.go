@@ -163,6 +163,18 @@ spec:
 	assert.Equal(t, "http://mycluster.com", c.Host)
 }
 
+func TestMakeClientWithFileWithoutSpinnakerService(t *testing.T) {
+	a := &Account{
+		Name: "test",
+		Auth: &interfaces.KubernetesAuth{
+			KubeconfigFile: "kubecfg",
+		},
+	}
+	kv := &kubernetesAccountValidator{account: a}
+	_, err := kv.makeClient(context.TODO(), nil, nil)
+	assert.NotNil(t, err)
+}
+
 func TestCurrentContextMakeClientWithFileFromConfig(t *testing.T) {
 	y := `
 apiVersion: spinnaker.io/v1alpha2
@@ -0,0 +1,128 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// MigrationResult holds the objects accounts of a halconfig were converted to
+type MigrationResult struct {
+	Accounts []interfaces.SpinnakerAccount
+	Secrets  []*corev1.Secret
+	// Skipped holds the reason each account left in the halconfig couldn't be migrated
+	Skipped []error
+}
+
+// SecretExists tells whether a secret with the given name already exists where migrated objects are created
+type SecretExists func(name string) (bool, error)
+
+// Migrate converts accounts of the halconfig to SpinnakerAccount objects for account types supporting it and
+// removes them from the halconfig. Accounts that can't be converted, including accounts of types not supporting
+// migration, are left in the halconfig so accounts can be moved gradually. readFile resolves files referenced by
+// the accounts. Accounts whose secrets would overwrite an existing secret aren't converted, secretExists may be nil
+// to skip that check.
+func Migrate(ctx context.Context, config interfaces.FreeForm, readFile account.FileReader, secretExists SecretExists) (*MigrationResult, error) {
+	res := &MigrationResult{}
+	tps := make([]string, 0, len(Types))
+	for t := range Types {
+		tps = append(tps, string(t))
+	}
+	sort.Strings(tps)
+
+	for _, tp := range tps {
+		aType := Types[interfaces.AccountType(tp)]
+		arr, err := inspect.GetObjectArray(config, aType.GetConfigAccountsKey())
+		if err != nil {
+			// No account of this type
+			continue
+		}
+		mType, ok := aType.(account.AccountTypeWithMigration)
+		if !ok {
+			for _, settings := range arr {
+				res.Skipped = append(res.Skipped, fmt.Errorf("%s account %v: migration of %s accounts is not supported", tp, settings["name"], tp))
+			}
+			continue
+		}
+		primary, _ := inspect.GetRawObjectPropString(config, aType.GetPrimaryAccountsKey())
+		kept := make([]interface{}, 0)
+		for _, settings := range arr {
+			acc, secrets, err := mType.Migrate(ctx, settings, readFile)
+			if err != nil {
+				res.Skipped = append(res.Skipped, fmt.Errorf("%s account %v: %w", tp, settings["name"], err))
+				kept = append(kept, settings)
+				continue
+			}
+			if err = checkSecrets(res, secrets, secretExists); err != nil {
+				res.Skipped = append(res.Skipped, fmt.Errorf("%s account %v: %w", tp, settings["name"], err))
+				kept = append(kept, settings)
+				continue
+			}
+			res.Accounts = append(res.Accounts, acc)
+			res.Secrets = append(res.Secrets, secrets...)
+			if acc.GetName() == primary {
+				// The primary account must be defined in the halconfig
+				if err = deleteObjectProp(config, aType.GetPrimaryAccountsKey()); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err = inspect.SetObjectProp(config, aType.GetConfigAccountsKey(), kept); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// checkSecrets returns an error if a secret of a migrated account has the name of a secret already migrated or of
+// an existing secret
+func checkSecrets(res *MigrationResult, secrets []*corev1.Secret, secretExists SecretExists) error {
+	for _, sec := range secrets {
+		for _, s := range res.Secrets {
+			if s.Name == sec.Name {
+				return fmt.Errorf("secret %s is generated more than once", sec.Name)
+			}
+		}
+		if secretExists == nil {
+			continue
+		}
+		exists, err := secretExists(sec.Name)
+		if err != nil {
+			return fmt.Errorf("unable to check secret %s: %w", sec.Name, err)
+		}
+		if exists {
+			return fmt.Errorf("secret %s already exists", sec.Name)
+		}
+	}
+	return nil
+}
+
+// deleteObjectProp deletes the property at the given path (e.g. providers.kubernetes.primaryAccount)
+func deleteObjectProp(obj map[string]interface{}, prop string) error {
+	parent, key := "", prop
+	if i := strings.LastIndex(prop, "."); i >= 0 {
+		parent, key = prop[:i], prop[i+1:]
+	}
+	v := reflect.ValueOf(obj)
+	if parent != "" {
+		var err error
+		if v, err = inspect.GetObjectProp(obj, parent); err != nil {
+			return err
+		}
+	}
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Map {
+		return fmt.Errorf("%s is not an object", parent)
+	}
+	v.SetMapIndex(reflect.ValueOf(key), reflect.Value{})
+	return nil
+}
@@ -0,0 +1,89 @@
+package notifications
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlackClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/auth.test", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		if r.Header.Get("Authorization") != "Bearer good" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "invalid_auth"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "user": "spinnakerbot"}`))
+	}))
+	defer srv.Close()
+
+	c := &slackClientImpl{}
+	assert.Nil(t, c.AuthTest(context.TODO(), srv.URL, "good"))
+	err := c.AuthTest(context.TODO(), srv.URL, "bad")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid_auth")
+	}
+}
+
+// serveSMTP answers a minimal SMTP conversation without STARTTLS or AUTH support on a single connection
+func serveSMTP(t *testing.T, l net.Listener) {
+	conn, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	write := func(s string) {
+		_, _ = conn.Write([]byte(s + "\r\n"))
+	}
+	write("220 localhost ESMTP test")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			write("250 localhost")
+		case strings.HasPrefix(cmd, "QUIT"):
+			write("221 bye")
+			return
+		default:
+			write("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPClient(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer l.Close()
+	addr := l.Addr().(*net.TCPAddr)
+	port := int32(addr.Port)
+
+	c := &smtpClientImpl{}
+	go serveSMTP(t, l)
+	assert.Nil(t, c.Handshake(context.TODO(), smtpConfig{host: "127.0.0.1", port: port}))
+
+	// STARTTLS is not supported by the test server
+	go serveSMTP(t, l)
+	err = c.Handshake(context.TODO(), smtpConfig{host: "127.0.0.1", port: port, startTls: true})
+	assert.NotNil(t, err)
+
+	// Nothing listening
+	l2, _ := net.Listen("tcp", "127.0.0.1:0")
+	p2, _ := strconv.Atoi(strings.Split(l2.Addr().String(), ":")[1])
+	l2.Close()
+	assert.NotNil(t, c.Handshake(context.TODO(), smtpConfig{host: "127.0.0.1", port: int32(p2)}))
+}
@@ -0,0 +1,68 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+)
+
+// Notification accounts are read from their section of the SpinnakerAccount (e.g. `slack`) and rendered in Echo.
+// Echo only supports one configuration of each kind of notification: accounts are singletons whose settings
+// (including `spec.settings`) are keyed by property path in Echo's settings (e.g. `slack.botName`).
+const echoService = "echo"
+
+var (
+	noSlackDefinedError          = errors.New("slack needs to be defined")
+	noSMTPDefinedError           = errors.New("smtp needs to be defined")
+	noMicrosoftTeamsDefinedError = errors.New("microsoftTeams needs to be defined")
+	noBotNameError               = errors.New("slack botName is required")
+	noHostError                  = errors.New("smtp host is required")
+	noFromError                  = errors.New("smtp from is required")
+	noWebhookUrlError            = errors.New("microsoftTeams webhookUrl is required")
+)
+
+// notificationKind holds what's common to the types of a kind of notification account
+type notificationKind struct {
+	accountType interfaces.AccountType
+	// key of the kind in Echo's settings (e.g. slack)
+	key string
+	// key of the kind in Spinnaker's config, empty if it can't be configured there
+	configKey string
+	// name of the kind in spec.validation.notifications
+	validationName string
+}
+
+func (k notificationKind) services() []string {
+	return []string{echoService}
+}
+
+// validationSettings returns the validation settings of the kind in spec.validation.notifications
+func (k notificationKind) validationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	v := spinsvc.GetSpinnakerValidation()
+	for n, s := range v.Notifications {
+		if strings.ToLower(n) == k.validationName {
+			return &s
+		}
+	}
+	return v.GetValidationSettings()
+}
+
+// baseSettings returns the free form settings of the account, enabling the notification
+func (k notificationKind) baseSettings(settings interfaces.FreeForm) map[string]interface{} {
+	m := make(map[string]interface{})
+	m[fmt.Sprintf("%s.enabled", k.key)] = true
+	for key, val := range settings {
+		m[key] = val
+	}
+	return m
+}
+
+// nameFromSettings returns the name of an account read from Spinnaker's config, defaulting to the kind
+func nameFromSettings(k notificationKind, settings map[string]interface{}) string {
+	if n, ok := settings["name"].(string); ok && n != "" {
+		return n
+	}
+	return k.key
+}
@@ -0,0 +1,161 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func TestFromCRD(t *testing.T) {
+	tests := []struct {
+		name        string
+		accountType account.SpinnakerAccountType
+		manifest    string
+		expected    func(t *testing.T, a account.Account, err error)
+	}{
+		{
+			name:        "no slack section in CRD",
+			accountType: &SlackAccountType{},
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: slack
+spec:
+  type: Slack
+`,
+			expected: func(t *testing.T, _ account.Account, err error) {
+				assert.Equal(t, noSlackDefinedError, err)
+			},
+		},
+		{
+			name:        "slack",
+			accountType: &SlackAccountType{},
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: slack
+spec:
+  type: Slack
+  slack:
+    botName: spinnakerbot
+    tokenSecret:
+      name: slack
+      key: token
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if assert.Nil(t, err) {
+					s := a.(*SlackAccount)
+					assert.Equal(t, "spinnakerbot", s.Slack.BotName)
+					assert.Equal(t, "slack", s.Slack.TokenSecret.Name)
+				}
+			},
+		},
+		{
+			name:        "smtp",
+			accountType: &SMTPAccountType{},
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: mail
+spec:
+  type: SMTP
+  smtp:
+    host: smtp.example.com
+    port: 587
+    from: spinnaker@example.com
+    username: spinnaker
+    startTls: true
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if assert.Nil(t, err) {
+					s := a.(*SMTPAccount)
+					assert.Equal(t, "smtp.example.com", s.SMTP.Host)
+					assert.Equal(t, int32(587), s.getPort())
+					assert.True(t, s.SMTP.StartTls)
+				}
+			},
+		},
+		{
+			name:        "microsoft teams",
+			accountType: &MicrosoftTeamsAccountType{},
+			manifest: `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerAccount
+metadata:
+  name: teams
+spec:
+  type: MicrosoftTeams
+  microsoftTeams:
+    webhookUrl: https://example.webhook.office.com/webhookb2/abc
+`,
+			expected: func(t *testing.T, a account.Account, err error) {
+				if assert.Nil(t, err) {
+					assert.Equal(t, "https://example.webhook.office.com/webhookb2/abc", a.(*MicrosoftTeamsAccount).MicrosoftTeams.WebhookUrl)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sa := test.TypesFactory.NewAccount()
+			if !assert.Nil(t, yaml.Unmarshal([]byte(tt.manifest), sa)) {
+				return
+			}
+			a, err := tt.accountType.FromCRD(sa)
+			tt.expected(t, a, err)
+		})
+	}
+}
+
+func TestFromSpinnakerSettings(t *testing.T) {
+	k := &SlackAccountType{}
+	a, err := k.FromSpinnakerConfig(context.TODO(), map[string]interface{}{
+		"enabled": true,
+		"botName": "spinnakerbot",
+		"token":   "encrypted:s3!b:bucket!f:secrets.yml!k:slack.token",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "slack", a.GetName())
+	ss, err := a.ToSpinnakerSettings(context.TODO())
+	if assert.Nil(t, err) {
+		assert.Equal(t, true, ss["slack.enabled"])
+		assert.Equal(t, "spinnakerbot", ss[SlackBotNameSettings])
+		assert.Equal(t, "encrypted:s3!b:bucket!f:secrets.yml!k:slack.token", ss[SlackTokenSettings])
+	}
+
+	_, err = (&SMTPAccountType{}).FromSpinnakerConfig(context.TODO(), map[string]interface{}{})
+	assert.NotNil(t, err)
+}
+
+func TestToSpinnakerSettings(t *testing.T) {
+	s := &SMTPAccount{
+		Name:     "mail",
+		SMTP:     interfaces.SMTPAccount{Host: "smtp.example.com", From: "spinnaker@example.com", Username: "spinnaker", StartTls: true},
+		Settings: interfaces.FreeForm{"spring.mail.properties.mail.smtp.connectiontimeout": 5000},
+	}
+	ss, err := s.ToSpinnakerSettings(context.TODO())
+	if assert.Nil(t, err) {
+		assert.Equal(t, true, ss["mail.enabled"])
+		assert.Equal(t, "spinnaker@example.com", ss[SMTPFromSettings])
+		assert.Equal(t, int32(defaultSMTPPort), ss[SMTPPortSettings])
+		assert.Equal(t, true, ss[SMTPAuthSettings])
+		assert.Equal(t, true, ss[SMTPStartTlsSettings])
+		assert.Equal(t, 5000, ss["spring.mail.properties.mail.smtp.connectiontimeout"])
+		assert.Nil(t, ss["name"])
+	}
+
+	s.SMTP.From = ""
+	_, err = s.ToSpinnakerSettings(context.TODO())
+	assert.Equal(t, noFromError, err)
+}
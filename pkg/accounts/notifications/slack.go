@@ -0,0 +1,150 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	SlackBotNameSettings = "slack.botName"
+	SlackTokenSettings   = "slack.token"
+	SlackBaseUrlSettings = "slack.baseUrl"
+	slackApiUrl          = "https://slack.com"
+)
+
+var slackKind = notificationKind{accountType: interfaces.SlackAccountType, key: "slack", configKey: "notifications.slack", validationName: "slack"}
+
+type SlackAccountType struct{}
+
+func (k *SlackAccountType) GetType() interfaces.AccountType {
+	return slackKind.accountType
+}
+
+func (k *SlackAccountType) GetAccountsKey() string {
+	return slackKind.key
+}
+
+func (k *SlackAccountType) GetConfigAccountsKey() string {
+	return slackKind.configKey
+}
+
+func (k *SlackAccountType) GetServices() []string {
+	return slackKind.services()
+}
+
+// GetPrimaryAccountsKey returns an empty key, notification accounts have no primary account
+func (k *SlackAccountType) GetPrimaryAccountsKey() string {
+	return ""
+}
+
+func (k *SlackAccountType) IsSingleton() bool {
+	return true
+}
+
+func (k *SlackAccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	return slackKind.validationSettings(spinsvc)
+}
+
+func (k *SlackAccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := &SlackAccount{Name: account.GetName(), Settings: account.GetSpec().Settings}
+	if account.GetSpec().Slack == nil {
+		return nil, noSlackDefinedError
+	}
+	account.GetSpec().Slack.DeepCopyInto(&a.Slack)
+	return a, nil
+}
+
+// FromSpinnakerConfig reads Slack settings from Spinnaker's config (notifications.slack)
+func (k *SlackAccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	a := &SlackAccount{Name: nameFromSettings(slackKind, settings), Settings: interfaces.FreeForm{}}
+	if n, ok := settings["botName"].(string); ok {
+		a.Slack.BotName = n
+	}
+	if u, ok := settings["baseUrl"].(string); ok {
+		a.Slack.BaseUrl = u
+	}
+	if t, ok := settings["token"]; ok {
+		a.Settings[SlackTokenSettings] = t
+	}
+	return a, nil
+}
+
+type SlackAccount struct {
+	*account.BaseAccount
+	Name     string                  `json:"name,omitempty"`
+	Slack    interfaces.SlackAccount `json:"slack,omitempty"`
+	Settings interfaces.FreeForm     `json:"settings,omitempty"`
+}
+
+func (k *SlackAccount) GetType() interfaces.AccountType {
+	return slackKind.accountType
+}
+
+func (k *SlackAccount) GetName() string {
+	return k.Name
+}
+
+func (k *SlackAccount) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *SlackAccount) NewValidator() account.AccountValidator {
+	return &slackAccountValidator{account: k, client: &slackClientImpl{}}
+}
+
+func (k *SlackAccount) NewSettingsValidator() account.AccountValidator {
+	return &slackAccountValidator{account: k}
+}
+
+func (k *SlackAccount) getBaseUrl() string {
+	if k.Slack.BaseUrl != "" {
+		return k.Slack.BaseUrl
+	}
+	return slackApiUrl
+}
+
+// ToSpinnakerSettings outputs the account to Echo's settings keyed by property path
+func (k *SlackAccount) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := slackKind.baseSettings(k.Settings)
+	if k.Slack.BotName == "" {
+		return nil, noBotNameError
+	}
+	m[SlackBotNameSettings] = k.Slack.BotName
+	if k.Slack.BaseUrl != "" {
+		m[SlackBaseUrlSettings] = k.Slack.BaseUrl
+	}
+	account.SetSecretReference(m, k.Slack.TokenSecret, SlackTokenSettings)
+	return m, nil
+}
+
+type slackAccountValidator struct {
+	account *SlackAccount
+	// client is nil if only settings are validated
+	client slackClient
+}
+
+func (v *slackAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	a := v.account
+	if a.Slack.BotName == "" {
+		return noBotNameError
+	}
+	token, err := account.GetSecretValue(ctx, a.Slack.TokenSecret, a.Settings, SlackTokenSettings)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return fmt.Errorf("slack account \"%s\" requires a token", a.Name)
+	}
+	if v.client == nil {
+		return nil
+	}
+	if err := v.client.AuthTest(ctx, a.getBaseUrl(), token); err != nil {
+		return fmt.Errorf("slack token of account \"%s\" was rejected:\n  %w", a.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/util"
+)
+
+// slackClient checks a token with Slack's auth.test method
+type slackClient interface {
+	AuthTest(ctx context.Context, baseUrl, token string) error
+}
+
+type slackClientImpl struct{}
+
+type slackResponse struct {
+	Ok    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+func (s *slackClientImpl) AuthTest(ctx context.Context, baseUrl, token string) error {
+	h := &util.HttpService{}
+	req, err := h.Request(ctx, util.POST, strings.TrimSuffix(baseUrl, "/")+"/api/auth.test", nil, map[string]string{
+		"Authorization": "Bearer " + token,
+	}, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := h.Execute(ctx, req)
+	if err != nil {
+		return err
+	}
+	b, err := h.ParseResponseBody(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("auth.test returned %d: %s", resp.StatusCode, string(b))
+	}
+	r := slackResponse{}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return fmt.Errorf("invalid auth.test response: %w", err)
+	}
+	if !r.Ok {
+		return fmt.Errorf("auth.test failed: %s", r.Error)
+	}
+	return nil
+}
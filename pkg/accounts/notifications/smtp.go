@@ -0,0 +1,160 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	SMTPFromSettings     = "mail.from"
+	SMTPHostSettings     = "spring.mail.host"
+	SMTPPortSettings     = "spring.mail.port"
+	SMTPUsernameSettings = "spring.mail.username"
+	SMTPPasswordSettings = "spring.mail.password"
+	SMTPAuthSettings     = "spring.mail.properties.mail.smtp.auth"
+	SMTPStartTlsSettings = "spring.mail.properties.mail.smtp.starttls.enable"
+	defaultSMTPPort      = 25
+)
+
+var smtpKind = notificationKind{accountType: interfaces.SMTPAccountType, key: "mail", validationName: "smtp"}
+
+type SMTPAccountType struct{}
+
+func (k *SMTPAccountType) GetType() interfaces.AccountType {
+	return smtpKind.accountType
+}
+
+func (k *SMTPAccountType) GetAccountsKey() string {
+	return smtpKind.key
+}
+
+func (k *SMTPAccountType) GetConfigAccountsKey() string {
+	return smtpKind.configKey
+}
+
+func (k *SMTPAccountType) GetServices() []string {
+	return smtpKind.services()
+}
+
+// GetPrimaryAccountsKey returns an empty key, notification accounts have no primary account
+func (k *SMTPAccountType) GetPrimaryAccountsKey() string {
+	return ""
+}
+
+func (k *SMTPAccountType) IsSingleton() bool {
+	return true
+}
+
+func (k *SMTPAccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	return smtpKind.validationSettings(spinsvc)
+}
+
+func (k *SMTPAccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := &SMTPAccount{Name: account.GetName(), Settings: account.GetSpec().Settings}
+	if account.GetSpec().SMTP == nil {
+		return nil, noSMTPDefinedError
+	}
+	account.GetSpec().SMTP.DeepCopyInto(&a.SMTP)
+	return a, nil
+}
+
+// FromSpinnakerConfig is not supported: SMTP is configured in Echo's profile and not in Spinnaker's config
+func (k *SMTPAccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	return nil, fmt.Errorf("%s accounts can't be read from Spinnaker's config", k.GetType())
+}
+
+type SMTPAccount struct {
+	*account.BaseAccount
+	Name     string                 `json:"name,omitempty"`
+	SMTP     interfaces.SMTPAccount `json:"smtp,omitempty"`
+	Settings interfaces.FreeForm    `json:"settings,omitempty"`
+}
+
+func (k *SMTPAccount) GetType() interfaces.AccountType {
+	return smtpKind.accountType
+}
+
+func (k *SMTPAccount) GetName() string {
+	return k.Name
+}
+
+func (k *SMTPAccount) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *SMTPAccount) NewValidator() account.AccountValidator {
+	return &smtpAccountValidator{account: k, client: &smtpClientImpl{}}
+}
+
+func (k *SMTPAccount) getPort() int32 {
+	if k.SMTP.Port != 0 {
+		return k.SMTP.Port
+	}
+	return defaultSMTPPort
+}
+
+// ToSpinnakerSettings outputs the account to Echo's settings keyed by property path
+func (k *SMTPAccount) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := smtpKind.baseSettings(k.Settings)
+	s := k.SMTP
+	if s.Host == "" {
+		return nil, noHostError
+	}
+	if s.From == "" {
+		return nil, noFromError
+	}
+	m[SMTPFromSettings] = s.From
+	m[SMTPHostSettings] = s.Host
+	m[SMTPPortSettings] = k.getPort()
+	if s.Username != "" {
+		m[SMTPUsernameSettings] = s.Username
+		m[SMTPAuthSettings] = true
+	}
+	account.SetSecretReference(m, s.PasswordSecret, SMTPPasswordSettings)
+	if s.StartTls {
+		m[SMTPStartTlsSettings] = true
+	}
+	return m, nil
+}
+
+type smtpAccountValidator struct {
+	account *SMTPAccount
+	client  smtpClient
+}
+
+func (v *smtpAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	a := v.account
+	if a.SMTP.Host == "" {
+		return noHostError
+	}
+	if a.SMTP.From == "" {
+		return noFromError
+	}
+	if _, err := mail.ParseAddress(a.SMTP.From); err != nil {
+		return fmt.Errorf("invalid from address \"%s\" in smtp account \"%s\":\n  %w", a.SMTP.From, a.Name, err)
+	}
+	password, err := account.GetSecretValue(ctx, a.SMTP.PasswordSecret, a.Settings, SMTPPasswordSettings)
+	if err != nil {
+		return err
+	}
+	if a.SMTP.Username != "" && password == "" {
+		return fmt.Errorf("smtp account \"%s\" has a username but no password", a.Name)
+	}
+	cfg := smtpConfig{
+		host:     a.SMTP.Host,
+		port:     a.getPort(),
+		username: a.SMTP.Username,
+		password: password,
+		startTls: a.SMTP.StartTls,
+	}
+	if err := v.client.Handshake(ctx, cfg); err != nil {
+		return fmt.Errorf("smtp handshake with %s:%d failed for account \"%s\":\n  %w", cfg.host, cfg.port, a.Name, err)
+	}
+	return nil
+}
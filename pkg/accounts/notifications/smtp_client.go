@@ -0,0 +1,59 @@
+package notifications
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+const smtpTimeout = 10 * time.Second
+
+type smtpConfig struct {
+	host     string
+	port     int32
+	username string
+	password string
+	startTls bool
+}
+
+// smtpClient connects to an SMTP server, upgrading the connection and authenticating as configured
+type smtpClient interface {
+	Handshake(ctx context.Context, cfg smtpConfig) error
+}
+
+type smtpClientImpl struct{}
+
+func (s *smtpClientImpl) Handshake(ctx context.Context, cfg smtpConfig) error {
+	d := &net.Dialer{Timeout: smtpTimeout}
+	conn, err := d.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", cfg.host, cfg.port))
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(smtpTimeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	c, err := smtp.NewClient(conn, cfg.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if err := c.Hello("spinnaker-operator"); err != nil {
+		return err
+	}
+	if cfg.startTls {
+		if err := c.StartTLS(&tls.Config{ServerName: cfg.host}); err != nil {
+			return err
+		}
+	}
+	if cfg.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", cfg.username, cfg.password, cfg.host)); err != nil {
+			return err
+		}
+	}
+	return c.Quit()
+}
@@ -0,0 +1,140 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const MicrosoftTeamsWebhookUrlSettings = "microsoftteams.webhookUrl"
+
+// Hosts of Teams incoming webhooks (connectors) and of Power Automate workflows replacing them
+var teamsWebhookHostSuffixes = []string{".webhook.office.com", "outlook.office.com", "outlook.office365.com", ".logic.azure.com"}
+
+var teamsKind = notificationKind{accountType: interfaces.MicrosoftTeamsAccountType, key: "microsoftteams", validationName: "microsoftteams"}
+
+type MicrosoftTeamsAccountType struct{}
+
+func (k *MicrosoftTeamsAccountType) GetType() interfaces.AccountType {
+	return teamsKind.accountType
+}
+
+func (k *MicrosoftTeamsAccountType) GetAccountsKey() string {
+	return teamsKind.key
+}
+
+func (k *MicrosoftTeamsAccountType) GetConfigAccountsKey() string {
+	return teamsKind.configKey
+}
+
+func (k *MicrosoftTeamsAccountType) GetServices() []string {
+	return teamsKind.services()
+}
+
+// GetPrimaryAccountsKey returns an empty key, notification accounts have no primary account
+func (k *MicrosoftTeamsAccountType) GetPrimaryAccountsKey() string {
+	return ""
+}
+
+func (k *MicrosoftTeamsAccountType) IsSingleton() bool {
+	return true
+}
+
+func (k *MicrosoftTeamsAccountType) GetValidationSettings(spinsvc interfaces.SpinnakerService) *interfaces.ValidationSetting {
+	return teamsKind.validationSettings(spinsvc)
+}
+
+func (k *MicrosoftTeamsAccountType) FromCRD(account interfaces.SpinnakerAccount) (account.Account, error) {
+	a := &MicrosoftTeamsAccount{Name: account.GetName(), Settings: account.GetSpec().Settings}
+	if account.GetSpec().MicrosoftTeams == nil {
+		return nil, noMicrosoftTeamsDefinedError
+	}
+	a.MicrosoftTeams = *account.GetSpec().MicrosoftTeams
+	return a, nil
+}
+
+// FromSpinnakerConfig is not supported: Microsoft Teams is configured in Echo's profile and not in Spinnaker's config
+func (k *MicrosoftTeamsAccountType) FromSpinnakerConfig(ctx context.Context, settings map[string]interface{}) (account.Account, error) {
+	return nil, fmt.Errorf("%s accounts can't be read from Spinnaker's config", k.GetType())
+}
+
+type MicrosoftTeamsAccount struct {
+	*account.BaseAccount
+	Name           string                           `json:"name,omitempty"`
+	MicrosoftTeams interfaces.MicrosoftTeamsAccount `json:"microsoftTeams,omitempty"`
+	Settings       interfaces.FreeForm              `json:"settings,omitempty"`
+}
+
+func (k *MicrosoftTeamsAccount) GetType() interfaces.AccountType {
+	return teamsKind.accountType
+}
+
+func (k *MicrosoftTeamsAccount) GetName() string {
+	return k.Name
+}
+
+func (k *MicrosoftTeamsAccount) GetSettings() *interfaces.FreeForm {
+	return &k.Settings
+}
+
+func (k *MicrosoftTeamsAccount) NewValidator() account.AccountValidator {
+	return &teamsAccountValidator{account: k}
+}
+
+// ToSpinnakerSettings outputs the account to Echo's settings keyed by property path
+func (k *MicrosoftTeamsAccount) ToSpinnakerSettings(ctx context.Context) (map[string]interface{}, error) {
+	m := teamsKind.baseSettings(k.Settings)
+	if k.MicrosoftTeams.WebhookUrl == "" {
+		return nil, noWebhookUrlError
+	}
+	m[MicrosoftTeamsWebhookUrlSettings] = k.MicrosoftTeams.WebhookUrl
+	return m, nil
+}
+
+// teamsAccountValidator checks the format of the webhook URL, posting to it would send a message to the channel
+type teamsAccountValidator struct {
+	account *MicrosoftTeamsAccount
+}
+
+func (v *teamsAccountValidator) Validate(spinSvc interfaces.SpinnakerService, c client.Client, ctx context.Context, log logr.Logger) error {
+	a := v.account
+	if a.MicrosoftTeams.WebhookUrl == "" {
+		return noWebhookUrlError
+	}
+	if err := validateWebhookUrl(a.MicrosoftTeams.WebhookUrl); err != nil {
+		return fmt.Errorf("invalid webhook URL in microsoftTeams account \"%s\":\n  %w", a.Name, err)
+	}
+	return nil
+}
+
+func validateWebhookUrl(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("expected https scheme, found \"%s\"", u.Scheme)
+	}
+	if !isTeamsWebhookHost(u.Hostname()) {
+		return fmt.Errorf("host \"%s\" is not a Microsoft Teams webhook host", u.Hostname())
+	}
+	if u.Path == "" || u.Path == "/" {
+		return fmt.Errorf("missing webhook path")
+	}
+	return nil
+}
+
+func isTeamsWebhookHost(host string) bool {
+	for _, s := range teamsWebhookHostSuffixes {
+		if strings.HasPrefix(s, ".") && strings.HasSuffix(host, s) || host == s {
+			return true
+		}
+	}
+	return false
+}
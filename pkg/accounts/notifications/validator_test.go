@@ -0,0 +1,164 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	logr "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type fakeSlackClient struct {
+	err     error
+	baseUrl string
+	token   string
+}
+
+func (f *fakeSlackClient) AuthTest(ctx context.Context, baseUrl, token string) error {
+	f.baseUrl = baseUrl
+	f.token = token
+	return f.err
+}
+
+type fakeSMTPClient struct {
+	err    error
+	called bool
+	cfg    smtpConfig
+}
+
+func (f *fakeSMTPClient) Handshake(ctx context.Context, cfg smtpConfig) error {
+	f.called = true
+	f.cfg = cfg
+	return f.err
+}
+
+func TestSlackValidate(t *testing.T) {
+	ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+	defer secrets.Cleanup(ctx)
+	c := &fakeSlackClient{}
+	v := &slackAccountValidator{
+		account: &SlackAccount{Name: "slack", Slack: interfaces.SlackAccount{BotName: "spinnakerbot"}},
+		client:  c,
+	}
+	err := v.Validate(nil, nil, ctx, logr.Log)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "requires a token")
+	}
+
+	v.account.Settings = interfaces.FreeForm{SlackTokenSettings: "xoxb-token"}
+	assert.Nil(t, v.Validate(nil, nil, ctx, logr.Log))
+	assert.Equal(t, slackApiUrl, c.baseUrl)
+	assert.Equal(t, "xoxb-token", c.token)
+
+	c.err = errors.New("auth.test failed: invalid_auth")
+	err = v.Validate(nil, nil, ctx, logr.Log)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid_auth")
+	}
+	// settings are checked without calling Slack
+	assert.Nil(t, v.account.NewSettingsValidator().Validate(nil, nil, ctx, logr.Log))
+}
+
+func TestSMTPValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		smtp     interfaces.SMTPAccount
+		settings interfaces.FreeForm
+		client   *fakeSMTPClient
+		expected func(t *testing.T, c *fakeSMTPClient, err error)
+	}{
+		{
+			name:   "valid",
+			smtp:   interfaces.SMTPAccount{Host: "smtp.example.com", Port: 587, From: "Spinnaker <spinnaker@example.com>", Username: "spinnaker", StartTls: true},
+			client: &fakeSMTPClient{},
+			settings: interfaces.FreeForm{
+				SMTPPasswordSettings: "secret",
+			},
+			expected: func(t *testing.T, c *fakeSMTPClient, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, smtpConfig{host: "smtp.example.com", port: 587, username: "spinnaker", password: "secret", startTls: true}, c.cfg)
+			},
+		},
+		{
+			name:   "invalid from",
+			smtp:   interfaces.SMTPAccount{Host: "smtp.example.com", From: "spinnaker"},
+			client: &fakeSMTPClient{},
+			expected: func(t *testing.T, c *fakeSMTPClient, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "invalid from address")
+				}
+				assert.False(t, c.called)
+			},
+		},
+		{
+			name:   "no host",
+			smtp:   interfaces.SMTPAccount{From: "spinnaker@example.com"},
+			client: &fakeSMTPClient{},
+			expected: func(t *testing.T, c *fakeSMTPClient, err error) {
+				assert.Equal(t, noHostError, err)
+			},
+		},
+		{
+			name:   "handshake failure",
+			smtp:   interfaces.SMTPAccount{Host: "smtp.example.com", From: "spinnaker@example.com"},
+			client: &fakeSMTPClient{err: errors.New("connection refused")},
+			expected: func(t *testing.T, c *fakeSMTPClient, err error) {
+				if assert.NotNil(t, err) {
+					assert.Contains(t, err.Error(), "smtp.example.com:25")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := secrets.NewContext(context.TODO(), nil, "ns1")
+			defer secrets.Cleanup(ctx)
+			v := &smtpAccountValidator{
+				account: &SMTPAccount{Name: "mail", SMTP: tt.smtp, Settings: tt.settings},
+				client:  tt.client,
+			}
+			err := v.Validate(nil, nil, ctx, logr.Log)
+			tt.expected(t, tt.client, err)
+		})
+	}
+}
+
+func TestValidateWebhookUrl(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://contoso.webhook.office.com/webhookb2/abc@def/IncomingWebhook/123/456", true},
+		{"https://outlook.office.com/webhook/abc", true},
+		{"https://prod-01.westus.logic.azure.com:443/workflows/abc/triggers/manual/paths/invoke", true},
+		{"http://contoso.webhook.office.com/webhookb2/abc", false},
+		{"https://example.com/webhook", false},
+		{"https://contoso.webhook.office.com", false},
+		{"not a url", false},
+	}
+	for _, tt := range tests {
+		err := validateWebhookUrl(tt.url)
+		assert.Equal(t, tt.valid, err == nil, tt.url)
+	}
+}
+
+func TestGetValidationSettings(t *testing.T) {
+	spinsvc := test.ManifestToSpinService(`
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: test
+spec:
+  validation:
+    notifications:
+      slack:
+        enabled: false
+`, t)
+	assert.False(t, (&SlackAccountType{}).GetValidationSettings(spinsvc).Enabled)
+	assert.True(t, (&SMTPAccountType{}).GetValidationSettings(spinsvc).Enabled)
+}
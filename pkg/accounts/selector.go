@@ -0,0 +1,170 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SpinnakerAccount are read from the SpinnakerService namespace and from namespaces matching
+// spec.accounts.namespaceSelector. spec.accounts.selector further filters accounts by label.
+
+// IsNamespaceSelected returns true if SpinnakerAccount in namespace ns can be used by the SpinnakerService
+func IsNamespaceSelected(ctx context.Context, c client.Client, spinsvc interfaces.SpinnakerService, ns string) (bool, error) {
+	if ns == spinsvc.GetNamespace() {
+		return true, nil
+	}
+	sel, err := namespaceSelector(spinsvc)
+	if err != nil || sel == nil {
+		return false, err
+	}
+	n := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: ns}, n); err != nil {
+		return false, err
+	}
+	return sel.Matches(labels.Set(n.GetLabels())), nil
+}
+
+// CheckProvisioning returns an error if the account asks for resources to be provisioned (e.g. role bindings) outside of
+// the namespaces selected by the SpinnakerService, or resources the operator isn't allowed to provision.
+func CheckProvisioning(ctx context.Context, c client.Client, spinsvc interfaces.SpinnakerService, acc interfaces.SpinnakerAccount) error {
+	t, err := GetType(acc.GetSpec().Type)
+	if err != nil {
+		return err
+	}
+	p, ok := t.(account.AccountTypeWithProvisioning)
+	if !ok || !p.NeedsProvisioning(acc) {
+		return nil
+	}
+	if spinsvc == nil {
+		return fmt.Errorf("namespace %s is not selected by any SpinnakerService", acc.GetNamespace())
+	}
+	nss, err := p.ProvisionedNamespaces(acc)
+	if err != nil {
+		return err
+	}
+	for _, ns := range append([]string{acc.GetNamespace()}, nss...) {
+		ok, err := IsNamespaceSelected(ctx, c, spinsvc, ns)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("namespace %s is not selected by SpinnakerService %s (spec.accounts.namespaceSelector)", ns, spinsvc.GetName())
+		}
+	}
+	return nil
+}
+
+// IsSelected returns true if the SpinnakerAccount is selected by the SpinnakerService
+func IsSelected(ctx context.Context, c client.Client, spinsvc interfaces.SpinnakerService, acc interfaces.SpinnakerAccount) (bool, error) {
+	sel, err := accountSelector(spinsvc)
+	if err != nil || !sel.Matches(labels.Set(acc.GetLabels())) {
+		return false, err
+	}
+	return IsNamespaceSelected(ctx, c, spinsvc, acc.GetNamespace())
+}
+
+// FindSpinnakerServices returns the SpinnakerService selecting the account or having it in their status
+// (status.accounts) - the latter need to be updated when an account is no longer selected.
+func FindSpinnakerServices(ctx context.Context, c client.Client, acc interfaces.SpinnakerAccount) ([]interfaces.SpinnakerService, error) {
+	l := TypesFactory.NewServiceList()
+	if err := c.List(ctx, l); err != nil {
+		return nil, err
+	}
+	svcs := make([]interfaces.SpinnakerService, 0)
+	for _, s := range l.GetItems() {
+		ok, err := IsSelected(ctx, c, s, acc)
+		if err != nil {
+			return nil, err
+		}
+		if ok || isIncluded(s, acc) {
+			svcs = append(svcs, s)
+		}
+	}
+	return svcs, nil
+}
+
+func isIncluded(spinsvc interfaces.SpinnakerService, acc interfaces.SpinnakerAccount) bool {
+	for _, a := range spinsvc.GetStatus().Accounts {
+		if a.Namespace == acc.GetNamespace() && a.Name == acc.GetName() {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectedAccounts returns the accounts selected by the SpinnakerService, whether they're enabled or not
+func SelectedAccounts(ctx context.Context, c client.Client, spinsvc interfaces.SpinnakerService) ([]interfaces.SpinnakerAccount, error) {
+	return listCRDAccounts(ctx, c, spinsvc)
+}
+
+// listCRDAccounts lists accounts selected by the SpinnakerService, starting with accounts of its namespace. Accounts of
+// a namespace are sorted by name.
+func listCRDAccounts(ctx context.Context, c client.Client, spinsvc interfaces.SpinnakerService) ([]interfaces.SpinnakerAccount, error) {
+	sel, err := accountSelector(spinsvc)
+	if err != nil {
+		return nil, err
+	}
+	nss, err := selectedNamespaces(ctx, c, spinsvc)
+	if err != nil {
+		return nil, err
+	}
+	accs := make([]interfaces.SpinnakerAccount, 0)
+	for _, ns := range nss {
+		l := TypesFactory.NewAccountList()
+		if err := c.List(ctx, l, client.InNamespace(ns), client.MatchingLabelsSelector{Selector: sel}); err != nil {
+			return nil, err
+		}
+		items := l.GetItems()
+		sort.Slice(items, func(i, j int) bool { return items[i].GetName() < items[j].GetName() })
+		accs = append(accs, items...)
+	}
+	return accs, nil
+}
+
+// selectedNamespaces returns the SpinnakerService namespace followed by other namespaces matching
+// spec.accounts.namespaceSelector in alphabetical order
+func selectedNamespaces(ctx context.Context, c client.Client, spinsvc interfaces.SpinnakerService) ([]string, error) {
+	nss := []string{spinsvc.GetNamespace()}
+	sel, err := namespaceSelector(spinsvc)
+	if err != nil || sel == nil {
+		return nss, err
+	}
+	l := &corev1.NamespaceList{}
+	if err := c.List(ctx, l, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+		return nil, err
+	}
+	others := make([]string, 0)
+	for _, n := range l.Items {
+		if n.Name != spinsvc.GetNamespace() {
+			others = append(others, n.Name)
+		}
+	}
+	sort.Strings(others)
+	return append(nss, others...), nil
+}
+
+// namespaceSelector returns nil if no other namespace is selected
+func namespaceSelector(spinsvc interfaces.SpinnakerService) (labels.Selector, error) {
+	s := spinsvc.GetAccountConfig().NamespaceSelector
+	if s == nil {
+		return nil, nil
+	}
+	return metav1.LabelSelectorAsSelector(s)
+}
+
+func accountSelector(spinsvc interfaces.SpinnakerService) (labels.Selector, error) {
+	s := spinsvc.GetAccountConfig().Selector
+	if s == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(s)
+}
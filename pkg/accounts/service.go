@@ -2,13 +2,18 @@ package accounts
 
 import (
 	"context"
+	yamlsecrets "github.com/armory/go-yaml-tools/pkg/secrets"
 	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/util"
 )
 
-func foundIn(obj string, list []string) bool {
+// foundIn returns true if the service is in the list, HA services (e.g. clouddriver-rw) match their base service
+func foundIn(svc string, list []string) bool {
 	for _, s := range list {
-		if s == obj {
+		if util.IsServiceLike(svc, s) {
 			return true
 		}
 	}
@@ -27,6 +32,12 @@ func PrepareSettings(ctx context.Context, svc string, accountList []account.Acco
 		if !foundIn(svc, aType.GetServices()) {
 			continue
 		}
+		if st, ok := aType.(account.SingletonAccountType); ok && st.IsSingleton() {
+			if err := prepareSingletonSettings(ctx, ss, accountType, accountList); err != nil {
+				return ss, err
+			}
+			continue
+		}
 		aSettings := make([]map[string]interface{}, 0)
 		for _, a := range accountList {
 			if a.GetType() == accountType {
@@ -37,8 +48,20 @@ func PrepareSettings(ctx context.Context, svc string, accountList []account.Acco
 				aSettings = append(aSettings, m)
 			}
 		}
+		if len(aSettings) == 0 {
+			continue
+		}
+		withAccounts := true
+		if st, ok := aType.(account.AccountTypeWithServiceSettings); ok {
+			for k, v := range st.GetServiceSettings(svc) {
+				if err := inspect.SetObjectProp(ss, k, v); err != nil {
+					return ss, err
+				}
+			}
+			withAccounts = st.RendersAccounts(svc)
+		}
 		// And that slice to the service settings under the type key (e.g. provider.kubernetes.accounts)
-		if len(aSettings) > 0 {
+		if withAccounts {
 			if err := inspect.SetObjectProp(ss, aType.GetAccountsKey(), aSettings); err != nil {
 				return ss, err
 			}
@@ -46,3 +69,57 @@ func PrepareSettings(ctx context.Context, svc string, accountList []account.Acco
 	}
 	return ss, nil
 }
+
+// prepareSingletonSettings sets the settings of the first account of the given type. Other SpinnakerAccount objects of
+// the type are excluded when selected (see excludeExtraSingletons).
+func prepareSingletonSettings(ctx context.Context, ss map[string]interface{}, accountType interfaces.AccountType, accountList []account.Account) error {
+	for _, a := range accountList {
+		if a.GetType() != accountType {
+			continue
+		}
+		m, err := a.ToSpinnakerSettings(ctx)
+		if err != nil {
+			return err
+		}
+		for k, v := range m {
+			if err := inspect.SetObjectProp(ss, k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// RendersAccountsIn returns true if accounts of the type are rendered as a list in the settings of the service
+func RendersAccountsIn(aType account.SpinnakerAccountType, svc string) bool {
+	if !foundIn(svc, aType.GetServices()) {
+		return false
+	}
+	if st, ok := aType.(account.SingletonAccountType); ok && st.IsSingleton() {
+		return false
+	}
+	if st, ok := aType.(account.AccountTypeWithServiceSettings); ok {
+		return st.RendersAccounts(svc)
+	}
+	return true
+}
+
+// DecodeSecrets replaces Kubernetes secret and config map references in settings with their values, for settings
+// written to services without going through the deployment of the SpinnakerService (e.g. dynamic accounts). Services
+// can't read references to secrets that are not mounted in their pods. File references are left as is.
+func DecodeSecrets(ctx context.Context, settings map[string]interface{}) (map[string]interface{}, error) {
+	h := func(val string) (string, error) {
+		e, isFile, _ := yamlsecrets.GetEngine(val)
+		if isFile || (e != secrets.KubernetesSecretEngine && e != secrets.KubernetesConfigMapEngine) {
+			return val, nil
+		}
+		v, _, err := secrets.Decode(ctx, val)
+		return v, err
+	}
+	res, err := inspect.InspectStrings(settings, h)
+	if err != nil {
+		return nil, err
+	}
+	return res.(map[string]interface{}), nil
+}
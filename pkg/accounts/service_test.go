@@ -3,7 +3,10 @@ package accounts
 import (
 	"context"
 	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/accounts/azure"
+	"github.com/armory/spinnaker-operator/pkg/accounts/ci"
 	"github.com/armory/spinnaker-operator/pkg/accounts/kubernetes"
+	"github.com/armory/spinnaker-operator/pkg/accounts/notifications"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/armory/spinnaker-operator/pkg/inspect"
 	"github.com/armory/spinnaker-operator/pkg/test"
@@ -42,3 +45,113 @@ func TestPrepareSettings(t *testing.T) {
 		}
 	}
 }
+
+func TestPrepareSettingsForRosco(t *testing.T) {
+	acc1 := &kubernetes.Account{
+		Name:     "account1",
+		Auth:     &interfaces.KubernetesAuth{KubeconfigFile: "/tmp/kubeconfig-1.yml"},
+		Env:      kubernetes.Env{},
+		Settings: interfaces.FreeForm{},
+	}
+	acc2 := &azure.Account{
+		Name: "azure1",
+		Azure: interfaces.AzureAccount{
+			ClientId: "11111111-1111-1111-1111-111111111111",
+			Packer:   &interfaces.AzurePacker{ResourceGroup: "packer"},
+		},
+		Settings: interfaces.FreeForm{},
+	}
+	ss, err := PrepareSettings(context.TODO(), "rosco", []account.Account{acc1, acc2})
+	if assert.Nil(t, err) {
+		_, ok := ss["kubernetes"]
+		assert.False(t, ok)
+		n, err := inspect.GetObjectPropString(context.TODO(), ss, "azure.accounts.0.packerResourceGroup")
+		if assert.Nil(t, err) {
+			assert.Equal(t, "packer", n)
+		}
+	}
+}
+
+func TestPrepareSettingsForCI(t *testing.T) {
+	acc1 := &kubernetes.Account{
+		Name:     "account1",
+		Auth:     &interfaces.KubernetesAuth{KubeconfigFile: "/tmp/kubeconfig-1.yml"},
+		Env:      kubernetes.Env{},
+		Settings: interfaces.FreeForm{},
+	}
+	acc2 := &ci.JenkinsAccount{
+		Name:     "jenkins1",
+		Jenkins:  interfaces.JenkinsAccount{Address: "https://jenkins.example.com"},
+		Settings: interfaces.FreeForm{},
+	}
+	accountList := []account.Account{acc1, acc2}
+
+	ss, err := PrepareSettings(context.TODO(), "igor", accountList)
+	if assert.Nil(t, err) {
+		_, ok := ss["kubernetes"]
+		assert.False(t, ok)
+		n, err := inspect.GetObjectPropString(context.TODO(), ss, "jenkins.masters.0.address")
+		if assert.Nil(t, err) {
+			assert.Equal(t, "https://jenkins.example.com", n)
+		}
+		e, err := inspect.GetObjectPropBool(ss, "jenkins.enabled", false)
+		if assert.Nil(t, err) {
+			assert.True(t, e)
+		}
+	}
+
+	ss, err = PrepareSettings(context.TODO(), "echo", accountList)
+	if assert.Nil(t, err) {
+		_, ok := ss["jenkins"]
+		assert.False(t, ok)
+		e, err := inspect.GetObjectPropBool(ss, "igor.enabled", false)
+		if assert.Nil(t, err) {
+			assert.True(t, e)
+		}
+	}
+
+	// HA services get the accounts of their base service
+	ss, err = PrepareSettings(context.TODO(), "clouddriver-rw", accountList)
+	if assert.Nil(t, err) {
+		n, err := inspect.GetObjectPropString(context.TODO(), ss, "kubernetes.accounts.0.name")
+		if assert.Nil(t, err) {
+			assert.Equal(t, "account1", n)
+		}
+	}
+}
+
+func TestPrepareSettingsForSingletons(t *testing.T) {
+	acc1 := &notifications.SMTPAccount{
+		Name: "mail",
+		SMTP: interfaces.SMTPAccount{Host: "smtp.example.com", From: "spinnaker@example.com", StartTls: true},
+	}
+	acc2 := &notifications.MicrosoftTeamsAccount{
+		Name:           "teams1",
+		MicrosoftTeams: interfaces.MicrosoftTeamsAccount{WebhookUrl: "https://contoso.webhook.office.com/webhookb2/1"},
+	}
+	acc3 := &notifications.MicrosoftTeamsAccount{
+		Name:           "teams2",
+		MicrosoftTeams: interfaces.MicrosoftTeamsAccount{WebhookUrl: "https://contoso.webhook.office.com/webhookb2/2"},
+	}
+	ss, err := PrepareSettings(context.TODO(), "echo", []account.Account{acc1, acc2, acc3})
+	if !assert.Nil(t, err) {
+		return
+	}
+	h, err := inspect.GetObjectPropString(context.TODO(), ss, "spring.mail.host")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "smtp.example.com", h)
+	}
+	e, err := inspect.GetObjectPropBool(ss, "spring.mail.properties.mail.smtp.starttls.enable", false)
+	if assert.Nil(t, err) {
+		assert.True(t, e)
+	}
+	e, err = inspect.GetObjectPropBool(ss, "mail.enabled", false)
+	if assert.Nil(t, err) {
+		assert.True(t, e)
+	}
+	// Only the first Teams account is used
+	u, err := inspect.GetObjectPropString(context.TODO(), ss, "microsoftteams.webhookUrl")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "https://contoso.webhook.office.com/webhookb2/1", u)
+	}
+}
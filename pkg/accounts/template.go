@@ -0,0 +1,169 @@
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// A SpinnakerAccountTemplate generates a SpinnakerAccount per set of parameters of its generator. String values
+// of the template are Go templates executed with the parameters.
+// TemplateLabel is set on generated SpinnakerAccounts to the name of their template.
+const TemplateLabel = "spinnaker.io/account-template"
+
+// TemplateParameters are the parameters of a generated account. "name" is always set.
+type TemplateParameters map[string]interface{}
+
+// Name returns the name parameter, used to name the generated account
+func (p TemplateParameters) Name() string {
+	n, _ := p["name"].(string)
+	return n
+}
+
+// GenerateParameters returns the parameters of each account of the template, sorted by name
+func GenerateParameters(ctx context.Context, c client.Client, tmpl interfaces.SpinnakerAccountTemplate) ([]TemplateParameters, error) {
+	gen := tmpl.GetSpec().Generator
+	params := make([]TemplateParameters, 0)
+	for i, l := range gen.List {
+		p := TemplateParameters{}
+		for k, v := range l {
+			p[k] = v
+		}
+		if p.Name() == "" {
+			return nil, fmt.Errorf("generator.list[%d] has no name", i)
+		}
+		params = append(params, p)
+	}
+	if gen.NamespaceSelector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(gen.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+		l := &corev1.NamespaceList{}
+		if err := c.List(ctx, l, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+			return nil, err
+		}
+		for _, n := range l.Items {
+			params = append(params, TemplateParameters{"name": n.Name, "namespace": n.Name, "labels": n.Labels})
+		}
+	}
+	if gen.ConfigMap != "" {
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: tmpl.GetNamespace(), Name: gen.ConfigMap}, cm); err != nil {
+			return nil, err
+		}
+		for k, v := range cm.Data {
+			p := TemplateParameters{}
+			if err := yaml.Unmarshal([]byte(v), &p); err != nil {
+				return nil, fmt.Errorf("unable to parse key %s of config map %s: %w", k, gen.ConfigMap, err)
+			}
+			if p == nil {
+				p = TemplateParameters{}
+			}
+			p["name"] = k
+			params = append(params, p)
+		}
+	}
+
+	sort.SliceStable(params, func(i, j int) bool {
+		return params[i].Name() < params[j].Name()
+	})
+	for i := 1; i < len(params); i++ {
+		if params[i].Name() == params[i-1].Name() {
+			return nil, fmt.Errorf("account %s generated more than once", params[i].Name())
+		}
+	}
+	return params, nil
+}
+
+// GeneratedAccountName returns the name of the SpinnakerAccount generated for the parameters
+func GeneratedAccountName(tmpl interfaces.SpinnakerAccountTemplate, params TemplateParameters) string {
+	return fmt.Sprintf("%s-%s", tmpl.GetName(), params.Name())
+}
+
+// RenderAccount returns the SpinnakerAccount generated from the template for the parameters. The account name
+// must be a valid DNS-1123 subdomain and the account is parsed by its account type like any other SpinnakerAccount.
+func RenderAccount(tmpl interfaces.SpinnakerAccountTemplate, params TemplateParameters) (interfaces.SpinnakerAccount, error) {
+	b, err := json.Marshal(tmpl.GetSpec().Template)
+	if err != nil {
+		return nil, err
+	}
+	var raw interface{}
+	if err = json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	if raw, err = renderValue(raw, params); err != nil {
+		return nil, err
+	}
+	if b, err = json.Marshal(raw); err != nil {
+		return nil, err
+	}
+
+	name := GeneratedAccountName(tmpl, params)
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid account name %s: %s", name, strings.Join(errs, ", "))
+	}
+	acc := TypesFactory.NewAccount()
+	acc.SetName(name)
+	acc.SetNamespace(tmpl.GetNamespace())
+	acc.SetLabels(map[string]string{TemplateLabel: tmpl.GetName()})
+	if err = json.Unmarshal(b, acc.GetSpec()); err != nil {
+		return nil, err
+	}
+
+	aType, err := GetType(acc.GetSpec().Type)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = aType.FromCRD(acc); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// renderValue executes string values containing templates
+func renderValue(v interface{}, params TemplateParameters) (interface{}, error) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			r, err := renderValue(val, params)
+			if err != nil {
+				return nil, err
+			}
+			t[k] = r
+		}
+	case []interface{}:
+		for i, val := range t {
+			r, err := renderValue(val, params)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = r
+		}
+	case string:
+		if !strings.Contains(t, "{{") {
+			return t, nil
+		}
+		tp, err := template.New("").Option("missingkey=error").Parse(t)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err = tp.Execute(&buf, map[string]interface{}(params)); err != nil {
+			return nil, err
+		}
+		return buf.String(), nil
+	}
+	return v, nil
+}
@@ -0,0 +1,111 @@
+package accounts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTemplate(gen interfaces.AccountGenerator) *v1alpha2.SpinnakerAccountTemplate {
+	return &v1alpha2.SpinnakerAccountTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "spinnaker"},
+		Spec: interfaces.SpinnakerAccountTemplateSpec{
+			Template: interfaces.SpinnakerAccountSpec{
+				Type:    interfaces.KubernetesAccountType,
+				Enabled: true,
+				Kubernetes: &interfaces.KubernetesAuth{
+					Provision: &interfaces.KubernetesProvision{Namespaces: []string{"{{ .namespace }}"}},
+				},
+				Settings: interfaces.FreeForm{
+					"environment":          "{{ .name }}",
+					"onlySpinnakerManaged": true,
+				},
+			},
+			Generator: gen,
+		},
+	}
+}
+
+func newTemplateClient(t *testing.T, objs ...client.Object) client.Client {
+	s := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{corev1.AddToScheme, v1alpha2.SchemeBuilder.AddToScheme} {
+		if err := add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func TestGenerateParameters(t *testing.T) {
+	ns := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "teams", Namespace: "spinnaker"},
+		Data: map[string]string{
+			"b": "namespace: team-b\n",
+			"a": "namespace: team-a\n",
+		},
+	}
+	c := newTemplateClient(t, cm, ns("team-c", map[string]string{"team": "true"}), ns("other", nil))
+
+	tmpl := newTemplate(interfaces.AccountGenerator{
+		List:              []map[string]string{{"name": "d", "namespace": "team-d"}},
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "true"}},
+		ConfigMap:         "teams",
+	})
+	params, err := GenerateParameters(context.TODO(), c, tmpl)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []TemplateParameters{
+			{"name": "a", "namespace": "team-a"},
+			{"name": "b", "namespace": "team-b"},
+			{"name": "d", "namespace": "team-d"},
+			{"name": "team-c", "namespace": "team-c", "labels": map[string]string{"team": "true"}},
+		}, params)
+	}
+
+	tmpl.Spec.Generator.List = append(tmpl.Spec.Generator.List, map[string]string{"name": "a"})
+	_, err = GenerateParameters(context.TODO(), c, tmpl)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "account a generated more than once", err.Error())
+	}
+
+	_, err = GenerateParameters(context.TODO(), c, newTemplate(interfaces.AccountGenerator{List: []map[string]string{{"namespace": "ns"}}}))
+	assert.NotNil(t, err)
+}
+
+func TestRenderAccount(t *testing.T) {
+	tmpl := newTemplate(interfaces.AccountGenerator{})
+	acc, err := RenderAccount(tmpl, TemplateParameters{"name": "a", "namespace": "team-a"})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "team-a", acc.GetName())
+	assert.Equal(t, "spinnaker", acc.GetNamespace())
+	assert.Equal(t, "team", acc.GetLabels()[TemplateLabel])
+	assert.Equal(t, []string{"team-a"}, acc.GetSpec().Kubernetes.Provision.Namespaces)
+	assert.Equal(t, "a", acc.GetSpec().Settings["environment"])
+	assert.Equal(t, true, acc.GetSpec().Settings["onlySpinnakerManaged"])
+	// The template is left untouched
+	assert.Equal(t, "{{ .name }}", tmpl.Spec.Template.Settings["environment"])
+
+	_, err = RenderAccount(tmpl, TemplateParameters{"name": "a"})
+	assert.NotNil(t, err, "missing parameter")
+
+	_, err = RenderAccount(tmpl, TemplateParameters{"name": "my_cluster", "namespace": "team-a"})
+	if assert.NotNil(t, err, "invalid account name") {
+		assert.Contains(t, err.Error(), "invalid account name team-my_cluster")
+	}
+
+	tmpl.Spec.Template.Kubernetes = nil
+	_, err = RenderAccount(tmpl, TemplateParameters{"name": "a", "namespace": "team-a"})
+	assert.NotNil(t, err, "account type validation")
+}
@@ -15,8 +15,25 @@ const (
 	LatestVersion   = V1alpha2Version
 )
 const (
-	KubernetesAccountType AccountType = "Kubernetes"
-	AWSAccountType                    = "AWS"
+	KubernetesAccountType     AccountType = "Kubernetes"
+	AWSAccountType                        = "AWS"
+	DockerRegistryAccountType             = "DockerRegistry"
+	GoogleAccountType                     = "Google"
+	AzureAccountType                      = "Azure"
+	CloudFoundryAccountType               = "CloudFoundry"
+	ECSAccountType                        = "ECS"
+	GitHubArtifactAccountType             = "GitHubArtifact"
+	GitLabArtifactAccountType             = "GitLabArtifact"
+	S3ArtifactAccountType                 = "S3Artifact"
+	GCSArtifactAccountType                = "GCSArtifact"
+	HelmArtifactAccountType               = "HelmArtifact"
+	HTTPArtifactAccountType               = "HTTPArtifact"
+	JenkinsAccountType                    = "Jenkins"
+	GitHubActionsAccountType              = "GitHubActions"
+	GitLabCIAccountType                   = "GitLabCI"
+	SlackAccountType                      = "Slack"
+	SMTPAccountType                       = "SMTP"
+	MicrosoftTeamsAccountType             = "MicrosoftTeams"
 )
 const (
 	Read    Authorization = "READ"
@@ -45,6 +62,8 @@ type TypesFactory interface {
 	NewServiceList() SpinnakerServiceList
 	NewAccount() SpinnakerAccount
 	NewAccountList() SpinnakerAccountList
+	NewAccountTemplate() SpinnakerAccountTemplate
+	NewAccountTemplateList() SpinnakerAccountTemplateList
 	GetGroupVersion() schema.GroupVersion
 	DeepCopyLatestTypesFactory() TypesFactory
 }
@@ -88,6 +107,29 @@ type SpinnakerAccount interface {
 	DeepCopySpinnakerAccount() SpinnakerAccount
 }
 
+// +kubebuilder:object:generate=false
+type SpinnakerAccountTemplate interface {
+	v1.Object
+	runtime.Object
+	GetSpec() *SpinnakerAccountTemplateSpec
+	GetStatus() *SpinnakerAccountTemplateStatus
+	DeepCopyInterface() SpinnakerAccountTemplate
+}
+
+// +kubebuilder:object:generate=false
+type SpinnakerAccountTemplateList interface {
+	runtime.Object
+	GetItems() []SpinnakerAccountTemplate
+	GetResourceVersion() string
+	SetResourceVersion(c string)
+	GetSelfLink() string
+	SetSelfLink(c string)
+	GetContinue() string
+	SetContinue(c string)
+	GetRemainingItemCount() *int64
+	SetRemainingItemCount(c *int64)
+}
+
 // +kubebuilder:object:generate=false
 type SpinnakerAccountList interface {
 	runtime.Object
@@ -183,6 +225,8 @@ type SpinnakerValidation struct {
 	// +optional
 	Notifications map[string]ValidationSetting `json:"notifications,omitempty"`
 	// +optional
+	Artifacts map[string]ValidationSetting `json:"artifacts,omitempty"`
+	// +optional
 	CI map[string]ValidationSetting `json:"ci,omitempty"`
 	// +optional
 	Pubsub map[string]ValidationSetting `json:"pubsub,omitempty"`
@@ -195,6 +239,8 @@ type SpinnakerValidation struct {
 type ExposeConfig struct {
 	Type    string              `json:"type,omitempty"`
 	Service ExposeConfigService `json:"service,omitempty"`
+	// +optional
+	Istio ExposeConfigIstio `json:"istio,omitempty"`
 }
 
 // ExposeConfigService represents the configuration for exposing Spinnaker using k8s services
@@ -214,12 +260,48 @@ type ExposeConfigServiceOverrides struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
+// ExposeConfigIstio represents the configuration for exposing Spinnaker through an Istio gateway
+// +k8s:openapi-gen=true
+type ExposeConfigIstio struct {
+	// Gateway the generated VirtualServices are bound to, as <namespace>/<name> or <name>
+	Gateway string `json:"gateway,omitempty"`
+	// Hosts by Spinnaker service name (e.g. deck, gate)
+	// +optional
+	Hosts map[string]ExposeConfigIstioHost `json:"hosts,omitempty"`
+	// TLS mode of the generated DestinationRules (e.g. ISTIO_MUTUAL)
+	// +optional
+	TLSMode string `json:"tlsMode,omitempty"`
+	// Label Spinnaker pods for Istio sidecar injection
+	// +optional
+	SidecarInjection bool `json:"sidecarInjection,omitempty"`
+}
+
+// ExposeConfigIstioHost represents the public address of a Spinnaker service exposed through Istio
+// +k8s:openapi-gen=true
+type ExposeConfigIstioHost struct {
+	Host string `json:"host"`
+	// Path prefix routed to the service, defaults to /
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Scheme of the public URL, defaults to https
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
+}
+
 // +k8s:openapi-gen=true
 type AccountConfig struct {
 	// Enable the injection of SpinnakerAccount
 	Enabled bool `json:"enabled,omitempty"`
 	// Enable accounts to be added dynamically
 	Dynamic bool `json:"dynamic,omitempty"`
+	// Exclude accounts whose last validation failed
+	ExcludeInvalid bool `json:"excludeInvalid,omitempty"`
+	// Namespaces other than the SpinnakerService's to read SpinnakerAccount from
+	// +optional
+	NamespaceSelector *v1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Labels of the SpinnakerAccount to include, defaults to all
+	// +optional
+	Selector *v1.LabelSelector `json:"selector,omitempty"`
 }
 
 // SpinnakerServiceSpec defines the desired state of SpinnakerService
@@ -279,9 +361,23 @@ type SpinnakerServiceStatus struct {
 	// Exposed Gate URL
 	// +optional
 	APIUrl string `json:"apiUrl"`
+	// Exposed URLs by Spinnaker service name
+	// +optional
+	ServiceUrls map[string]string `json:"serviceUrls,omitempty"`
 	// Number of accounts
 	// +optional
 	AccountCount int `json:"accountCount,omitempty"`
+	// SpinnakerAccount included in Spinnaker's settings
+	// +optional
+	Accounts []IncludedAccount `json:"accounts,omitempty"`
+}
+
+// IncludedAccount references a SpinnakerAccount included in Spinnaker's settings
+// +k8s:openapi-gen=true
+type IncludedAccount struct {
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace"`
+	Type      AccountType `json:"type"`
 }
 
 // +k8s:openapi-gen=true
@@ -302,6 +398,42 @@ type SpinnakerAccountSpec struct {
 	// +optional
 	Kubernetes *KubernetesAuth `json:"kubernetes,omitempty"`
 	// +optional
+	AWS *AWSAccount `json:"aws,omitempty"`
+	// +optional
+	DockerRegistry *DockerRegistryAccount `json:"dockerRegistry,omitempty"`
+	// +optional
+	Google *GoogleAccount `json:"google,omitempty"`
+	// +optional
+	Azure *AzureAccount `json:"azure,omitempty"`
+	// +optional
+	CloudFoundry *CloudFoundryAccount `json:"cloudFoundry,omitempty"`
+	// +optional
+	ECS *ECSAccount `json:"ecs,omitempty"`
+	// +optional
+	GitHubArtifact *GitHubArtifactAccount `json:"githubArtifact,omitempty"`
+	// +optional
+	GitLabArtifact *GitLabArtifactAccount `json:"gitlabArtifact,omitempty"`
+	// +optional
+	S3Artifact *S3ArtifactAccount `json:"s3Artifact,omitempty"`
+	// +optional
+	GCSArtifact *GCSArtifactAccount `json:"gcsArtifact,omitempty"`
+	// +optional
+	HelmArtifact *HelmArtifactAccount `json:"helmArtifact,omitempty"`
+	// +optional
+	HTTPArtifact *HTTPArtifactAccount `json:"httpArtifact,omitempty"`
+	// +optional
+	Jenkins *JenkinsAccount `json:"jenkins,omitempty"`
+	// +optional
+	GitHubActions *GitHubActionsAccount `json:"githubActions,omitempty"`
+	// +optional
+	GitLabCI *GitLabCIAccount `json:"gitlabCI,omitempty"`
+	// +optional
+	Slack *SlackAccount `json:"slack,omitempty"`
+	// +optional
+	SMTP *SMTPAccount `json:"smtp,omitempty"`
+	// +optional
+	MicrosoftTeams *MicrosoftTeamsAccount `json:"microsoftTeams,omitempty"`
+	// +optional
 	Settings FreeForm `json:"settings,omitempty"`
 }
 
@@ -319,6 +451,304 @@ type KubernetesAuth struct {
 	// UseServiceAccount authenticate to the target cluster using the service account mounted in Spinnaker's pods
 	// +optional
 	UseServiceAccount bool `json:"useServiceAccount"`
+	// Provision a service account with access to namespaces of the cluster the operator runs in
+	// +optional
+	Provision *KubernetesProvision `json:"provision,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type KubernetesProvision struct {
+	// Namespaces Spinnaker can deploy to
+	Namespaces []string `json:"namespaces"`
+	// ClusterRole bound to the service account in each namespace, defaults to edit
+	// +optional
+	Role string `json:"role,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type AWSAccount struct {
+	// AccountId is the id of the AWS account
+	AccountId string `json:"accountId"`
+	// AssumeRole is the role Spinnaker assumes in the account (e.g. role/spinnakerManaged)
+	// +optional
+	AssumeRole string `json:"assumeRole,omitempty"`
+	// ExternalId passed when assuming the role
+	// +optional
+	ExternalId string `json:"externalId,omitempty"`
+	// Regions to manage in the account, defaults to the provider's default regions
+	// +optional
+	Regions []AWSRegion `json:"regions,omitempty"`
+	// +optional
+	LifecycleHooks []AWSLifecycleHook `json:"lifecycleHooks,omitempty"`
+	// +optional
+	Lambda *AWSLambda `json:"lambda,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type AWSRegion struct {
+	Name string `json:"name"`
+}
+
+// +k8s:openapi-gen=true
+type AWSLifecycleHook struct {
+	DefaultResult         string `json:"defaultResult,omitempty"`
+	HeartbeatTimeout      int32  `json:"heartbeatTimeout,omitempty"`
+	LifecycleTransition   string `json:"lifecycleTransition,omitempty"`
+	NotificationTargetARN string `json:"notificationTargetARN,omitempty"`
+	RoleARN               string `json:"roleARN,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type AWSLambda struct {
+	// Enabled makes the account available for Lambda deployments
+	Enabled bool `json:"enabled"`
+}
+
+// +k8s:openapi-gen=true
+type DockerRegistryAccount struct {
+	// Address of the registry (e.g. index.docker.io)
+	Address string `json:"address"`
+	// +optional
+	Username string `json:"username,omitempty"`
+	// PasswordSecret references the registry password in a Kubernetes secret
+	// +optional
+	PasswordSecret *SecretInNamespaceReference `json:"passwordSecret,omitempty"`
+	// DockerconfigSecret references a dockerconfigjson in a Kubernetes secret holding credentials for the registry
+	// +optional
+	DockerconfigSecret *SecretInNamespaceReference `json:"dockerconfigSecret,omitempty"`
+	// +optional
+	Email string `json:"email,omitempty"`
+	// Repositories to cache, all repositories of the registry are cached if empty
+	// +optional
+	Repositories []string `json:"repositories,omitempty"`
+	// +optional
+	CacheIntervalSeconds int64 `json:"cacheIntervalSeconds,omitempty"`
+	// +optional
+	CacheThreads int32 `json:"cacheThreads,omitempty"`
+	// +optional
+	ClientTimeoutMillis int64 `json:"clientTimeoutMillis,omitempty"`
+	// +optional
+	PaginateSize int32 `json:"paginateSize,omitempty"`
+	// +optional
+	SortTagsByDate bool `json:"sortTagsByDate,omitempty"`
+	// +optional
+	TrackDigests bool `json:"trackDigests,omitempty"`
+	// +optional
+	InsecureRegistry bool `json:"insecureRegistry,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type GoogleAccount struct {
+	// Project managed by the account
+	Project string `json:"project"`
+	// JsonKeySecret references the service account JSON key in a Kubernetes secret, Spinnaker's default credentials are used if not set
+	// +optional
+	JsonKeySecret *SecretInNamespaceReference `json:"jsonKeySecret,omitempty"`
+	// Regions to manage, all regions are managed if empty
+	// +optional
+	Regions []string `json:"regions,omitempty"`
+	// +optional
+	RequiredGroupMembership []string `json:"requiredGroupMembership,omitempty"`
+	// ImageProjects are additional projects to read images from
+	// +optional
+	ImageProjects []string `json:"imageProjects,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type AzureAccount struct {
+	// ClientId of the service principal
+	ClientId string `json:"clientId"`
+	// AppKeySecret references the service principal's secret (app key) in a Kubernetes secret
+	// +optional
+	AppKeySecret *SecretInNamespaceReference `json:"appKeySecret,omitempty"`
+	// TenantId of the Azure Active Directory the service principal belongs to
+	TenantId string `json:"tenantId"`
+	// SubscriptionId managed by the account
+	SubscriptionId string `json:"subscriptionId"`
+	// ObjectId of the service principal, required for key vault access policies
+	// +optional
+	ObjectId string `json:"objectId,omitempty"`
+	// DefaultResourceGroup holds the key vault and other resources created by Spinnaker
+	DefaultResourceGroup string `json:"defaultResourceGroup"`
+	// DefaultKeyVault holds the credentials of the VMs deployed by Spinnaker
+	DefaultKeyVault string `json:"defaultKeyVault"`
+	// Regions to manage, defaults to Clouddriver's default regions
+	// +optional
+	Regions []string `json:"regions,omitempty"`
+	// Packer settings used by Rosco when baking images in this account
+	// +optional
+	Packer *AzurePacker `json:"packer,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type AzurePacker struct {
+	// ResourceGroup in which Packer creates its temporary resources
+	// +optional
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+	// StorageAccount in which baked images are stored
+	// +optional
+	StorageAccount string `json:"storageAccount,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type CloudFoundryAccount struct {
+	// Api is the host of the foundation's API (e.g. api.sys.example.com)
+	Api string `json:"api"`
+	// +optional
+	AppsManagerUri string `json:"appsManagerUri,omitempty"`
+	// +optional
+	MetricsUri string `json:"metricsUri,omitempty"`
+	// User authenticating to the foundation
+	User string `json:"user"`
+	// PasswordSecret references the user's password in a Kubernetes secret
+	// +optional
+	PasswordSecret *SecretInNamespaceReference `json:"passwordSecret,omitempty"`
+	// +optional
+	Environment string `json:"environment,omitempty"`
+	// +optional
+	SkipSslValidation bool `json:"skipSslValidation,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type ECSAccount struct {
+	// AwsAccount is the name of the AWS SpinnakerAccount providing credentials and regions
+	AwsAccount string `json:"awsAccount"`
+}
+
+// +k8s:openapi-gen=true
+type GitHubArtifactAccount struct {
+	// +optional
+	Username string `json:"username,omitempty"`
+	// PasswordSecret references the user's password in a Kubernetes secret
+	// +optional
+	PasswordSecret *SecretInNamespaceReference `json:"passwordSecret,omitempty"`
+	// TokenSecret references a personal access token in a Kubernetes secret
+	// +optional
+	TokenSecret *SecretInNamespaceReference `json:"tokenSecret,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type GitLabArtifactAccount struct {
+	// TokenSecret references a personal access token in a Kubernetes secret
+	// +optional
+	TokenSecret *SecretInNamespaceReference `json:"tokenSecret,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type S3ArtifactAccount struct {
+	// ApiEndpoint of an S3 compatible storage, defaults to AWS
+	// +optional
+	ApiEndpoint string `json:"apiEndpoint,omitempty"`
+	// +optional
+	ApiRegion string `json:"apiRegion,omitempty"`
+	// +optional
+	Region string `json:"region,omitempty"`
+	// AwsAccessKeyId is used with AwsSecretAccessKeySecret, Clouddriver's default credentials are used if not set
+	// +optional
+	AwsAccessKeyId string `json:"awsAccessKeyId,omitempty"`
+	// AwsSecretAccessKeySecret references the secret access key in a Kubernetes secret
+	// +optional
+	AwsSecretAccessKeySecret *SecretInNamespaceReference `json:"awsSecretAccessKeySecret,omitempty"`
+	// Bucket is checked with HeadBucket when the account is validated, it's not passed to Clouddriver
+	// +optional
+	Bucket string `json:"bucket,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type GCSArtifactAccount struct {
+	// JsonKeySecret references the service account JSON key in a Kubernetes secret, Clouddriver's default credentials are used if not set
+	// +optional
+	JsonKeySecret *SecretInNamespaceReference `json:"jsonKeySecret,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type HelmArtifactAccount struct {
+	// Repository is the URL of the chart repository
+	Repository string `json:"repository"`
+	// +optional
+	Username string `json:"username,omitempty"`
+	// PasswordSecret references the user's password in a Kubernetes secret
+	// +optional
+	PasswordSecret *SecretInNamespaceReference `json:"passwordSecret,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type HTTPArtifactAccount struct {
+	// +optional
+	Username string `json:"username,omitempty"`
+	// PasswordSecret references the user's password in a Kubernetes secret
+	// +optional
+	PasswordSecret *SecretInNamespaceReference `json:"passwordSecret,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type JenkinsAccount struct {
+	// Address of the Jenkins master
+	Address string `json:"address"`
+	// +optional
+	Username string `json:"username,omitempty"`
+	// PasswordSecret references the user's password or API token in a Kubernetes secret
+	// +optional
+	PasswordSecret *SecretInNamespaceReference `json:"passwordSecret,omitempty"`
+	// Csrf enables CSRF protection crumbs in requests
+	// +optional
+	Csrf bool `json:"csrf,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type GitHubActionsAccount struct {
+	// Organization owning the repositories whose workflows are listed
+	Organization string `json:"organization"`
+	// BaseUrl of GitHub's API, defaults to https://api.github.com
+	// +optional
+	BaseUrl string `json:"baseUrl,omitempty"`
+	// TokenSecret references a personal access token in a Kubernetes secret
+	TokenSecret *SecretInNamespaceReference `json:"tokenSecret"`
+}
+
+// +k8s:openapi-gen=true
+type GitLabCIAccount struct {
+	// Address of the GitLab instance
+	Address string `json:"address"`
+	// PrivateTokenSecret references a private token in a Kubernetes secret
+	// +optional
+	PrivateTokenSecret *SecretInNamespaceReference `json:"privateTokenSecret,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type SlackAccount struct {
+	// BotName is the name of the Slack bot posting notifications
+	BotName string `json:"botName"`
+	// TokenSecret references the bot token in a Kubernetes secret
+	TokenSecret *SecretInNamespaceReference `json:"tokenSecret"`
+	// BaseUrl of Slack's API, defaults to https://slack.com
+	// +optional
+	BaseUrl string `json:"baseUrl,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type SMTPAccount struct {
+	// Host of the SMTP server
+	Host string `json:"host"`
+	// Port of the SMTP server, defaults to 25
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// From is the sender address of notifications
+	From string `json:"from"`
+	// +optional
+	Username string `json:"username,omitempty"`
+	// PasswordSecret references the user's password in a Kubernetes secret
+	// +optional
+	PasswordSecret *SecretInNamespaceReference `json:"passwordSecret,omitempty"`
+	// StartTls upgrades connections to TLS
+	// +optional
+	StartTls bool `json:"startTls,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type MicrosoftTeamsAccount struct {
+	// WebhookUrl is the incoming webhook of the Teams channel
+	WebhookUrl string `json:"webhookUrl"`
 }
 
 // +k8s:openapi-gen=true
@@ -327,11 +757,62 @@ type SecretInNamespaceReference struct {
 	Key  string `json:"key"`
 }
 
+// SpinnakerAccountTemplateSpec defines SpinnakerAccount generated from a template
+// +k8s:openapi-gen=true
+type SpinnakerAccountTemplateSpec struct {
+	// Template of the spec of generated SpinnakerAccount. String values are Go templates of the parameters
+	// of each account, e.g. "{{ .namespace }}".
+	Template SpinnakerAccountSpec `json:"template"`
+	// Generator of the parameters of each SpinnakerAccount
+	Generator AccountGenerator `json:"generator"`
+}
+
+// AccountGenerator generates a set of parameters per SpinnakerAccount. Each set has a name: generated
+// SpinnakerAccount are named <template name>-<name>.
+// +k8s:openapi-gen=true
+type AccountGenerator struct {
+	// List of parameters, each with a name
+	// +optional
+	List []map[string]string `json:"list,omitempty"`
+	// NamespaceSelector generates parameters for each namespace matching the selector: name and namespace
+	// are the name of the namespace, labels its labels
+	// +optional
+	NamespaceSelector *v1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// ConfigMap in the namespace of the template. Each key generates parameters named after the key,
+	// read from the YAML map value.
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+}
+
+// SpinnakerAccountTemplateStatus defines the observed state of SpinnakerAccountTemplate
+// +k8s:openapi-gen=true
+type SpinnakerAccountTemplateStatus struct {
+	// Accounts generated from the template
+	// +optional
+	Accounts []string `json:"accounts,omitempty"`
+	// InvalidReason is set when some accounts couldn't be generated
+	// +optional
+	InvalidReason string `json:"invalidReason,omitempty"`
+}
+
 // SpinnakerAccountStatus defines the observed state of SpinnakerAccount
 // +k8s:openapi-gen=true
 type SpinnakerAccountStatus struct {
 	InvalidReason   string        `json:"invalidReason"`
 	LastValidatedAt *v1.Timestamp `json:"lastValidatedAt"`
+	// Hash of the spec that was last validated
+	// +optional
+	Hash string `json:"hash,omitempty"`
+	// CredentialsExpireAt is when credentials minted by the operator for the account expire
+	// +optional
+	CredentialsExpireAt *v1.Timestamp `json:"credentialsExpireAt,omitempty"`
+	// CredentialsRefreshAt is when the operator rotates credentials minted for the account
+	// +optional
+	CredentialsRefreshAt *v1.Timestamp `json:"credentialsRefreshAt,omitempty"`
+	// ExcludedReason is set when a valid account is left out of Spinnaker's services, e.g. because an account it
+	// references is missing or another account has the same name
+	// +optional
+	ExcludedReason string `json:"excludedReason,omitempty"`
 }
 
 var _ TypesFactory = &TypesFactoryImpl{}
@@ -357,6 +838,14 @@ func (f *TypesFactoryImpl) NewAccountList() SpinnakerAccountList {
 	return f.Factories[LatestVersion].NewAccountList()
 }
 
+func (f *TypesFactoryImpl) NewAccountTemplate() SpinnakerAccountTemplate {
+	return f.Factories[LatestVersion].NewAccountTemplate()
+}
+
+func (f *TypesFactoryImpl) NewAccountTemplateList() SpinnakerAccountTemplateList {
+	return f.Factories[LatestVersion].NewAccountTemplateList()
+}
+
 func (f *TypesFactoryImpl) GetGroupVersion() schema.GroupVersion {
 	return f.Factories[LatestVersion].GetGroupVersion()
 }
@@ -413,6 +902,13 @@ func (in *SpinnakerValidation) DeepCopyInto(out *SpinnakerValidation) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make(map[string]ValidationSetting, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	if in.CI != nil {
 		in, out := &in.CI, &out.CI
 		*out = make(map[string]ValidationSetting, len(*in))
@@ -473,6 +969,7 @@ func (in *ValidationSetting) DeepCopy() *ValidationSetting {
 func (in *ExposeConfig) DeepCopyInto(out *ExposeConfig) {
 	*out = *in
 	in.Service.DeepCopyInto(&out.Service)
+	in.Istio.DeepCopyInto(&out.Istio)
 	return
 }
 
@@ -539,9 +1036,42 @@ func (in *ExposeConfigServiceOverrides) DeepCopy() *ExposeConfigServiceOverrides
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposeConfigIstio) DeepCopyInto(out *ExposeConfigIstio) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make(map[string]ExposeConfigIstioHost, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeConfigIstio.
+func (in *ExposeConfigIstio) DeepCopy() *ExposeConfigIstio {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposeConfigIstio)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountConfig) DeepCopyInto(out *AccountConfig) {
 	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -556,60 +1086,609 @@ func (in *AccountConfig) DeepCopy() *AccountConfig {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HashStatus) DeepCopyInto(out *HashStatus) {
+func (in *SpinnakerAccountTemplateSpec) DeepCopyInto(out *SpinnakerAccountTemplateSpec) {
 	*out = *in
-	in.LastUpdatedAt.DeepCopyInto(&out.LastUpdatedAt)
+	in.Template.DeepCopyInto(&out.Template)
+	in.Generator.DeepCopyInto(&out.Generator)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HashStatus.
-func (in *HashStatus) DeepCopy() *HashStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpinnakerAccountTemplateSpec.
+func (in *SpinnakerAccountTemplateSpec) DeepCopy() *SpinnakerAccountTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HashStatus)
+	out := new(SpinnakerAccountTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubernetesAuth) DeepCopyInto(out *KubernetesAuth) {
+func (in *AccountGenerator) DeepCopyInto(out *AccountGenerator) {
 	*out = *in
-	if in.KubeconfigSecret != nil {
-		in, out := &in.KubeconfigSecret, &out.KubeconfigSecret
-		*out = new(SecretInNamespaceReference)
-		**out = **in
+	if in.List != nil {
+		in, out := &in.List, &out.List
+		*out = make([]map[string]string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+		}
 	}
-	if in.Kubeconfig != nil {
-		in, out := &in.Kubeconfig, &out.Kubeconfig
-		*out = new(clientv1.Config)
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesAuth.
-func (in *KubernetesAuth) DeepCopy() *KubernetesAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountGenerator.
+func (in *AccountGenerator) DeepCopy() *AccountGenerator {
 	if in == nil {
 		return nil
 	}
-	out := new(KubernetesAuth)
+	out := new(AccountGenerator)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretInNamespaceReference) DeepCopyInto(out *SecretInNamespaceReference) {
+func (in *SpinnakerAccountTemplateStatus) DeepCopyInto(out *SpinnakerAccountTemplateStatus) {
 	*out = *in
+	if in.Accounts != nil {
+		in, out := &in.Accounts, &out.Accounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretInNamespaceReference.
-func (in *SecretInNamespaceReference) DeepCopy() *SecretInNamespaceReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpinnakerAccountTemplateStatus.
+func (in *SpinnakerAccountTemplateStatus) DeepCopy() *SpinnakerAccountTemplateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretInNamespaceReference)
+	out := new(SpinnakerAccountTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IncludedAccount) DeepCopyInto(out *IncludedAccount) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncludedAccount.
+func (in *IncludedAccount) DeepCopy() *IncludedAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(IncludedAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HashStatus) DeepCopyInto(out *HashStatus) {
+	*out = *in
+	in.LastUpdatedAt.DeepCopyInto(&out.LastUpdatedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HashStatus.
+func (in *HashStatus) DeepCopy() *HashStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HashStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesAuth) DeepCopyInto(out *KubernetesAuth) {
+	*out = *in
+	if in.KubeconfigSecret != nil {
+		in, out := &in.KubeconfigSecret, &out.KubeconfigSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	if in.Kubeconfig != nil {
+		in, out := &in.Kubeconfig, &out.Kubeconfig
+		*out = new(clientv1.Config)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Provision != nil {
+		in, out := &in.Provision, &out.Provision
+		*out = new(KubernetesProvision)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesProvision) DeepCopyInto(out *KubernetesProvision) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesProvision.
+func (in *KubernetesProvision) DeepCopy() *KubernetesProvision {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesProvision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesAuth.
+func (in *KubernetesAuth) DeepCopy() *KubernetesAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSAccount) DeepCopyInto(out *AWSAccount) {
+	*out = *in
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]AWSRegion, len(*in))
+		copy(*out, *in)
+	}
+	if in.LifecycleHooks != nil {
+		in, out := &in.LifecycleHooks, &out.LifecycleHooks
+		*out = make([]AWSLifecycleHook, len(*in))
+		copy(*out, *in)
+	}
+	if in.Lambda != nil {
+		in, out := &in.Lambda, &out.Lambda
+		*out = new(AWSLambda)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSAccount.
+func (in *AWSAccount) DeepCopy() *AWSAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerRegistryAccount) DeepCopyInto(out *DockerRegistryAccount) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	if in.DockerconfigSecret != nil {
+		in, out := &in.DockerconfigSecret, &out.DockerconfigSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerRegistryAccount.
+func (in *DockerRegistryAccount) DeepCopy() *DockerRegistryAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerRegistryAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoogleAccount) DeepCopyInto(out *GoogleAccount) {
+	*out = *in
+	if in.JsonKeySecret != nil {
+		in, out := &in.JsonKeySecret, &out.JsonKeySecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequiredGroupMembership != nil {
+		in, out := &in.RequiredGroupMembership, &out.RequiredGroupMembership
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageProjects != nil {
+		in, out := &in.ImageProjects, &out.ImageProjects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleAccount.
+func (in *GoogleAccount) DeepCopy() *GoogleAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(GoogleAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureAccount) DeepCopyInto(out *AzureAccount) {
+	*out = *in
+	if in.AppKeySecret != nil {
+		in, out := &in.AppKeySecret, &out.AppKeySecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Packer != nil {
+		in, out := &in.Packer, &out.Packer
+		*out = new(AzurePacker)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureAccount.
+func (in *AzureAccount) DeepCopy() *AzureAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzurePacker) DeepCopyInto(out *AzurePacker) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzurePacker.
+func (in *AzurePacker) DeepCopy() *AzurePacker {
+	if in == nil {
+		return nil
+	}
+	out := new(AzurePacker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFoundryAccount) DeepCopyInto(out *CloudFoundryAccount) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFoundryAccount.
+func (in *CloudFoundryAccount) DeepCopy() *CloudFoundryAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFoundryAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ECSAccount) DeepCopyInto(out *ECSAccount) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ECSAccount.
+func (in *ECSAccount) DeepCopy() *ECSAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(ECSAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitHubArtifactAccount) DeepCopyInto(out *GitHubArtifactAccount) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	if in.TokenSecret != nil {
+		in, out := &in.TokenSecret, &out.TokenSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHubArtifactAccount.
+func (in *GitHubArtifactAccount) DeepCopy() *GitHubArtifactAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(GitHubArtifactAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitLabArtifactAccount) DeepCopyInto(out *GitLabArtifactAccount) {
+	*out = *in
+	if in.TokenSecret != nil {
+		in, out := &in.TokenSecret, &out.TokenSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitLabArtifactAccount.
+func (in *GitLabArtifactAccount) DeepCopy() *GitLabArtifactAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(GitLabArtifactAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3ArtifactAccount) DeepCopyInto(out *S3ArtifactAccount) {
+	*out = *in
+	if in.AwsSecretAccessKeySecret != nil {
+		in, out := &in.AwsSecretAccessKeySecret, &out.AwsSecretAccessKeySecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3ArtifactAccount.
+func (in *S3ArtifactAccount) DeepCopy() *S3ArtifactAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(S3ArtifactAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCSArtifactAccount) DeepCopyInto(out *GCSArtifactAccount) {
+	*out = *in
+	if in.JsonKeySecret != nil {
+		in, out := &in.JsonKeySecret, &out.JsonKeySecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCSArtifactAccount.
+func (in *GCSArtifactAccount) DeepCopy() *GCSArtifactAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(GCSArtifactAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmArtifactAccount) DeepCopyInto(out *HelmArtifactAccount) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmArtifactAccount.
+func (in *HelmArtifactAccount) DeepCopy() *HelmArtifactAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmArtifactAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPArtifactAccount) DeepCopyInto(out *HTTPArtifactAccount) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPArtifactAccount.
+func (in *HTTPArtifactAccount) DeepCopy() *HTTPArtifactAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPArtifactAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsAccount) DeepCopyInto(out *JenkinsAccount) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsAccount.
+func (in *JenkinsAccount) DeepCopy() *JenkinsAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitHubActionsAccount) DeepCopyInto(out *GitHubActionsAccount) {
+	*out = *in
+	if in.TokenSecret != nil {
+		in, out := &in.TokenSecret, &out.TokenSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHubActionsAccount.
+func (in *GitHubActionsAccount) DeepCopy() *GitHubActionsAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(GitHubActionsAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitLabCIAccount) DeepCopyInto(out *GitLabCIAccount) {
+	*out = *in
+	if in.PrivateTokenSecret != nil {
+		in, out := &in.PrivateTokenSecret, &out.PrivateTokenSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitLabCIAccount.
+func (in *GitLabCIAccount) DeepCopy() *GitLabCIAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(GitLabCIAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackAccount) DeepCopyInto(out *SlackAccount) {
+	*out = *in
+	if in.TokenSecret != nil {
+		in, out := &in.TokenSecret, &out.TokenSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackAccount.
+func (in *SlackAccount) DeepCopy() *SlackAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SMTPAccount) DeepCopyInto(out *SMTPAccount) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(SecretInNamespaceReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SMTPAccount.
+func (in *SMTPAccount) DeepCopy() *SMTPAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(SMTPAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MicrosoftTeamsAccount) DeepCopyInto(out *MicrosoftTeamsAccount) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MicrosoftTeamsAccount.
+func (in *MicrosoftTeamsAccount) DeepCopy() *MicrosoftTeamsAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(MicrosoftTeamsAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretInNamespaceReference) DeepCopyInto(out *SecretInNamespaceReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretInNamespaceReference.
+func (in *SecretInNamespaceReference) DeepCopy() *SecretInNamespaceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretInNamespaceReference)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -638,6 +1717,96 @@ func (in *SpinnakerAccountSpec) DeepCopyInto(out *SpinnakerAccountSpec) {
 		*out = new(KubernetesAuth)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AWS != nil {
+		in, out := &in.AWS, &out.AWS
+		*out = new(AWSAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DockerRegistry != nil {
+		in, out := &in.DockerRegistry, &out.DockerRegistry
+		*out = new(DockerRegistryAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Google != nil {
+		in, out := &in.Google, &out.Google
+		*out = new(GoogleAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(AzureAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CloudFoundry != nil {
+		in, out := &in.CloudFoundry, &out.CloudFoundry
+		*out = new(CloudFoundryAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ECS != nil {
+		in, out := &in.ECS, &out.ECS
+		*out = new(ECSAccount)
+		**out = **in
+	}
+	if in.GitHubArtifact != nil {
+		in, out := &in.GitHubArtifact, &out.GitHubArtifact
+		*out = new(GitHubArtifactAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GitLabArtifact != nil {
+		in, out := &in.GitLabArtifact, &out.GitLabArtifact
+		*out = new(GitLabArtifactAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3Artifact != nil {
+		in, out := &in.S3Artifact, &out.S3Artifact
+		*out = new(S3ArtifactAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GCSArtifact != nil {
+		in, out := &in.GCSArtifact, &out.GCSArtifact
+		*out = new(GCSArtifactAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HelmArtifact != nil {
+		in, out := &in.HelmArtifact, &out.HelmArtifact
+		*out = new(HelmArtifactAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPArtifact != nil {
+		in, out := &in.HTTPArtifact, &out.HTTPArtifact
+		*out = new(HTTPArtifactAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Jenkins != nil {
+		in, out := &in.Jenkins, &out.Jenkins
+		*out = new(JenkinsAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GitHubActions != nil {
+		in, out := &in.GitHubActions, &out.GitHubActions
+		*out = new(GitHubActionsAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GitLabCI != nil {
+		in, out := &in.GitLabCI, &out.GitLabCI
+		*out = new(GitLabCIAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(SlackAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SMTP != nil {
+		in, out := &in.SMTP, &out.SMTP
+		*out = new(SMTPAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MicrosoftTeams != nil {
+		in, out := &in.MicrosoftTeams, &out.MicrosoftTeams
+		*out = new(MicrosoftTeamsAccount)
+		**out = **in
+	}
 	in.Settings.DeepCopyInto(&out.Settings)
 	return
 }
@@ -660,6 +1829,16 @@ func (in *SpinnakerAccountStatus) DeepCopyInto(out *SpinnakerAccountStatus) {
 		*out = new(v1.Timestamp)
 		**out = **in
 	}
+	if in.CredentialsExpireAt != nil {
+		in, out := &in.CredentialsExpireAt, &out.CredentialsExpireAt
+		*out = new(v1.Timestamp)
+		**out = **in
+	}
+	if in.CredentialsRefreshAt != nil {
+		in, out := &in.CredentialsRefreshAt, &out.CredentialsRefreshAt
+		*out = new(v1.Timestamp)
+		**out = **in
+	}
 	return
 }
 
@@ -695,7 +1874,7 @@ func (in *SpinnakerServiceSpec) DeepCopyInto(out *SpinnakerServiceSpec) {
 	in.SpinnakerConfig.DeepCopyInto(&out.SpinnakerConfig)
 	in.Validation.DeepCopyInto(&out.Validation)
 	in.Expose.DeepCopyInto(&out.Expose)
-	out.Accounts = in.Accounts
+	in.Accounts.DeepCopyInto(&out.Accounts)
 	return
 }
 
@@ -724,6 +1903,18 @@ func (in *SpinnakerServiceStatus) DeepCopyInto(out *SpinnakerServiceStatus) {
 		*out = make([]SpinnakerDeploymentStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.ServiceUrls != nil {
+		in, out := &in.ServiceUrls, &out.ServiceUrls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Accounts != nil {
+		in, out := &in.Accounts, &out.Accounts
+		*out = make([]IncludedAccount, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -749,3 +1940,30 @@ func (e *ExposeConfig) GetAggregatedAnnotations(serviceName string) map[string]s
 	}
 	return annotations
 }
+
+// SetServiceUrl records the URL at which the given Spinnaker service (e.g. deck, echo) is exposed
+func (s *SpinnakerServiceStatus) SetServiceUrl(serviceName string, url string) {
+	if s.ServiceUrls == nil {
+		s.ServiceUrls = map[string]string{}
+	}
+	s.ServiceUrls[serviceName] = url
+}
+
+// RetainServiceUrls removes the URLs of services other than the given ones, no longer exposed
+func (s *SpinnakerServiceStatus) RetainServiceUrls(serviceNames ...string) {
+	for n := range s.ServiceUrls {
+		found := false
+		for _, k := range serviceNames {
+			if k == n {
+				found = true
+				break
+			}
+		}
+		if !found {
+			delete(s.ServiceUrls, n)
+		}
+	}
+	if len(s.ServiceUrls) == 0 {
+		s.ServiceUrls = nil
+	}
+}
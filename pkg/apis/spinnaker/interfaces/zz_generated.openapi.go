@@ -11,17 +11,22 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
+		"./pkg/apis/spinnaker/interfaces.AccountGenerator":             schema_pkg_apis_spinnaker_interfaces_AccountGenerator(ref),
 		"./pkg/apis/spinnaker/interfaces.AccountConfig":                schema_pkg_apis_spinnaker_interfaces_AccountConfig(ref),
 		"./pkg/apis/spinnaker/interfaces.ExposeConfig":                 schema_pkg_apis_spinnaker_interfaces_ExposeConfig(ref),
 		"./pkg/apis/spinnaker/interfaces.ExposeConfigService":          schema_pkg_apis_spinnaker_interfaces_ExposeConfigService(ref),
 		"./pkg/apis/spinnaker/interfaces.ExposeConfigServiceOverrides": schema_pkg_apis_spinnaker_interfaces_ExposeConfigServiceOverrides(ref),
 		"./pkg/apis/spinnaker/interfaces.HashStatus":                   schema_pkg_apis_spinnaker_interfaces_HashStatus(ref),
+		"./pkg/apis/spinnaker/interfaces.IncludedAccount":              schema_pkg_apis_spinnaker_interfaces_IncludedAccount(ref),
 		"./pkg/apis/spinnaker/interfaces.KubernetesAuth":               schema_pkg_apis_spinnaker_interfaces_KubernetesAuth(ref),
+		"./pkg/apis/spinnaker/interfaces.KubernetesProvision":          schema_pkg_apis_spinnaker_interfaces_KubernetesProvision(ref),
 		"./pkg/apis/spinnaker/interfaces.Kustomization":                schema_pkg_apis_spinnaker_interfaces_Kustomization(ref),
 		"./pkg/apis/spinnaker/interfaces.SecretInNamespaceReference":   schema_pkg_apis_spinnaker_interfaces_SecretInNamespaceReference(ref),
 		"./pkg/apis/spinnaker/interfaces.ServiceKustomization":         schema_pkg_apis_spinnaker_interfaces_ServiceKustomization(ref),
 		"./pkg/apis/spinnaker/interfaces.SpinnakerAccountSpec":         schema_pkg_apis_spinnaker_interfaces_SpinnakerAccountSpec(ref),
 		"./pkg/apis/spinnaker/interfaces.SpinnakerAccountStatus":       schema_pkg_apis_spinnaker_interfaces_SpinnakerAccountStatus(ref),
+		"./pkg/apis/spinnaker/interfaces.SpinnakerAccountTemplateSpec":   schema_pkg_apis_spinnaker_interfaces_SpinnakerAccountTemplateSpec(ref),
+		"./pkg/apis/spinnaker/interfaces.SpinnakerAccountTemplateStatus": schema_pkg_apis_spinnaker_interfaces_SpinnakerAccountTemplateStatus(ref),
 		"./pkg/apis/spinnaker/interfaces.SpinnakerDeploymentStatus":    schema_pkg_apis_spinnaker_interfaces_SpinnakerDeploymentStatus(ref),
 		"./pkg/apis/spinnaker/interfaces.SpinnakerServiceSpec":         schema_pkg_apis_spinnaker_interfaces_SpinnakerServiceSpec(ref),
 		"./pkg/apis/spinnaker/interfaces.SpinnakerServiceStatus":       schema_pkg_apis_spinnaker_interfaces_SpinnakerServiceStatus(ref),
@@ -50,9 +55,30 @@ func schema_pkg_apis_spinnaker_interfaces_AccountConfig(ref common.ReferenceCall
 							Format:      "",
 						},
 					},
+					"excludeInvalid": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Exclude accounts whose last validation failed",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"namespaceSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespaces other than the SpinnakerService's to read SpinnakerAccount from",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"selector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Labels of the SpinnakerAccount to include, defaults to all",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
 	}
 }
 
@@ -201,6 +227,38 @@ func schema_pkg_apis_spinnaker_interfaces_HashStatus(ref common.ReferenceCallbac
 	}
 }
 
+func schema_pkg_apis_spinnaker_interfaces_IncludedAccount(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "IncludedAccount references a SpinnakerAccount included in Spinnaker's settings",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"name", "namespace", "type"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_spinnaker_interfaces_KubernetesAuth(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -233,11 +291,51 @@ func schema_pkg_apis_spinnaker_interfaces_KubernetesAuth(ref common.ReferenceCal
 							Format:      "",
 						},
 					},
+					"provision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Provision a service account with access to namespaces of the cluster the operator runs in",
+							Ref:         ref("./pkg/apis/spinnaker/interfaces.KubernetesProvision"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/spinnaker/interfaces.SecretInNamespaceReference", "k8s.io/client-go/tools/clientcmd/api/v1.Config"},
+			"./pkg/apis/spinnaker/interfaces.KubernetesProvision", "./pkg/apis/spinnaker/interfaces.SecretInNamespaceReference", "k8s.io/client-go/tools/clientcmd/api/v1.Config"},
+	}
+}
+
+func schema_pkg_apis_spinnaker_interfaces_KubernetesProvision(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespaces Spinnaker can deploy to",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"role": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClusterRole bound to the service account in each namespace, defaults to edit",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"namespaces"},
+			},
+		},
 	}
 }
 
@@ -438,6 +536,32 @@ func schema_pkg_apis_spinnaker_interfaces_SpinnakerAccountStatus(ref common.Refe
 							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Timestamp"),
 						},
 					},
+					"hash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hash of the spec that was last validated",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"credentialsExpireAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CredentialsExpireAt is when credentials minted by the operator for the account expire",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Timestamp"),
+						},
+					},
+					"credentialsRefreshAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CredentialsRefreshAt is when the operator rotates credentials minted for the account",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Timestamp"),
+						},
+					},
+					"excludedReason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExcludedReason is set when a valid account is left out of Spinnaker's services, e.g. because an account it references is missing or another account has the same name",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"invalidReason", "lastValidatedAt"},
 			},
@@ -447,6 +571,118 @@ func schema_pkg_apis_spinnaker_interfaces_SpinnakerAccountStatus(ref common.Refe
 	}
 }
 
+func schema_pkg_apis_spinnaker_interfaces_SpinnakerAccountTemplateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SpinnakerAccountTemplateSpec defines SpinnakerAccount generated from a template",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"template": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Template of the spec of generated SpinnakerAccount. String values are Go templates of the parameters of each account, e.g. \"{{ .namespace }}\".",
+							Ref:         ref("./pkg/apis/spinnaker/interfaces.SpinnakerAccountSpec"),
+						},
+					},
+					"generator": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Generator of the parameters of each SpinnakerAccount",
+							Ref:         ref("./pkg/apis/spinnaker/interfaces.AccountGenerator"),
+						},
+					},
+				},
+				Required: []string{"template", "generator"},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/spinnaker/interfaces.AccountGenerator", "./pkg/apis/spinnaker/interfaces.SpinnakerAccountSpec"},
+	}
+}
+
+func schema_pkg_apis_spinnaker_interfaces_SpinnakerAccountTemplateStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SpinnakerAccountTemplateStatus defines the observed state of SpinnakerAccountTemplate",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"accounts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Accounts generated from the template",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"invalidReason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InvalidReason is set when some accounts couldn't be generated",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_spinnaker_interfaces_AccountGenerator(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AccountGenerator generates a set of parameters per SpinnakerAccount. Each set has a name: generated SpinnakerAccount are named <template name>-<name>.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"list": {
+						SchemaProps: spec.SchemaProps{
+							Description: "List of parameters, each with a name",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type: []string{"object"},
+										AdditionalProperties: &spec.SchemaOrBool{
+											Allows: true,
+											Schema: &spec.Schema{
+												SchemaProps: spec.SchemaProps{
+													Type:   []string{"string"},
+													Format: "",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					"namespaceSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NamespaceSelector generates parameters for each namespace matching the selector: name and namespace are the name of the namespace, labels its labels",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"configMap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigMap in the namespace of the template. Each key generates parameters named after the key, read from the YAML map value.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
 func schema_pkg_apis_spinnaker_interfaces_SpinnakerDeploymentStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -614,6 +850,21 @@ func schema_pkg_apis_spinnaker_interfaces_SpinnakerServiceStatus(ref common.Refe
 							Format:      "",
 						},
 					},
+					"serviceUrls": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Exposed URLs by Spinnaker service name",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
 					"accountCount": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Number of accounts",
@@ -621,11 +872,24 @@ func schema_pkg_apis_spinnaker_interfaces_SpinnakerServiceStatus(ref common.Refe
 							Format:      "int32",
 						},
 					},
+					"accounts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SpinnakerAccount included in Spinnaker's settings",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/spinnaker/interfaces.IncludedAccount"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/spinnaker/interfaces.HashStatus", "./pkg/apis/spinnaker/interfaces.SpinnakerDeploymentStatus"},
+			"./pkg/apis/spinnaker/interfaces.HashStatus", "./pkg/apis/spinnaker/interfaces.IncludedAccount", "./pkg/apis/spinnaker/interfaces.SpinnakerDeploymentStatus"},
 	}
 }
 
@@ -708,6 +972,19 @@ func schema_pkg_apis_spinnaker_interfaces_SpinnakerValidation(ref common.Referen
 							},
 						},
 					},
+					"artifacts": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/spinnaker/interfaces.ValidationSetting"),
+									},
+								},
+							},
+						},
+					},
 					"ci": {
 						SchemaProps: spec.SchemaProps{
 							Type: []string{"object"},
@@ -0,0 +1,42 @@
+package v1alpha2
+
+import (
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+)
+
+var _ interfaces.SpinnakerAccountTemplate = &SpinnakerAccountTemplate{}
+
+func (s *SpinnakerAccountTemplate) GetSpec() *interfaces.SpinnakerAccountTemplateSpec {
+	return &s.Spec
+}
+func (s *SpinnakerAccountTemplate) GetStatus() *interfaces.SpinnakerAccountTemplateStatus {
+	return &s.Status
+}
+func (s *SpinnakerAccountTemplate) DeepCopyInterface() interfaces.SpinnakerAccountTemplate {
+	return s.DeepCopy()
+}
+
+var _ interfaces.SpinnakerAccountTemplateList = &SpinnakerAccountTemplateList{}
+
+func (s *SpinnakerAccountTemplateList) GetItems() []interfaces.SpinnakerAccountTemplate {
+	if interfaces.IsNil(s.Items) {
+		return nil
+	} else {
+		var result []interfaces.SpinnakerAccountTemplate
+		for i := range s.Items {
+			result = append(result, &s.Items[i])
+		}
+		return result
+	}
+}
+
+func (s *SpinnakerAccountTemplateList) GetResourceVersion() string { return s.ResourceVersion }
+func (s *SpinnakerAccountTemplateList) SetResourceVersion(version string) {
+	s.ResourceVersion = version
+}
+func (s *SpinnakerAccountTemplateList) GetSelfLink() string            { return s.SelfLink }
+func (s *SpinnakerAccountTemplateList) SetSelfLink(selfLink string)    { s.SelfLink = selfLink }
+func (s *SpinnakerAccountTemplateList) GetContinue() string            { return s.Continue }
+func (s *SpinnakerAccountTemplateList) SetContinue(c string)           { s.Continue = c }
+func (s *SpinnakerAccountTemplateList) GetRemainingItemCount() *int64  { return s.RemainingItemCount }
+func (s *SpinnakerAccountTemplateList) SetRemainingItemCount(c *int64) { s.RemainingItemCount = c }
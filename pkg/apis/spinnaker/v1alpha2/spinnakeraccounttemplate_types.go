@@ -0,0 +1,35 @@
+package v1alpha2
+
+import (
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SpinnakerAccountTemplate is the Schema for the spinnakeraccounttemplates API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="type",type="string",JSONPath=".spec.template.type",description="Type"
+// +kubebuilder:printcolumn:name="reason",type="string",JSONPath=".status.invalidReason",description="Invalid Reason"
+// +kubebuilder:resource:path=spinnakeraccounttemplates,shortName=spinaccounttemplate
+type SpinnakerAccountTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   interfaces.SpinnakerAccountTemplateSpec   `json:"spec,omitempty"`
+	Status interfaces.SpinnakerAccountTemplateStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SpinnakerAccountTemplateList contains a list of SpinnakerAccountTemplate
+type SpinnakerAccountTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SpinnakerAccountTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SpinnakerAccountTemplate{}, &SpinnakerAccountTemplateList{})
+}
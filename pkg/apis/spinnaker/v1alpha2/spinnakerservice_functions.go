@@ -48,8 +48,8 @@ func (s *SpinnakerServiceList) GetItems() []interfaces.SpinnakerService {
 		return nil
 	} else {
 		var result []interfaces.SpinnakerService
-		for _, i := range s.Items {
-			result = append(result, &i)
+		for i := range s.Items {
+			result = append(result, &s.Items[i])
 		}
 		return result
 	}
@@ -74,6 +74,12 @@ func (f *TypesFactory) NewAccount() interfaces.SpinnakerAccount {
 func (f *TypesFactory) NewAccountList() interfaces.SpinnakerAccountList {
 	return &SpinnakerAccountList{}
 }
+func (f *TypesFactory) NewAccountTemplate() interfaces.SpinnakerAccountTemplate {
+	return &SpinnakerAccountTemplate{}
+}
+func (f *TypesFactory) NewAccountTemplateList() interfaces.SpinnakerAccountTemplateList {
+	return &SpinnakerAccountTemplateList{}
+}
 func (f *TypesFactory) GetGroupVersion() schema.GroupVersion {
 	return SchemeGroupVersion
 }
@@ -11,8 +11,9 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"./pkg/apis/spinnaker/v1alpha2.SpinnakerAccount": schema_pkg_apis_spinnaker_v1alpha2_SpinnakerAccount(ref),
-		"./pkg/apis/spinnaker/v1alpha2.SpinnakerService": schema_pkg_apis_spinnaker_v1alpha2_SpinnakerService(ref),
+		"./pkg/apis/spinnaker/v1alpha2.SpinnakerAccount":         schema_pkg_apis_spinnaker_v1alpha2_SpinnakerAccount(ref),
+		"./pkg/apis/spinnaker/v1alpha2.SpinnakerAccountTemplate": schema_pkg_apis_spinnaker_v1alpha2_SpinnakerAccountTemplate(ref),
+		"./pkg/apis/spinnaker/v1alpha2.SpinnakerService":         schema_pkg_apis_spinnaker_v1alpha2_SpinnakerService(ref),
 	}
 }
 
@@ -60,6 +61,50 @@ func schema_pkg_apis_spinnaker_v1alpha2_SpinnakerAccount(ref common.ReferenceCal
 	}
 }
 
+func schema_pkg_apis_spinnaker_v1alpha2_SpinnakerAccountTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SpinnakerAccountTemplate is the Schema for the spinnakeraccounttemplates API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces.SpinnakerAccountTemplateSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces.SpinnakerAccountTemplateStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces.SpinnakerAccountTemplateSpec", "github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces.SpinnakerAccountTemplateStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
 func schema_pkg_apis_spinnaker_v1alpha2_SpinnakerService(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
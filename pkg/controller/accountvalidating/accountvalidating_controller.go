@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"reflect"
 
 	"github.com/armory/spinnaker-operator/pkg/accounts"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/armory/spinnaker-operator/pkg/controller/webhook"
 	"github.com/armory/spinnaker-operator/pkg/secrets"
+	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
@@ -57,28 +60,79 @@ func (v *accountValidatingController) Handle(ctx context.Context, req admission.
 		if err := v.decoder.Decode(req, acc); err != nil {
 			return admission.Errored(http.StatusBadRequest, err)
 		}
+		if acc.GetDeletionTimestamp() != nil {
+			// Let the operator remove its finalizer
+			return admission.ValidationResponse(true, "")
+		}
+		if req.Operation == admissionv1.Update && len(req.OldObject.Raw) > 0 {
+			old := TypesFactory.NewAccount()
+			if err := v.decoder.DecodeRaw(req.OldObject, old); err == nil && reflect.DeepEqual(old.GetSpec(), acc.GetSpec()) {
+				// Metadata changes (e.g. finalizers added by the operator) don't need the account to be validated again
+				return admission.ValidationResponse(true, "")
+			}
+		}
+
+		spinsvc, allowed, err := v.isNamespaceAllowed(ctx, acc)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if !allowed {
+			return admission.Denied(fmt.Sprintf("namespace %s is not selected by any SpinnakerService (spec.accounts.namespaceSelector)", acc.GetNamespace()))
+		}
 
 		accType, err := accounts.GetType(acc.GetSpec().Type)
 		if err != nil {
 			return admission.Errored(http.StatusBadRequest, err)
 		}
 
+		if p, ok := accType.(account.AccountTypeWithProvisioning); ok && p.NeedsProvisioning(acc) {
+			if err := accounts.CheckProvisioning(ctx, v.client, spinsvc, acc); err != nil {
+				return admission.Denied(err.Error())
+			}
+			// Resources are provisioned after admission, the account is validated by the operator
+			return admission.ValidationResponse(true, "")
+		}
+
 		spinAccount, err := accType.FromCRD(acc)
 		if err != nil {
 			return admission.Errored(http.StatusBadRequest, err)
 		}
 
 		av := spinAccount.NewValidator()
-		ctx = secrets.NewContext(ctx, v.restConfig, acc.GetNamespace())
+		// Secrets of the SpinnakerService config are read from its namespace, secrets referenced by the account from
+		// the account's namespace
+		ctx = secrets.NewContext(ctx, v.restConfig, spinsvc.GetNamespace())
 		defer secrets.Cleanup(ctx)
+		ctx = account.WithNamespace(ctx, acc.GetNamespace())
+		defer account.Cleanup(ctx)
 
-		if err := av.Validate(nil, v.client, ctx, log); err != nil {
+		if err := av.Validate(spinsvc, v.client, ctx, log); err != nil {
 			return admission.Errored(http.StatusUnprocessableEntity, err)
 		}
 	}
 	return admission.ValidationResponse(true, "")
 }
 
+// isNamespaceAllowed checks that the account's namespace is selected by a SpinnakerService (spec.accounts.namespaceSelector)
+// and returns that SpinnakerService. Accounts are denied if no SpinnakerService selects their namespace, including when
+// there is no SpinnakerService yet.
+func (v *accountValidatingController) isNamespaceAllowed(ctx context.Context, acc interfaces.SpinnakerAccount) (interfaces.SpinnakerService, bool, error) {
+	l := TypesFactory.NewServiceList()
+	if err := v.client.List(ctx, l); err != nil {
+		return nil, false, err
+	}
+	for _, s := range l.GetItems() {
+		ok, err := accounts.IsNamespaceSelected(ctx, v.client, s, acc.GetNamespace())
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			return s, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
 // InjectClient injects the client.
 func (v *accountValidatingController) InjectClient(c client.Client) error {
 	v.client = c
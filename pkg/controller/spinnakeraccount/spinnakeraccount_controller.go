@@ -4,20 +4,32 @@ import (
 	"context"
 	"github.com/armory/spinnaker-operator/pkg/accounts"
 	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/accounts/configserver"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/transformer"
 	"github.com/armory/spinnaker-operator/pkg/secrets"
 	"github.com/armory/spinnaker-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"time"
 )
 
 var log = logf.Log.WithName("spinnakerservice")
@@ -33,9 +45,10 @@ func Add(mgr manager.Manager) error {
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 	return &ReconcileSpinnakerAccount{
-		client:     mgr.GetClient(),
-		restConfig: mgr.GetConfig(),
-		scheme:     mgr.GetScheme(),
+		client:      mgr.GetClient(),
+		restConfig:  mgr.GetConfig(),
+		scheme:      mgr.GetScheme(),
+		evtRecorder: mgr.GetEventRecorderFor("spinnakeraccount-controller"),
 	}
 }
 
@@ -48,7 +61,8 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to primary resource SpinnakerService
-	err = c.Watch(&source.Kind{Type: TypesFactory.NewAccount()}, &handler.EnqueueRequestForObject{})
+	// Status updates don't change the generation and don't trigger a reconcile
+	err = c.Watch(&source.Kind{Type: TypesFactory.NewAccount()}, &handler.EnqueueRequestForObject{}, predicate.Or(predicate.GenerationChangedPredicate{}, deletingPredicate))
 	if err != nil {
 		// Ignore no kind match
 		if _, ok := err.(*meta.NoKindMatchError); ok {
@@ -57,7 +71,39 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		}
 		return err
 	}
-	return nil
+
+	// Watch for SpinnakerService changes that may include or exclude their accounts
+	return c.Watch(&source.Kind{Type: TypesFactory.NewService()}, handler.EnqueueRequestsFromMapFunc(selectedAccounts(mgr.GetClient())), predicate.GenerationChangedPredicate{})
+}
+
+// selectedAccounts returns the accounts selected by the SpinnakerService
+func selectedAccounts(c client.Client) handler.MapFunc {
+	return func(o client.Object) []reconcile.Request {
+		spinsvc, ok := o.(interfaces.SpinnakerService)
+		if !ok || !spinsvc.GetAccountConfig().Enabled {
+			return nil
+		}
+		accs, err := accounts.SelectedAccounts(context.TODO(), c, spinsvc)
+		if err != nil {
+			log.Error(err, "unable to list accounts of SpinnakerService", "Namespace", spinsvc.GetNamespace(), "Name", spinsvc.GetName())
+			return nil
+		}
+		reqs := make([]reconcile.Request, 0, len(accs))
+		for _, a := range accs {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: a.GetNamespace(), Name: a.GetName()}})
+		}
+		return reqs
+	}
+}
+
+// deletingPredicate lets through updates of accounts being deleted so provisioned resources can be cleaned up
+var deletingPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectNew.GetDeletionTimestamp() != nil
+	},
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
 }
 
 // blank assignment to verify that ReconcileSpinnakerService implements reconcile.Reconciler
@@ -67,9 +113,10 @@ var _ reconcile.Reconciler = &ReconcileSpinnakerAccount{}
 type ReconcileSpinnakerAccount struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client     client.Client
-	restConfig *rest.Config
-	scheme     *runtime.Scheme
+	client      client.Client
+	restConfig  *rest.Config
+	scheme      *runtime.Scheme
+	evtRecorder record.EventRecorder
 }
 
 // Reconcile reads that state of the cluster for a SpinnakerService object and makes changes based on the state read
@@ -83,6 +130,7 @@ func (r *ReconcileSpinnakerAccount) Reconcile(ctx context.Context, request recon
 
 	// Fetch the SpinnakerService instance
 	instance := TypesFactory.NewAccount()
+	ctx = secrets.NewContext(ctx, r.restConfig, request.Namespace)
 	defer secrets.Cleanup(ctx)
 
 	err := r.client.Get(ctx, request.NamespacedName, instance)
@@ -97,6 +145,16 @@ func (r *ReconcileSpinnakerAccount) Reconcile(ctx context.Context, request recon
 		return reconcile.Result{}, err
 	}
 
+	if instance.GetDeletionTimestamp() != nil {
+		return reconcile.Result{}, r.finalize(ctx, instance)
+	}
+	if !controllerutil.ContainsFinalizer(instance, account.AccountFinalizer) {
+		controllerutil.AddFinalizer(instance, account.AccountFinalizer)
+		if err = r.client.Update(ctx, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
 	// Check if we need to redeploy
 	reqLogger.Info("Checking Spinnaker accounts")
 
@@ -104,42 +162,302 @@ func (r *ReconcileSpinnakerAccount) Reconcile(ctx context.Context, request recon
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	spinsvcs, err := accounts.FindSpinnakerServices(ctx, r.client, instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	var spinsvc interfaces.SpinnakerService
+	if len(spinsvcs) > 0 {
+		spinsvc = spinsvcs[0]
+		// Secrets of the SpinnakerService config are read from its namespace, secrets referenced by the account from
+		// the account's namespace
+		if spinsvc.GetNamespace() != request.Namespace {
+			ctx = secrets.NewContext(ctx, r.restConfig, spinsvc.GetNamespace())
+			defer secrets.Cleanup(ctx)
+		}
+	} else {
+		log.Info("no SpinnakerService to deploy account to")
+	}
+	rotated := false
+	if p, ok := aType.(account.AccountTypeWithProvisioning); ok {
+		refreshAt := instance.GetStatus().CredentialsRefreshAt
+		if err = r.provision(ctx, spinsvc, instance, p); err != nil {
+			return reconcile.Result{}, err
+		}
+		if rotated = !reflect.DeepEqual(refreshAt, instance.GetStatus().CredentialsRefreshAt); rotated {
+			if err = r.client.Status().Update(ctx, instance); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+	}
 	cpInstance := instance.DeepCopyInterface()
-	err = r.deploy(ctx, cpInstance, aType)
-	return reconcile.Result{}, err
+	hash, err := accounts.SpecHash(cpInstance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	status := cpInstance.GetStatus()
+	changed := status.Hash != hash
+	wasValid := status.InvalidReason == ""
+
+	settings := getValidationSettings(spinsvc, cpInstance, aType)
+	if !settings.Enabled {
+		log.Info("validation disabled for account type", "metadata.name", cpInstance.GetName(), "type", aType.GetType())
+		// The hash records the spec deployed even if it's not validated, the result of a prior validation no longer
+		// applies. Accounts that can't be parsed are still invalid.
+		if changed {
+			status.Hash = hash
+			status.InvalidReason = ""
+			if _, err := aType.FromCRD(cpInstance); err != nil {
+				status.InvalidReason = err.Error()
+			}
+			if err = r.client.Status().Update(ctx, cpInstance); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+	} else if changed || needsValidation(settings, status) {
+		if err = r.validate(ctx, spinsvc, cpInstance, aType, hash); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Only redeploy if the account changed or its validity changed
+	if changed || wasValid != (status.InvalidReason == "") {
+		for _, s := range spinsvcs {
+			if err = r.deploy(ctx, s.DeepCopyInterface(), aType); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+	} else if rotated {
+		for _, s := range spinsvcs {
+			if err = r.rotate(ctx, s.DeepCopyInterface(), cpInstance, aType); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+	}
+	if err = r.recordExclusions(ctx, spinsvcs); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter(settings, status, time.Now())}, nil
 }
 
-func (r *ReconcileSpinnakerAccount) deploy(ctx context.Context, account interfaces.SpinnakerAccount, accountType account.SpinnakerAccountType) error {
-	spinsvc, err := util.FindSpinnakerService(r.client, account.GetNamespace(), TypesFactory)
-	if err != nil {
+// requeueAfter returns when the account needs to be revalidated or its credentials rotated, whichever comes first.
+// Zero means the account doesn't need to be reconciled until it changes.
+func requeueAfter(settings interfaces.ValidationSetting, status *interfaces.SpinnakerAccountStatus, now time.Time) time.Duration {
+	var d time.Duration
+	if settings.Enabled {
+		d = time.Duration(settings.FrequencySeconds.IntValue()) * time.Second
+	}
+	if status.CredentialsRefreshAt != nil {
+		r := time.Unix(status.CredentialsRefreshAt.Seconds, 0).Sub(now)
+		if r <= 0 {
+			r = time.Second
+		}
+		if d == 0 || r < d {
+			d = r
+		}
+	}
+	return d
+}
+
+// provision creates or updates the resources of accounts needing them and deletes them when the account no longer
+// needs them or isn't allowed to provision them for the SpinnakerService. Role bindings may live in other namespaces,
+// so a finalizer is used instead of owner references.
+func (r *ReconcileSpinnakerAccount) provision(ctx context.Context, spinsvc interfaces.SpinnakerService, acc interfaces.SpinnakerAccount, accountType account.AccountTypeWithProvisioning) error {
+	needed := accountType.NeedsProvisioning(acc)
+	if needed {
+		if err := accounts.CheckProvisioning(ctx, r.client, spinsvc, acc); err != nil {
+			r.evtRecorder.Eventf(acc, corev1.EventTypeWarning, "ProvisioningDenied", "Unable to provision account: %s", err.Error())
+			needed = false
+		}
+	}
+	if !needed {
+		if !controllerutil.ContainsFinalizer(acc, account.ProvisionFinalizer) {
+			return nil
+		}
+		log.Info("deleting provisioned resources", "metadata.name", acc.GetName())
+		if err := accountType.Deprovision(ctx, r.client, acc); err != nil {
+			return err
+		}
+		controllerutil.RemoveFinalizer(acc, account.ProvisionFinalizer)
+		if err := r.client.Update(ctx, acc); err != nil {
+			return err
+		}
+		status := acc.GetStatus()
+		status.CredentialsExpireAt, status.CredentialsRefreshAt = nil, nil
+		return nil
+	}
+	if !controllerutil.ContainsFinalizer(acc, account.ProvisionFinalizer) {
+		controllerutil.AddFinalizer(acc, account.ProvisionFinalizer)
+		if err := r.client.Update(ctx, acc); err != nil {
+			return err
+		}
+	}
+	if err := accountType.Provision(ctx, r.client, acc); err != nil {
+		r.evtRecorder.Eventf(acc, corev1.EventTypeWarning, "ProvisioningFailed", "Unable to provision account: %s", err.Error())
 		return err
 	}
+	return nil
+}
 
-	// No service to deploy to
-	if spinsvc == nil {
-		log.Info("no SpinnakerService to deploy account to")
+// finalize removes a deleted account from the services of the SpinnakerServices using it and deletes its provisioned
+// resources, then releases the account's finalizers. SpinnakerServices not accepting dynamic accounts drop the account
+// on their next deployment.
+func (r *ReconcileSpinnakerAccount) finalize(ctx context.Context, acc interfaces.SpinnakerAccount) error {
+	if !controllerutil.ContainsFinalizer(acc, account.AccountFinalizer) && !controllerutil.ContainsFinalizer(acc, account.ProvisionFinalizer) {
+		return nil
+	}
+	// Finalizers of accounts of unknown types are released right away
+	if aType, err := accounts.GetType(acc.GetSpec().Type); err == nil {
+		p, ok := aType.(account.AccountTypeWithProvisioning)
+		if ok && controllerutil.ContainsFinalizer(acc, account.ProvisionFinalizer) {
+			log.Info("deleting provisioned resources", "metadata.name", acc.GetName())
+			if err = p.Deprovision(ctx, r.client, acc); err != nil {
+				return err
+			}
+		}
+		if controllerutil.ContainsFinalizer(acc, account.AccountFinalizer) {
+			spinsvcs, err := accounts.FindSpinnakerServices(ctx, r.client, acc)
+			if err != nil {
+				return err
+			}
+			for _, s := range spinsvcs {
+				if err = r.deploy(ctx, s.DeepCopyInterface(), aType); err != nil {
+					return err
+				}
+				r.evtRecorder.Eventf(s, corev1.EventTypeNormal, "AccountDeleted", "%s account %s of namespace %s deleted",
+					acc.GetSpec().Type, acc.GetName(), acc.GetNamespace())
+			}
+			// Accounts left out because of the deleted account may be included again
+			if err = r.recordExclusions(ctx, spinsvcs); err != nil {
+				return err
+			}
+		}
+	}
+	controllerutil.RemoveFinalizer(acc, account.AccountFinalizer)
+	controllerutil.RemoveFinalizer(acc, account.ProvisionFinalizer)
+	return r.client.Update(ctx, acc)
+}
+
+// rotate updates rotated credentials of the account in the SpinnakerService without redeploying it. Services of
+// SpinnakerServices in the account's namespace mount the rotated files, updated in place. Files read from another
+// namespace are copied to the secrets of the services, so only the copies are updated. Dynamic accounts are written
+// again to the services.
+func (r *ReconcileSpinnakerAccount) rotate(ctx context.Context, spinsvc interfaces.SpinnakerService, acc interfaces.SpinnakerAccount, accountType account.SpinnakerAccountType) error {
+	if cfg := spinsvc.GetAccountConfig(); cfg.Enabled && cfg.Dynamic {
+		return r.deploy(ctx, spinsvc, accountType)
+	}
+	p, ok := accountType.(account.AccountTypeWithProvisioning)
+	if !ok || spinsvc.GetNamespace() == acc.GetNamespace() {
 		return nil
 	}
+	return transformer.UpdateRotatedFiles(ctx, r.client, spinsvc, p.RotatedFiles(acc))
+}
 
+// recordExclusions records why accounts of the SpinnakerServices are left out of their services. Exclusions depend
+// on other accounts (e.g. duplicate names), so all the accounts of the SpinnakerServices are updated.
+func (r *ReconcileSpinnakerAccount) recordExclusions(ctx context.Context, spinsvcs []interfaces.SpinnakerService) error {
+	for _, s := range spinsvcs {
+		if err := accounts.RecordExclusions(ctx, r.client, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defaultRevalidationSeconds is how often accounts are revalidated in the background. Validation calls external
+// services (e.g. an access review per kind and namespace of Kubernetes accounts), so frequencies of the SpinnakerService,
+// meant for its admission, don't apply.
+var defaultRevalidationSeconds = intstr.FromInt(3600)
+
+// getValidationSettings returns the validation settings of the account type in the SpinnakerService
+// (e.g. spec.validation.notifications). Accounts are revalidated every hour unless spec.validation.frequencySeconds of
+// the account is set.
+func getValidationSettings(spinsvc interfaces.SpinnakerService, acc interfaces.SpinnakerAccount, accountType account.SpinnakerAccountType) interfaces.ValidationSetting {
+	settings := interfaces.ValidationSetting{Enabled: true}
+	if spinsvc != nil {
+		settings = *accountType.GetValidationSettings(spinsvc)
+	}
+	settings.FrequencySeconds = defaultRevalidationSeconds
+	if f := acc.GetSpec().Validation.FrequencySeconds; f.IntValue() > 0 {
+		settings.FrequencySeconds = f
+	}
+	return settings
+}
+
+// needsValidation returns true if the account was never validated or was last validated more than
+// settings.FrequencySeconds ago
+func needsValidation(settings interfaces.ValidationSetting, status *interfaces.SpinnakerAccountStatus) bool {
+	if status.LastValidatedAt == nil {
+		return true
+	}
+	return settings.NeedsValidation(metav1.Unix(status.LastValidatedAt.Seconds, int64(status.LastValidatedAt.Nanos)))
+}
+
+// validate runs the account's validator and records the result and the hash of the validated spec in the account's status.
+// spinsvc is nil if no SpinnakerService accepts the account yet.
+func (r *ReconcileSpinnakerAccount) validate(ctx context.Context, spinsvc interfaces.SpinnakerService, acc interfaces.SpinnakerAccount, accountType account.SpinnakerAccountType, hash string) error {
+	status := acc.GetStatus()
+	wasValid := status.InvalidReason == "" && status.Hash == hash
+	status.InvalidReason = ""
+	a, err := accountType.FromCRD(acc)
+	if err == nil {
+		vctx := account.WithNamespace(ctx, acc.GetNamespace())
+		defer account.Cleanup(vctx)
+		err = a.NewValidator().Validate(spinsvc, r.client, vctx, log.WithValues("Accounts.Name", acc.GetName()))
+	}
+	if err != nil {
+		log.Info("account is invalid", "metadata.name", acc.GetName(), "reason", err.Error())
+		status.InvalidReason = err.Error()
+		r.evtRecorder.Eventf(acc, corev1.EventTypeWarning, "ValidationFailed", "Account is invalid: %s", err.Error())
+	} else if !wasValid {
+		r.evtRecorder.Eventf(acc, corev1.EventTypeNormal, "Validated", "Account is valid")
+	}
+	status.LastValidatedAt = &metav1.Timestamp{Seconds: time.Now().Unix()}
+	status.Hash = hash
+	return r.client.Status().Update(ctx, acc)
+}
+
+// deploy updates the accounts of the SpinnakerService's services using the account type and the accounts listed in its status.
+// Services served by the accounts config server reload their accounts on their own.
+// Accounts of SpinnakerService not accepting dynamic accounts are only updated when the SpinnakerService is deployed.
+func (r *ReconcileSpinnakerAccount) deploy(ctx context.Context, spinsvc interfaces.SpinnakerService, accountType account.SpinnakerAccountType) error {
 	// Check we can inject dynamic accounts in the SpinnakerService
 	if !spinsvc.GetAccountConfig().Enabled || !spinsvc.GetAccountConfig().Dynamic {
 		log.Info("SpinnakerService not accepting dynamic accounts", "metadata.name", spinsvc.GetName())
+		return nil
 	}
+	_, err := configserver.URL(spinsvc)
+	served := err == nil
+
+	// Account settings are rendered in the SpinnakerService namespace
+	ctx = secrets.NewContext(ctx, r.restConfig, spinsvc.GetNamespace())
+	defer secrets.Cleanup(ctx)
 
-	// Get all Spinnaker accounts
-	allAccounts, err := accounts.AllValidCRDAccounts(ctx, r.client, account.GetNamespace())
+	// Get all Spinnaker accounts. Accounts included are recorded in the SpinnakerService status by its controller.
+	allAccounts, err := accounts.IncludedCRDAccounts(ctx, r.client, spinsvc)
 	if err != nil {
 		return err
 	}
 
 	// Go through all affected services and update dynamic config secret
 	for _, svc := range accountType.GetServices() {
+		if served && configserver.IsServed(svc) {
+			continue
+		}
 		ss, err := accounts.PrepareSettings(ctx, svc, allAccounts)
 		if err != nil {
 			return err
 		}
+		if ss, err = accounts.DecodeSecrets(ctx, ss); err != nil {
+			return err
+		}
 		dep, err := util.FindDeployment(r.client, spinsvc, svc)
 		if err != nil {
+			// Service not deployed (e.g. Igor without CI enabled), accounts will be added when it's deployed
+			if errors.IsNotFound(err) {
+				log.Info("service not deployed, skipping accounts update", "service", svc)
+				continue
+			}
 			return err
 		}
 		sec, err := util.FindSecretInDeployment(r.client, dep, svc, "/opt/spinnaker/config")
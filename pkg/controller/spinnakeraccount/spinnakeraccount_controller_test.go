@@ -0,0 +1,218 @@
+package spinnakeraccount
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/accounts/kubernetes"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func init() {
+	TypesFactory = test.TypesFactory
+	accounts.TypesFactory = test.TypesFactory
+}
+
+func TestGetValidationSettings(t *testing.T) {
+	spinsvc := &v1alpha2.SpinnakerService{}
+	spinsvc.Spec.Validation.Providers = map[string]interfaces.ValidationSetting{
+		"kubernetes": {Enabled: true, FrequencySeconds: intstr.FromInt(120)},
+	}
+	tests := []struct {
+		name      string
+		spinsvc   interfaces.SpinnakerService
+		frequency int
+		expected  int
+	}{
+		{
+			name:     "default frequency without SpinnakerService",
+			expected: defaultRevalidationSeconds.IntValue(),
+		},
+		{
+			name:     "frequency of the account type only applies to admission",
+			spinsvc:  spinsvc,
+			expected: defaultRevalidationSeconds.IntValue(),
+		},
+		{
+			name:      "frequency of the account",
+			spinsvc:   spinsvc,
+			frequency: 600,
+			expected:  600,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acc := &v1alpha2.SpinnakerAccount{}
+			acc.Spec.Validation.FrequencySeconds = intstr.FromInt(tt.frequency)
+			s := getValidationSettings(tt.spinsvc, acc, &kubernetes.AccountType{})
+			assert.True(t, s.Enabled)
+			assert.Equal(t, tt.expected, s.FrequencySeconds.IntValue())
+		})
+	}
+}
+
+func TestNeedsValidation(t *testing.T) {
+	s := interfaces.ValidationSetting{Enabled: true, FrequencySeconds: intstr.FromInt(60)}
+	assert.True(t, needsValidation(s, &interfaces.SpinnakerAccountStatus{}))
+	assert.False(t, needsValidation(s, &interfaces.SpinnakerAccountStatus{LastValidatedAt: &metav1.Timestamp{Seconds: time.Now().Unix()}}))
+	assert.True(t, needsValidation(s, &interfaces.SpinnakerAccountStatus{LastValidatedAt: &metav1.Timestamp{Seconds: time.Now().Add(-2 * time.Minute).Unix()}}))
+}
+
+func TestRequeueAfter(t *testing.T) {
+	now := time.Now()
+	s := interfaces.ValidationSetting{Enabled: true, FrequencySeconds: intstr.FromInt(60)}
+	assert.Equal(t, time.Minute, requeueAfter(s, &interfaces.SpinnakerAccountStatus{}, now))
+	assert.Equal(t, time.Duration(0), requeueAfter(interfaces.ValidationSetting{}, &interfaces.SpinnakerAccountStatus{}, now))
+
+	// Credentials refreshed before the next validation
+	status := &interfaces.SpinnakerAccountStatus{CredentialsRefreshAt: &metav1.Timestamp{Seconds: now.Unix() + 30}}
+	assert.Equal(t, 30*time.Second, requeueAfter(s, status, time.Unix(now.Unix(), 0)))
+	assert.Equal(t, 30*time.Second, requeueAfter(interfaces.ValidationSetting{}, status, time.Unix(now.Unix(), 0)))
+	status.CredentialsRefreshAt.Seconds = now.Unix() - 30
+	assert.Equal(t, time.Second, requeueAfter(s, status, now))
+}
+
+func TestReconcileValidationDisabled(t *testing.T) {
+	acc := &v1alpha2.SpinnakerAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube", Namespace: "spinnaker", Finalizers: []string{account.AccountFinalizer}},
+		Spec: interfaces.SpinnakerAccountSpec{
+			Type:       interfaces.KubernetesAccountType,
+			Enabled:    true,
+			Kubernetes: &interfaces.KubernetesAuth{KubeconfigFile: "/tmp/kubeconfig.yml"},
+		},
+		Status: interfaces.SpinnakerAccountStatus{InvalidReason: "invalid kubeconfig", Hash: "prior"},
+	}
+	spinsvc := &v1alpha2.SpinnakerService{ObjectMeta: metav1.ObjectMeta{Name: "spinnaker", Namespace: "spinnaker"}}
+	spinsvc.Spec.Accounts.Enabled = true
+	spinsvc.Spec.Validation.Providers = map[string]interfaces.ValidationSetting{"kubernetes": {Enabled: false}}
+
+	s := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{corev1.AddToScheme, v1alpha2.SchemeBuilder.AddToScheme} {
+		if err := add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := &ReconcileSpinnakerAccount{
+		client:      fake.NewClientBuilder().WithScheme(s).WithObjects(acc, spinsvc).Build(),
+		scheme:      s,
+		evtRecorder: record.NewFakeRecorder(10),
+	}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(acc)}
+	if _, err := r.Reconcile(context.TODO(), req); !assert.Nil(t, err) {
+		return
+	}
+
+	// The spec hash is recorded without validating the account, so the next reconcile doesn't redeploy it
+	updated := &v1alpha2.SpinnakerAccount{}
+	if assert.Nil(t, r.client.Get(context.TODO(), req.NamespacedName, updated)) {
+		h, err := accounts.SpecHash(updated)
+		assert.Nil(t, err)
+		assert.Equal(t, h, updated.Status.Hash)
+		assert.Equal(t, "", updated.Status.InvalidReason)
+		assert.Nil(t, updated.Status.LastValidatedAt)
+	}
+}
+
+func TestReconcileRecordsExclusions(t *testing.T) {
+	awsAccount := func(ns string) *v1alpha2.SpinnakerAccount {
+		return &v1alpha2.SpinnakerAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws1", Namespace: ns, Finalizers: []string{account.AccountFinalizer}},
+			Spec: interfaces.SpinnakerAccountSpec{
+				Type:    interfaces.AWSAccountType,
+				Enabled: true,
+				AWS:     &interfaces.AWSAccount{AccountId: "123456789012"},
+			},
+		}
+	}
+	acc, other := awsAccount("spinnaker"), awsAccount("team-a")
+	spinsvc := &v1alpha2.SpinnakerService{ObjectMeta: metav1.ObjectMeta{Name: "spinnaker", Namespace: "spinnaker"}}
+	spinsvc.Spec.Accounts = interfaces.AccountConfig{Enabled: true, NamespaceSelector: &metav1.LabelSelector{}}
+	spinsvc.Spec.Validation.Providers = map[string]interfaces.ValidationSetting{"aws": {Enabled: false}}
+
+	s := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{corev1.AddToScheme, v1alpha2.SchemeBuilder.AddToScheme} {
+		if err := add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := &ReconcileSpinnakerAccount{
+		client: fake.NewClientBuilder().WithScheme(s).WithObjects(acc, other, spinsvc,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}).Build(),
+		scheme:      s,
+		evtRecorder: record.NewFakeRecorder(10),
+	}
+	if _, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(acc)}); !assert.Nil(t, err) {
+		return
+	}
+
+	// Exclusions of other accounts of the SpinnakerService are recorded too
+	updated := &v1alpha2.SpinnakerAccount{}
+	if assert.Nil(t, r.client.Get(context.TODO(), client.ObjectKeyFromObject(other), updated)) {
+		assert.Equal(t, "AWS account \"aws1\" is already defined in namespace spinnaker", updated.Status.ExcludedReason)
+	}
+	// The SpinnakerService status is left to its controller
+	svc := &v1alpha2.SpinnakerService{}
+	if assert.Nil(t, r.client.Get(context.TODO(), client.ObjectKeyFromObject(spinsvc), svc)) {
+		assert.Empty(t, svc.Status.Accounts)
+	}
+}
+
+func TestFinalize(t *testing.T) {
+	now := metav1.Now()
+	acc := &v1alpha2.SpinnakerAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "kube",
+			Namespace:         "spinnaker",
+			UID:               "1234",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{account.AccountFinalizer, account.ProvisionFinalizer},
+		},
+		Spec: interfaces.SpinnakerAccountSpec{
+			Type: interfaces.KubernetesAccountType,
+			Kubernetes: &interfaces.KubernetesAuth{
+				Provision: &interfaces.KubernetesProvision{Namespaces: []string{"ns1"}},
+			},
+		},
+	}
+	spinsvc := &v1alpha2.SpinnakerService{ObjectMeta: metav1.ObjectMeta{Name: "spinnaker", Namespace: "spinnaker"}}
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "spinnaker-account-kube", Namespace: "spinnaker"}}
+
+	s := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{corev1.AddToScheme, rbacv1.AddToScheme, v1alpha2.SchemeBuilder.AddToScheme} {
+		if err := add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileSpinnakerAccount{
+		client:      fake.NewClientBuilder().WithScheme(s).WithObjects(acc, spinsvc, sa).Build(),
+		scheme:      s,
+		evtRecorder: recorder,
+	}
+	if !assert.Nil(t, r.finalize(context.TODO(), acc)) {
+		return
+	}
+
+	// The account is gone once its finalizers are released
+	err := r.client.Get(context.TODO(), client.ObjectKeyFromObject(acc), &v1alpha2.SpinnakerAccount{})
+	assert.True(t, errors.IsNotFound(err))
+	err = r.client.Get(context.TODO(), client.ObjectKeyFromObject(sa), sa)
+	assert.True(t, errors.IsNotFound(err))
+	assert.Equal(t, "Normal AccountDeleted Kubernetes account kube of namespace spinnaker deleted", <-recorder.Events)
+}
@@ -0,0 +1,244 @@
+package spinnakeraccounttemplate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("spinnakeraccounttemplate")
+
+var TypesFactory interfaces.TypesFactory
+
+// Add creates a new SpinnakerAccountTemplate Controller and adds it to the Manager. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) *ReconcileSpinnakerAccountTemplate {
+	return &ReconcileSpinnakerAccountTemplate{
+		client:      mgr.GetClient(),
+		evtRecorder: mgr.GetEventRecorderFor("spinnakeraccounttemplate-controller"),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r *ReconcileSpinnakerAccountTemplate) error {
+	c, err := controller.New("spinnakeraccounttemplate-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource SpinnakerAccountTemplate
+	err = c.Watch(&source.Kind{Type: TypesFactory.NewAccountTemplate()}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		// Ignore no kind match
+		if _, ok := err.(*meta.NoKindMatchError); ok {
+			log.Info("operator starting without support for SpinnakerAccountTemplate")
+			return nil
+		}
+		return err
+	}
+
+	// Generated accounts changed or deleted by someone else are put back
+	err = c.Watch(&source.Kind{Type: TypesFactory.NewAccount()}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    TypesFactory.NewAccountTemplate(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Namespaces and config maps generators read from. Namespaces can only be watched when the operator
+	// watches the whole cluster.
+	if ns, _ := k8sutil.GetWatchNamespace(); ns == "" {
+		err = c.Watch(&source.Kind{Type: &corev1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(r.templatesSelectingNamespaces))
+		if err != nil {
+			return err
+		}
+	}
+	return c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.templatesReadingConfigMap),
+		predicate.NewPredicateFuncs(r.isReadByTemplates))
+}
+
+// isReadByTemplates filters out config maps no template generates accounts from
+func (r *ReconcileSpinnakerAccountTemplate) isReadByTemplates(o client.Object) bool {
+	return len(r.templatesReadingConfigMap(o)) > 0
+}
+
+// templatesSelectingNamespaces returns templates with a namespace selector. Namespaces that stopped matching the
+// selector need to be reconciled too, so templates aren't filtered on the namespace labels.
+func (r *ReconcileSpinnakerAccountTemplate) templatesSelectingNamespaces(client.Object) []reconcile.Request {
+	return r.findTemplates("", func(spec *interfaces.SpinnakerAccountTemplateSpec) bool {
+		return spec.Generator.NamespaceSelector != nil
+	})
+}
+
+// templatesReadingConfigMap returns templates generating accounts from the config map
+func (r *ReconcileSpinnakerAccountTemplate) templatesReadingConfigMap(o client.Object) []reconcile.Request {
+	return r.findTemplates(o.GetNamespace(), func(spec *interfaces.SpinnakerAccountTemplateSpec) bool {
+		return spec.Generator.ConfigMap == o.GetName()
+	})
+}
+
+func (r *ReconcileSpinnakerAccountTemplate) findTemplates(ns string, filter func(*interfaces.SpinnakerAccountTemplateSpec) bool) []reconcile.Request {
+	l := TypesFactory.NewAccountTemplateList()
+	if err := r.client.List(context.TODO(), l, client.InNamespace(ns)); err != nil {
+		log.Error(err, "unable to list SpinnakerAccountTemplates")
+		return nil
+	}
+	reqs := make([]reconcile.Request, 0)
+	for _, t := range l.GetItems() {
+		if filter(t.GetSpec()) {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: t.GetNamespace(), Name: t.GetName()}})
+		}
+	}
+	return reqs
+}
+
+// blank assignment to verify that ReconcileSpinnakerAccountTemplate implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileSpinnakerAccountTemplate{}
+
+// ReconcileSpinnakerAccountTemplate reconciles a SpinnakerAccountTemplate object
+type ReconcileSpinnakerAccountTemplate struct {
+	client      client.Client
+	evtRecorder record.EventRecorder
+}
+
+// Reconcile generates the SpinnakerAccounts of the template. Generated accounts are owned by the template: they're
+// updated when the template or its generator change and deleted when they're no longer generated.
+// The SpinnakerAccount controller then validates and deploys them like any other account.
+func (r *ReconcileSpinnakerAccountTemplate) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling SpinnakerAccountTemplate")
+
+	tmpl := TypesFactory.NewAccountTemplate()
+	if err := r.client.Get(ctx, request.NamespacedName, tmpl); err != nil {
+		if errors.IsNotFound(err) {
+			// Generated accounts are garbage collected
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	status := tmpl.GetStatus().DeepCopy()
+	names, err := r.generate(ctx, tmpl)
+	if err != nil {
+		reqLogger.Info("unable to generate accounts", "reason", err.Error())
+		r.evtRecorder.Eventf(tmpl, corev1.EventTypeWarning, "GenerationFailed", "Unable to generate accounts: %s", err.Error())
+		tmpl.GetStatus().InvalidReason = err.Error()
+	} else {
+		tmpl.GetStatus().Accounts = names
+		tmpl.GetStatus().InvalidReason = ""
+	}
+	if !reflect.DeepEqual(status, tmpl.GetStatus()) {
+		if err := r.client.Status().Update(ctx, tmpl); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	// Invalid templates are reconciled again when they or their generator change
+	return reconcile.Result{}, nil
+}
+
+// generate creates or updates the accounts of the template and deletes accounts it no longer generates.
+// Nothing is changed if any account fails to render. It returns the names of the generated accounts.
+func (r *ReconcileSpinnakerAccountTemplate) generate(ctx context.Context, tmpl interfaces.SpinnakerAccountTemplate) ([]string, error) {
+	params, err := accounts.GenerateParameters(ctx, r.client, tmpl)
+	if err != nil {
+		return nil, err
+	}
+	rendered := make([]interfaces.SpinnakerAccount, 0, len(params))
+	for _, p := range params {
+		acc, err := accounts.RenderAccount(tmpl, p)
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %w", p.Name(), err)
+		}
+		rendered = append(rendered, acc)
+	}
+
+	names := make([]string, 0, len(rendered))
+	for _, acc := range rendered {
+		if err := r.apply(ctx, tmpl, acc); err != nil {
+			return nil, err
+		}
+		names = append(names, acc.GetName())
+	}
+
+	l := TypesFactory.NewAccountList()
+	if err := r.client.List(ctx, l, client.InNamespace(tmpl.GetNamespace()), client.MatchingLabels{accounts.TemplateLabel: tmpl.GetName()}); err != nil {
+		return nil, err
+	}
+	for _, acc := range l.GetItems() {
+		if contains(names, acc.GetName()) || !metav1.IsControlledBy(acc, tmpl) {
+			continue
+		}
+		log.Info("deleting account no longer generated", "metadata.name", acc.GetName())
+		if err := r.client.Delete(ctx, acc); err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		r.evtRecorder.Eventf(tmpl, corev1.EventTypeNormal, "AccountDeleted", "Deleted account %s", acc.GetName())
+	}
+	return names, nil
+}
+
+// apply creates or updates the spec of a generated account. Accounts with the same name not generated by
+// the template are left untouched.
+func (r *ReconcileSpinnakerAccountTemplate) apply(ctx context.Context, tmpl interfaces.SpinnakerAccountTemplate, rendered interfaces.SpinnakerAccount) error {
+	acc := TypesFactory.NewAccount()
+	acc.SetNamespace(rendered.GetNamespace())
+	acc.SetName(rendered.GetName())
+	err := r.client.Get(ctx, client.ObjectKeyFromObject(acc), acc)
+	if err == nil && !metav1.IsControlledBy(acc, tmpl) {
+		return fmt.Errorf("SpinnakerAccount %s already exists and isn't generated by the template", acc.GetName())
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	res, err := controllerutil.CreateOrUpdate(ctx, r.client, acc, func() error {
+		labels := acc.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[accounts.TemplateLabel] = tmpl.GetName()
+		acc.SetLabels(labels)
+		*acc.GetSpec() = *rendered.GetSpec()
+		return controllerutil.SetControllerReference(tmpl, acc, r.client.Scheme())
+	})
+	if err != nil {
+		return err
+	}
+	if res == controllerutil.OperationResultCreated {
+		r.evtRecorder.Eventf(tmpl, corev1.EventTypeNormal, "AccountCreated", "Created account %s", acc.GetName())
+	}
+	return nil
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
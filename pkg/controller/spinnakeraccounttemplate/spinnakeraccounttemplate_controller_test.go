@@ -0,0 +1,147 @@
+package spinnakeraccounttemplate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func init() {
+	TypesFactory = test.TypesFactory
+	accounts.TypesFactory = test.TypesFactory
+}
+
+func newReconcilerWithObjects(t *testing.T, objs ...client.Object) *ReconcileSpinnakerAccountTemplate {
+	s := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{corev1.AddToScheme, v1alpha2.SchemeBuilder.AddToScheme} {
+		if err := add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &ReconcileSpinnakerAccountTemplate{
+		client:      fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build(),
+		evtRecorder: record.NewFakeRecorder(10),
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	tmpl := &v1alpha2.SpinnakerAccountTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "spinnaker", UID: "1234"},
+		Spec: interfaces.SpinnakerAccountTemplateSpec{
+			Template: interfaces.SpinnakerAccountSpec{
+				Type:       interfaces.KubernetesAccountType,
+				Enabled:    true,
+				Kubernetes: &interfaces.KubernetesAuth{KubeconfigFile: "{{ .kubeconfig }}"},
+			},
+			Generator: interfaces.AccountGenerator{List: []map[string]string{
+				{"name": "a", "kubeconfig": "/a.yml"},
+				{"name": "b", "kubeconfig": "/b.yml"},
+			}},
+		},
+	}
+	r := newReconcilerWithObjects(t, tmpl)
+	ctx := context.TODO()
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "spinnaker", Name: "team"}}
+
+	getAccount := func(name string) (*v1alpha2.SpinnakerAccount, error) {
+		acc := &v1alpha2.SpinnakerAccount{}
+		return acc, r.client.Get(ctx, client.ObjectKey{Namespace: "spinnaker", Name: name}, acc)
+	}
+	getTemplate := func() *v1alpha2.SpinnakerAccountTemplate {
+		tmpl := &v1alpha2.SpinnakerAccountTemplate{}
+		assert.Nil(t, r.client.Get(ctx, req.NamespacedName, tmpl))
+		return tmpl
+	}
+
+	_, err := r.Reconcile(ctx, req)
+	if !assert.Nil(t, err) {
+		return
+	}
+	acc, err := getAccount("team-a")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "/a.yml", acc.Spec.Kubernetes.KubeconfigFile)
+		assert.True(t, metav1.IsControlledBy(acc, tmpl))
+	}
+	assert.Equal(t, []string{"team-a", "team-b"}, getTemplate().Status.Accounts)
+
+	// Account b is no longer generated and account a is updated
+	tmpl = getTemplate()
+	tmpl.Spec.Generator.List = []map[string]string{{"name": "a", "kubeconfig": "/new.yml"}}
+	assert.Nil(t, r.client.Update(ctx, tmpl))
+	_, err = r.Reconcile(ctx, req)
+	assert.Nil(t, err)
+	if acc, err = getAccount("team-a"); assert.Nil(t, err) {
+		assert.Equal(t, "/new.yml", acc.Spec.Kubernetes.KubeconfigFile)
+	}
+	_, err = getAccount("team-b")
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"team-a"}, getTemplate().Status.Accounts)
+
+	// Invalid templates don't change accounts
+	tmpl = getTemplate()
+	tmpl.Spec.Generator.List = []map[string]string{{"name": "a"}}
+	assert.Nil(t, r.client.Update(ctx, tmpl))
+	_, err = r.Reconcile(ctx, req)
+	assert.Nil(t, err)
+	tmpl = getTemplate()
+	assert.NotEmpty(t, tmpl.Status.InvalidReason)
+	assert.Equal(t, []string{"team-a"}, tmpl.Status.Accounts)
+	_, err = getAccount("team-a")
+	assert.Nil(t, err)
+}
+
+func TestReconcileExistingAccount(t *testing.T) {
+	tmpl := &v1alpha2.SpinnakerAccountTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "spinnaker", UID: "1234"},
+		Spec: interfaces.SpinnakerAccountTemplateSpec{
+			Template: interfaces.SpinnakerAccountSpec{
+				Type:       interfaces.KubernetesAccountType,
+				Kubernetes: &interfaces.KubernetesAuth{KubeconfigFile: "/a.yml"},
+			},
+			Generator: interfaces.AccountGenerator{List: []map[string]string{{"name": "a"}}},
+		},
+	}
+	existing := &v1alpha2.SpinnakerAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "spinnaker"},
+		Spec:       interfaces.SpinnakerAccountSpec{Type: interfaces.KubernetesAccountType},
+	}
+	r := newReconcilerWithObjects(t, tmpl, existing)
+	_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "spinnaker", Name: "team"}})
+	assert.Nil(t, err)
+
+	acc := &v1alpha2.SpinnakerAccount{}
+	if assert.Nil(t, r.client.Get(context.TODO(), client.ObjectKeyFromObject(existing), acc)) {
+		assert.Nil(t, acc.Spec.Kubernetes)
+	}
+	assert.Nil(t, r.client.Get(context.TODO(), client.ObjectKeyFromObject(tmpl), tmpl))
+	assert.Contains(t, tmpl.Status.InvalidReason, "already exists")
+}
+
+func TestIsReadByTemplates(t *testing.T) {
+	tmpl := &v1alpha2.SpinnakerAccountTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "spinnaker"},
+		Spec: interfaces.SpinnakerAccountTemplateSpec{
+			Generator: interfaces.AccountGenerator{ConfigMap: "team-accounts"},
+		},
+	}
+	r := newReconcilerWithObjects(t, tmpl)
+	cm := func(ns, name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}
+	}
+	assert.True(t, r.isReadByTemplates(cm("spinnaker", "team-accounts")))
+	assert.False(t, r.isReadByTemplates(cm("spinnaker", "other")))
+	assert.False(t, r.isReadByTemplates(cm("other", "team-accounts")))
+}
@@ -3,6 +3,7 @@ package spinnakerservice
 import (
 	"context"
 	"fmt"
+	"github.com/armory/spinnaker-operator/pkg/accounts"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/armory/spinnaker-operator/pkg/deploy"
 	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy"
@@ -14,13 +15,17 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
+	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -79,10 +84,63 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	if err != nil {
 		return err
 	}
-	return c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForOwner{
+	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
 		OwnerType:    TypesFactory.NewService(),
 	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for SpinnakerAccount changes that require a redeploy or change the accounts listed in status
+	return c.Watch(&source.Kind{Type: TypesFactory.NewAccount()}, handler.EnqueueRequestsFromMapFunc(spinnakerServicesWithAccounts(mgr.GetClient())), accountChangedPredicate)
+}
+
+// accountChangedPredicate lets through account updates changing what is deployed: spec, labels, deletion and validity.
+// Updates of status.lastValidatedAt by background validation and rotated credentials, updated in place by the
+// SpinnakerAccount controller, are ignored.
+var accountChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		o, ok := e.ObjectOld.(interfaces.SpinnakerAccount)
+		if !ok {
+			return true
+		}
+		n, ok := e.ObjectNew.(interfaces.SpinnakerAccount)
+		if !ok {
+			return true
+		}
+		if o.GetGeneration() != n.GetGeneration() ||
+			!reflect.DeepEqual(o.GetLabels(), n.GetLabels()) ||
+			(o.GetDeletionTimestamp() == nil) != (n.GetDeletionTimestamp() == nil) {
+			return true
+		}
+		os, ns := o.GetStatus(), n.GetStatus()
+		return os.InvalidReason != ns.InvalidReason || os.ExcludedReason != ns.ExcludedReason
+	},
+}
+
+// spinnakerServicesWithAccounts returns the SpinnakerServices accepting accounts that select the account or have it in
+// their status. Services of SpinnakerServices with dynamic accounts are updated by the SpinnakerAccount controller, only
+// their status is updated.
+func spinnakerServicesWithAccounts(c client.Client) handler.MapFunc {
+	return func(o client.Object) []reconcile.Request {
+		acc, ok := o.(interfaces.SpinnakerAccount)
+		if !ok {
+			return nil
+		}
+		spinsvcs, err := accounts.FindSpinnakerServices(context.TODO(), c, acc)
+		if err != nil {
+			log.Error(err, "unable to find SpinnakerServices of account", "Namespace", acc.GetNamespace(), "Name", acc.GetName())
+			return nil
+		}
+		reqs := make([]reconcile.Request, 0)
+		for _, s := range spinsvcs {
+			if s.GetAccountConfig().Enabled {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: s.GetNamespace(), Name: s.GetName()}})
+			}
+		}
+		return reqs
+	}
 }
 
 // blank assignment to verify that ReconcileSpinnakerService implements reconcile.Reconciler
@@ -110,6 +168,7 @@ func (r *ReconcileSpinnakerService) Reconcile(ctx context.Context, request recon
 
 	// Fetch the SpinnakerService instance
 	instance := TypesFactory.NewService()
+	ctx = secrets.NewContext(ctx, r.restConfig, request.Namespace)
 	defer secrets.Cleanup(ctx)
 
 	err := r.client.Get(ctx, request.NamespacedName, instance)
@@ -139,6 +198,12 @@ func (r *ReconcileSpinnakerService) Reconcile(ctx context.Context, request recon
 			return reconcile.Result{Requeue: true}, nil
 		}
 	}
+	// Dynamic accounts are updated without redeploying, the accounts included are recorded with the status
+	if cfg := instance.GetAccountConfig(); cfg.Enabled && cfg.Dynamic {
+		if _, err = accounts.AllValidCRDAccounts(ctx, r.client, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
 	sc := newStatusChecker(r.client, reqLogger, TypesFactory, r.evtRecorder, util.NewK8sLookup(r.client))
 	if err = sc.checks(instance); err != nil {
 		r.evtRecorder.Eventf(instance, corev1.EventTypeWarning, "StatusError", "Error updating SpinnakerService status: %s", err.Error())
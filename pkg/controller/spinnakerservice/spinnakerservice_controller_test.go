@@ -0,0 +1,71 @@
+package spinnakerservice
+
+import (
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func init() {
+	accounts.TypesFactory = test.TypesFactory
+}
+
+func TestSpinnakerServicesWithAccounts(t *testing.T) {
+	spinsvc := func(name string, cfg interfaces.AccountConfig) *v1alpha2.SpinnakerService {
+		return &v1alpha2.SpinnakerService{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1"},
+			Spec:       interfaces.SpinnakerServiceSpec{Accounts: cfg},
+		}
+	}
+	s := runtime.NewScheme()
+	if err := v1alpha2.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		spinsvc("static", interfaces.AccountConfig{Enabled: true}),
+		spinsvc("dynamic", interfaces.AccountConfig{Enabled: true, Dynamic: true}),
+		spinsvc("disabled", interfaces.AccountConfig{}),
+	).Build()
+	acc := &v1alpha2.SpinnakerAccount{ObjectMeta: metav1.ObjectMeta{Name: "aws1", Namespace: "ns1"}}
+
+	reqs := spinnakerServicesWithAccounts(c)(acc)
+	names := make([]string, 0)
+	for _, r := range reqs {
+		assert.Equal(t, "ns1", r.Namespace)
+		names = append(names, r.Name)
+	}
+	assert.ElementsMatch(t, []string{"static", "dynamic"}, names)
+}
+
+func TestAccountChangedPredicate(t *testing.T) {
+	old := &v1alpha2.SpinnakerAccount{ObjectMeta: metav1.ObjectMeta{Name: "aws1", Namespace: "ns1", Generation: 1}}
+	cases := []struct {
+		name     string
+		update   func(a *v1alpha2.SpinnakerAccount)
+		expected bool
+	}{
+		{"validated", func(a *v1alpha2.SpinnakerAccount) { a.Status.LastValidatedAt = &metav1.Timestamp{Seconds: 1} }, false},
+		{"spec", func(a *v1alpha2.SpinnakerAccount) { a.Generation = 2 }, true},
+		{"labels", func(a *v1alpha2.SpinnakerAccount) { a.Labels = map[string]string{"team": "a"} }, true},
+		{"invalid", func(a *v1alpha2.SpinnakerAccount) { a.Status.InvalidReason = "invalid" }, true},
+		{"excluded", func(a *v1alpha2.SpinnakerAccount) { a.Status.ExcludedReason = "duplicate" }, true},
+		{"rotated", func(a *v1alpha2.SpinnakerAccount) { a.Status.CredentialsRefreshAt = &metav1.Timestamp{Seconds: 1} }, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			n := old.DeepCopy()
+			c.update(n)
+			assert.Equal(t, c.expected, accountChangedPredicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: n}))
+		})
+	}
+	assert.True(t, accountChangedPredicate.Create(event.CreateEvent{Object: old}))
+	assert.True(t, accountChangedPredicate.Delete(event.DeleteEvent{Object: old}))
+}
@@ -3,11 +3,9 @@ package webhook
 import (
 	"errors"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/armory/spinnaker-operator/pkg/util"
-	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	apiAdmissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -46,7 +44,7 @@ func Start(m manager.Manager) error {
 		return errors.New("no kind registered for validation")
 	}
 
-	ns, name, err := getOperatorNameAndNamespace()
+	ns, name, err := util.GetOperatorNamespaceAndName()
 	if err != nil {
 		return err
 	}
@@ -75,22 +73,6 @@ func Start(m manager.Manager) error {
 	return deployValidatingWebhookConfiguration(name, ns, rawClient, c.signingCert)
 }
 
-func getOperatorNameAndNamespace() (string, string, error) {
-	name, err := k8sutil.GetOperatorName()
-	if err != nil {
-		return "", "", err
-	}
-	ns, err := k8sutil.GetOperatorNamespace()
-	if err != nil {
-		envNs := os.Getenv("ADMISSION_PROXY_NAMESPACE")
-		if envNs == "" {
-			return "", "", fmt.Errorf("unable to determine operator namespace. Error: %s and ADMISSION_PROXY_NAMESPACE env var not set", err.Error())
-		}
-		ns = envNs
-	}
-	return ns, name, nil
-}
-
 func generateValidatePath(gvk schema.GroupVersionKind) string {
 	return "/validate-" + strings.Replace(gvk.Group, ".", "-", -1) + "-" +
 		gvk.Version + "-" + strings.ToLower(gvk.Kind)
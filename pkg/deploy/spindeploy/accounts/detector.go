@@ -0,0 +1,53 @@
+package accounts
+
+import (
+	"context"
+	crdaccounts "github.com/armory/spinnaker-operator/pkg/accounts"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/changedetector"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+const AccountsHashKey = "accounts"
+
+type changeDetector struct {
+	client      client.Client
+	log         logr.Logger
+	evtRecorder record.EventRecorder
+}
+
+type ChangeDetectorGenerator struct{}
+
+func (g *ChangeDetectorGenerator) NewChangeDetector(client client.Client, log logr.Logger, evtRecorder record.EventRecorder, scheme *runtime.Scheme) (changedetector.ChangeDetector, error) {
+	return &changeDetector{client: client, log: log, evtRecorder: evtRecorder}, nil
+}
+
+// IsSpinnakerUpToDate returns false if SpinnakerAccounts changed since the last deployment. Dynamic accounts are
+// updated without redeploying Spinnaker and are ignored.
+func (ch *changeDetector) IsSpinnakerUpToDate(ctx context.Context, spinSvc interfaces.SpinnakerService) (bool, error) {
+	cfg := spinSvc.GetAccountConfig()
+	if !cfg.Enabled || cfg.Dynamic {
+		return true, nil
+	}
+	h, err := crdaccounts.CRDAccountsHash(ctx, ch.client, spinSvc)
+	if err != nil {
+		if _, ok := err.(*meta.NoKindMatchError); ok {
+			ch.log.Info("SpinnakerAccount CRD not available, skipping accounts change detection")
+			return true, nil
+		}
+		return false, err
+	}
+
+	st := spinSvc.GetStatus()
+	prior := st.UpdateHashIfNotExist(AccountsHashKey, h, time.Now())
+	return h == prior.Hash, nil
+}
+
+func (ch *changeDetector) AlwaysRun() bool {
+	return true
+}
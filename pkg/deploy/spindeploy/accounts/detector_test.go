@@ -0,0 +1,78 @@
+package accounts
+
+import (
+	"context"
+	"testing"
+
+	crdaccounts "github.com/armory/spinnaker-operator/pkg/accounts"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func init() {
+	crdaccounts.TypesFactory = test.TypesFactory
+}
+
+func newAccount(name, accountId string) *v1alpha2.SpinnakerAccount {
+	return &v1alpha2.SpinnakerAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1"},
+		Spec: interfaces.SpinnakerAccountSpec{
+			Type:    interfaces.AWSAccountType,
+			Enabled: true,
+			AWS:     &interfaces.AWSAccount{AccountId: accountId},
+		},
+	}
+}
+
+func isUpToDate(t *testing.T, spinsvc interfaces.SpinnakerService, objs ...client.Object) bool {
+	s := runtime.NewScheme()
+	if err := v1alpha2.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+	ch, err := (&ChangeDetectorGenerator{}).NewChangeDetector(c, log.Log.WithName("spinnakerservice"), &record.FakeRecorder{}, s)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	up, err := ch.IsSpinnakerUpToDate(context.TODO(), spinsvc)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return up
+}
+
+func TestIsSpinnakerUpToDate(t *testing.T) {
+	spinsvc := &v1alpha2.SpinnakerService{
+		ObjectMeta: metav1.ObjectMeta{Name: "spinnaker", Namespace: "ns1"},
+		Spec:       interfaces.SpinnakerServiceSpec{Accounts: interfaces.AccountConfig{Enabled: true}},
+	}
+
+	// First run records the hash
+	assert.False(t, isUpToDate(t, spinsvc, newAccount("aws1", "123456789012")))
+	assert.NotEmpty(t, spinsvc.Status.GetHash(AccountsHashKey).Hash)
+	assert.True(t, isUpToDate(t, spinsvc, newAccount("aws1", "123456789012")))
+
+	assert.False(t, isUpToDate(t, spinsvc, newAccount("aws1", "210987654321")))
+	assert.False(t, isUpToDate(t, spinsvc, newAccount("aws1", "210987654321"), newAccount("aws2", "123456789012")))
+	assert.False(t, isUpToDate(t, spinsvc))
+	assert.True(t, isUpToDate(t, spinsvc))
+}
+
+func TestIsSpinnakerUpToDateDynamicAccounts(t *testing.T) {
+	for _, cfg := range []interfaces.AccountConfig{{Enabled: false}, {Enabled: true, Dynamic: true}} {
+		spinsvc := &v1alpha2.SpinnakerService{
+			ObjectMeta: metav1.ObjectMeta{Name: "spinnaker", Namespace: "ns1"},
+			Spec:       interfaces.SpinnakerServiceSpec{Accounts: cfg},
+		}
+		assert.True(t, isUpToDate(t, spinsvc, newAccount("aws1", "123456789012")))
+		assert.Nil(t, spinsvc.Status.GetHash(AccountsHashKey))
+	}
+}
@@ -63,6 +63,7 @@ func (t *ingressTransformer) TransformConfig(ctx context.Context) error {
 		return nil
 	}
 	st := t.svc.GetStatus()
+	st.RetainServiceUrls("deck", "gate")
 	gateUrl, err := t.getUrlFromConfig(ctx, util.GateOverrideBaseUrlProp)
 	if err != nil {
 		return fmt.Errorf("error checking ingress URL Gate prop: %v", err)
@@ -86,6 +87,7 @@ func (t *ingressTransformer) TransformConfig(ctx context.Context) error {
 				}
 			}
 			st.APIUrl = gateUrl.String()
+			st.SetServiceUrl("gate", gateUrl.String())
 		}
 	}
 
@@ -106,6 +108,7 @@ func (t *ingressTransformer) TransformConfig(ctx context.Context) error {
 				return err
 			}
 			st.UIUrl = deckUrl.String()
+			st.SetServiceUrl("deck", deckUrl.String())
 			return nil
 		}
 	}
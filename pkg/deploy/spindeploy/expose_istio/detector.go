@@ -0,0 +1,141 @@
+package expose_istio
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/changedetector"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ExposeHashKey is the key of the hash of spec.expose in status.lastDeployed
+const ExposeHashKey = "expose"
+
+type changeDetector struct {
+	client      client.Client
+	log         logr.Logger
+	evtRecorder record.EventRecorder
+	scheme      *runtime.Scheme
+}
+
+type ChangeDetectorGenerator struct{}
+
+func (g *ChangeDetectorGenerator) NewChangeDetector(client client.Client, log logr.Logger, evtRecorder record.EventRecorder, scheme *runtime.Scheme) (changedetector.ChangeDetector, error) {
+	return &changeDetector{client: client, log: log, evtRecorder: evtRecorder, scheme: scheme}, nil
+}
+
+func applies(svc interfaces.SpinnakerService) bool {
+	return svc.GetExposeConfig() != nil && svc.GetExposeConfig().Type == "istio"
+}
+
+// IsSpinnakerUpToDate returns true if the expose configuration is the one last deployed, a VirtualService exists for
+// each exposed host and VirtualServices of hosts no longer exposed are deleted
+func (ch *changeDetector) IsSpinnakerUpToDate(ctx context.Context, svc interfaces.SpinnakerService) (bool, error) {
+	if !applies(svc) {
+		return true, nil
+	}
+	exp := svc.GetExposeConfig()
+	// Paths, ports, TLS mode and sidecar injection are only checked through the hash of the configuration
+	h, err := exposeHash(exp)
+	if err != nil {
+		return false, err
+	}
+	if prior := svc.GetStatus().UpdateHashIfNotExist(ExposeHashKey, h, time.Now()); prior.Hash != h {
+		ch.log.Info("spec.expose changed since the last deployment")
+		return false, nil
+	}
+
+	gw := getGateway(exp, svc.GetNamespace())
+	byHost := getHostServices(exp.Istio.Hosts)
+	for host, services := range byHost {
+		upToDate, err := ch.isVirtualServiceUpToDate(ctx, svc, host, gw, services)
+		if !upToDate || err != nil {
+			return false, err
+		}
+	}
+	stale, err := staleVirtualServices(ctx, ch.client, svc, byHost)
+	if err != nil {
+		return false, err
+	}
+	if len(stale) > 0 {
+		ch.log.Info(fmt.Sprintf("VirtualService %s is no longer exposed", stale[0].GetName()))
+		return false, nil
+	}
+
+	st := svc.GetStatus()
+	if h, ok := exp.Istio.Hosts["gate"]; ok && st.APIUrl == "" {
+		ch.log.Info(fmt.Sprintf("Gate URL is not set in status, expected %s", getIstioUrl(h)))
+		return false, nil
+	}
+	if h, ok := exp.Istio.Hosts["deck"]; ok && st.UIUrl == "" {
+		ch.log.Info(fmt.Sprintf("Deck URL is not set in status, expected %s", getIstioUrl(h)))
+		return false, nil
+	}
+	return true, nil
+}
+
+func exposeHash(exp *interfaces.ExposeConfig) (string, error) {
+	data, err := json.Marshal(exp)
+	if err != nil {
+		return "", err
+	}
+	m := md5.Sum(data)
+	return hex.EncodeToString(m[:]), nil
+}
+
+// AlwaysRun is true for the hash of spec.expose to be recorded with each deployment
+func (ch *changeDetector) AlwaysRun() bool {
+	return true
+}
+
+// isVirtualServiceUpToDate returns true if the VirtualService of the host routes to all the services exposed on it
+func (ch *changeDetector) isVirtualServiceUpToDate(ctx context.Context, spinSvc interfaces.SpinnakerService, host, gateway string, services []string) (bool, error) {
+	rLogger := ch.log.WithValues("Service", spinSvc.GetName())
+	name := getVirtualServiceName(host)
+	vs := &unstructured.Unstructured{}
+	vs.SetGroupVersionKind(virtualServiceGVK)
+	err := ch.client.Get(ctx, client.ObjectKey{Namespace: spinSvc.GetNamespace(), Name: name}, vs)
+	if errors.IsNotFound(err) {
+		rLogger.Info(fmt.Sprintf("VirtualService for %s not found", host))
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	gateways, _, _ := unstructured.NestedStringSlice(vs.Object, "spec", "gateways")
+	if !reflect.DeepEqual(gateways, []string{gateway}) {
+		rLogger.Info(fmt.Sprintf("VirtualService gateways for %s: expected: %s, actual: %s", host, gateway, gateways))
+		return false, nil
+	}
+	routes, _, _ := unstructured.NestedSlice(vs.Object, "spec", "http")
+	var actual []string
+	for _, r := range routes {
+		if m, ok := r.(map[string]interface{}); ok {
+			n, _, _ := unstructured.NestedString(m, "name")
+			actual = append(actual, n)
+		}
+	}
+	sort.Strings(actual)
+	var expected []string
+	for _, s := range services {
+		expected = append(expected, fmt.Sprintf("spin-%s", s))
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		rLogger.Info(fmt.Sprintf("VirtualService routes for %s: expected: %s, actual: %s", host, expected, actual))
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,131 @@
+package expose_istio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/changedetectortest"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// deployed records the expose configuration of the SpinnakerService as deployed
+func deployed(spinSvc interfaces.SpinnakerService, t *testing.T) {
+	h, err := exposeHash(spinSvc.GetExposeConfig())
+	assert.Nil(t, err)
+	spinSvc.GetStatus().UpdateHashIfNotExist(ExposeHashKey, h, time.Now())
+	spinSvc.GetStatus().UIUrl = "https://spinnaker.acme.com"
+	spinSvc.GetStatus().APIUrl = "https://spinnaker.acme.com/api"
+}
+
+func TestIsSpinnakerUpToDate_NoVirtualServicesYet(t *testing.T) {
+	ch := changedetectortest.SetupChangeDetector(&ChangeDetectorGenerator{}, t)
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc_expose_istio.yml", t)
+
+	upToDate, err := ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+
+	assert.False(t, upToDate)
+	assert.Nil(t, err)
+}
+
+func TestIsSpinnakerUpToDate_VirtualServicesUpToDate(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc_expose_istio.yml", t)
+	h := spinSvc.GetExposeConfig().Istio.Hosts
+	gw := "istio-system/public-gateway"
+	ch := changedetectortest.SetupChangeDetector(&ChangeDetectorGenerator{}, t,
+		newVirtualService("spinnaker.acme.com", "ns1", gw, []istioRoute{
+			{serviceName: "spin-deck", port: 9000, path: h["deck"].Path},
+			{serviceName: "spin-gate", port: 8084, path: h["gate"].Path},
+		}))
+	deployed(spinSvc, t)
+
+	upToDate, err := ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+
+	assert.True(t, upToDate)
+	assert.Nil(t, err)
+}
+
+func TestIsSpinnakerUpToDate_HostChanged(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc_expose_istio.yml", t)
+	h := spinSvc.GetExposeConfig().Istio.Hosts
+	gw := "istio-system/public-gateway"
+	ch := changedetectortest.SetupChangeDetector(&ChangeDetectorGenerator{}, t,
+		newVirtualService("spinnaker.acme.com", "ns1", gw, []istioRoute{
+			{serviceName: "spin-deck", port: 9000, path: h["deck"].Path},
+			{serviceName: "spin-gate", port: 8084, path: h["gate"].Path},
+		}))
+	deployed(spinSvc, t)
+	d := h["deck"]
+	d.Host = "spin.acme.com"
+	h["deck"] = d
+
+	upToDate, err := ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+
+	assert.False(t, upToDate)
+	assert.Nil(t, err)
+}
+
+func TestIsSpinnakerUpToDate_ServiceAddedToHost(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc_expose_istio.yml", t)
+	gw := "istio-system/public-gateway"
+	ch := changedetectortest.SetupChangeDetector(&ChangeDetectorGenerator{}, t,
+		newVirtualService("spinnaker.acme.com", "ns1", gw, []istioRoute{{serviceName: "spin-deck", port: 9000}}))
+	deployed(spinSvc, t)
+
+	upToDate, err := ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+
+	assert.False(t, upToDate)
+	assert.Nil(t, err)
+}
+
+func TestIsSpinnakerUpToDate_PathChanged(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc_expose_istio.yml", t)
+	h := spinSvc.GetExposeConfig().Istio.Hosts
+	gw := "istio-system/public-gateway"
+	ch := changedetectortest.SetupChangeDetector(&ChangeDetectorGenerator{}, t,
+		newVirtualService("spinnaker.acme.com", "ns1", gw, []istioRoute{
+			{serviceName: "spin-deck", port: 9000, path: h["deck"].Path},
+			{serviceName: "spin-gate", port: 8084, path: h["gate"].Path},
+		}))
+	deployed(spinSvc, t)
+	g := h["gate"]
+	g.Path = "/gate"
+	h["gate"] = g
+
+	upToDate, err := ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+
+	assert.False(t, upToDate)
+	assert.Nil(t, err)
+}
+
+func TestIsSpinnakerUpToDate_HostRemoved(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc_expose_istio.yml", t)
+	spinSvc.SetUID("spinsvc-uid")
+	h := spinSvc.GetExposeConfig().Istio.Hosts
+	gw := "istio-system/public-gateway"
+	dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "spin-deck", Namespace: "ns1", UID: "deck-uid"}}
+	dep.OwnerReferences = []metav1.OwnerReference{ownerRef("SpinnakerService", "spinnaker", "spinsvc-uid")}
+	old := newVirtualService("old.acme.com", "ns1", gw, []istioRoute{{serviceName: "spin-deck", port: 9000}})
+	old.SetOwnerReferences([]metav1.OwnerReference{ownerRef("Deployment", "spin-deck", "deck-uid")})
+	ch := changedetectortest.SetupChangeDetector(&ChangeDetectorGenerator{}, t,
+		newVirtualService("spinnaker.acme.com", "ns1", gw, []istioRoute{
+			{serviceName: "spin-deck", port: 9000, path: h["deck"].Path},
+			{serviceName: "spin-gate", port: 8084, path: h["gate"].Path},
+		}), dep, old)
+	deployed(spinSvc, t)
+
+	upToDate, err := ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+
+	assert.False(t, upToDate)
+	assert.Nil(t, err)
+}
+
+func ownerRef(kind, name string, uid types.UID) metav1.OwnerReference {
+	controller := true
+	return metav1.OwnerReference{Kind: kind, Name: name, UID: uid, Controller: &controller}
+}
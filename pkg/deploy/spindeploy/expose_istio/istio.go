@@ -0,0 +1,195 @@
+package expose_istio
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const sidecarInjectLabel = "sidecar.istio.io/inject"
+
+var (
+	virtualServiceGVK  = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1beta1", Kind: "VirtualService"}
+	destinationRuleGVK = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1beta1", Kind: "DestinationRule"}
+)
+
+// getIstioUrl returns the public URL of a service exposed with the given host configuration
+func getIstioUrl(h interfaces.ExposeConfigIstioHost) *url.URL {
+	scheme := h.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	return &url.URL{
+		Scheme: scheme,
+		Host:   h.Host,
+		Path:   h.Path,
+	}
+}
+
+// getGateway returns the gateway reference qualified with the namespace
+func getGateway(exp *interfaces.ExposeConfig, ns string) string {
+	gw := exp.Istio.Gateway
+	if gw == "" || strings.Contains(gw, "/") {
+		return gw
+	}
+	return fmt.Sprintf("%s/%s", ns, gw)
+}
+
+func getServiceHost(serviceName, ns string) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, ns)
+}
+
+// istioRoute routes a path prefix of a host to a Kubernetes service
+type istioRoute struct {
+	serviceName string
+	port        int32
+	path        string
+}
+
+// getHostServices returns the exposed Spinnaker services by host, sorted by name
+func getHostServices(hosts map[string]interfaces.ExposeConfigIstioHost) map[string][]string {
+	byHost := make(map[string][]string)
+	for svc, h := range hosts {
+		byHost[h.Host] = append(byHost[h.Host], svc)
+	}
+	for _, svcs := range byHost {
+		sort.Strings(svcs)
+	}
+	return byHost
+}
+
+// getVirtualServiceName returns the name of the VirtualService of a host (e.g. spin-spinnaker-acme-com)
+func getVirtualServiceName(host string) string {
+	n := strings.NewReplacer(".", "-", "*", "wildcard").Replace(strings.ToLower(host))
+	return fmt.Sprintf("spin-%s", strings.Trim(n, "-"))
+}
+
+// staleVirtualServices returns the VirtualServices deployed for hosts no longer exposed. Only VirtualServices
+// controlled by a Deployment of the SpinnakerService are considered, others may have been written by users.
+func staleVirtualServices(ctx context.Context, c client.Client, spinSvc interfaces.SpinnakerService, hosts map[string][]string) ([]*unstructured.Unstructured, error) {
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(virtualServiceGVK.GroupVersion().WithKind("VirtualServiceList"))
+	if err := c.List(ctx, l, client.InNamespace(spinSvc.GetNamespace())); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for host := range hosts {
+		names[getVirtualServiceName(host)] = true
+	}
+	var stale []*unstructured.Unstructured
+	for i := range l.Items {
+		vs := &l.Items[i]
+		ref := metav1.GetControllerOf(vs)
+		if names[vs.GetName()] || ref == nil || ref.Kind != "Deployment" {
+			continue
+		}
+		dep := &appsv1.Deployment{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: spinSvc.GetNamespace(), Name: ref.Name}, dep); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if dep.UID == ref.UID && metav1.IsControlledBy(dep, spinSvc) {
+			stale = append(stale, vs)
+		}
+	}
+	return stale, nil
+}
+
+// newVirtualService makes a VirtualService routing each path prefix of the host to its Kubernetes service.
+// Routes are ordered from the longest path to the root path so that the root doesn't shadow other services.
+func newVirtualService(host, ns, gateway string, routes []istioRoute) *unstructured.Unstructured {
+	sorted := make([]istioRoute, len(routes))
+	copy(sorted, routes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(strings.TrimSuffix(sorted[i].path, "/")) > len(strings.TrimSuffix(sorted[j].path, "/"))
+	})
+	var http []interface{}
+	for _, r := range sorted {
+		http = append(http, newHTTPRoute(r, ns))
+	}
+
+	vs := &unstructured.Unstructured{}
+	vs.SetGroupVersionKind(virtualServiceGVK)
+	vs.SetName(getVirtualServiceName(host))
+	vs.SetNamespace(ns)
+	vs.Object["spec"] = map[string]interface{}{
+		"hosts":    []interface{}{host},
+		"gateways": []interface{}{gateway},
+		"http":     http,
+	}
+	return vs
+}
+
+// newHTTPRoute routes the path and anything below it to the service, with the path rewritten to the root
+// (e.g. /api and /api/foo to / and /foo)
+func newHTTPRoute(r istioRoute, ns string) map[string]interface{} {
+	route := map[string]interface{}{
+		"name": r.serviceName,
+		"route": []interface{}{
+			map[string]interface{}{
+				"destination": map[string]interface{}{
+					"host": getServiceHost(r.serviceName, ns),
+					"port": map[string]interface{}{
+						"number": int64(r.port),
+					},
+				},
+			},
+		},
+	}
+	p := strings.TrimSuffix(r.path, "/")
+	if p == "" {
+		return route
+	}
+	route["match"] = []interface{}{
+		map[string]interface{}{
+			"uri": map[string]interface{}{
+				"exact": p,
+			},
+		},
+		map[string]interface{}{
+			"uri": map[string]interface{}{
+				"prefix": p + "/",
+			},
+		},
+	}
+	route["rewrite"] = map[string]interface{}{
+		"uri": "/",
+	}
+	return route
+}
+
+// newDestinationRule makes a DestinationRule for the Kubernetes service, with the configured TLS mode if any
+func newDestinationRule(serviceName, ns, tlsMode string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"host": getServiceHost(serviceName, ns),
+	}
+	if tlsMode != "" {
+		spec["trafficPolicy"] = map[string]interface{}{
+			"tls": map[string]interface{}{
+				"mode": tlsMode,
+			},
+		}
+	}
+	dr := &unstructured.Unstructured{}
+	dr.SetGroupVersionKind(destinationRuleGVK)
+	dr.SetName(serviceName)
+	dr.SetNamespace(ns)
+	dr.Object["spec"] = spec
+	return dr
+}
@@ -0,0 +1,160 @@
+package expose_istio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/transformer"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/util"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var noGatewayError = errors.New("spec.expose.istio.gateway is required when exposing Spinnaker with istio")
+
+type TransformerGenerator struct{}
+
+func (tg *TransformerGenerator) NewTransformer(svc interfaces.SpinnakerService,
+	client client.Client, log logr.Logger, scheme *runtime.Scheme) (transformer.Transformer, error) {
+	tr := istioTransformer{svc: svc, log: log, client: client, scheme: scheme}
+	return &tr, nil
+}
+
+func (tg *TransformerGenerator) GetName() string {
+	return "ExposeWithIstio"
+}
+
+type istioTransformer struct {
+	svc    interfaces.SpinnakerService
+	log    logr.Logger
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+func (t *istioTransformer) TransformConfig(ctx context.Context) error {
+	if !applies(t.svc) {
+		return nil
+	}
+	exp := t.svc.GetExposeConfig()
+	if exp.Istio.Gateway == "" {
+		return noGatewayError
+	}
+	st := t.svc.GetStatus()
+	st.RetainServiceUrls("deck", "gate")
+	if h, ok := exp.Istio.Hosts["gate"]; ok {
+		u, err := t.setOverrideBaseUrl(ctx, getIstioUrl(h), util.GateOverrideBaseUrlProp)
+		if err != nil {
+			return err
+		}
+		st.APIUrl = u
+		st.SetServiceUrl("gate", u)
+	}
+	if h, ok := exp.Istio.Hosts["deck"]; ok {
+		u, err := t.setOverrideBaseUrl(ctx, getIstioUrl(h), util.DeckOverrideBaseUrlProp)
+		if err != nil {
+			return err
+		}
+		st.UIUrl = u
+		st.SetServiceUrl("deck", u)
+	}
+	return nil
+}
+
+// setOverrideBaseUrl sets the overrideBaseUrl unless explicitly set by the user and returns the URL to report in status
+func (t *istioTransformer) setOverrideBaseUrl(ctx context.Context, u *url.URL, overrideUrlName string) (string, error) {
+	// ignore error, overrideBaseUrl may not be set in hal config
+	if statusUrl, _ := t.svc.GetSpinnakerConfig().GetHalConfigPropString(ctx, overrideUrlName); statusUrl != "" {
+		return statusUrl, nil
+	}
+	t.log.Info(fmt.Sprintf("setting %s to %s", overrideUrlName, u.String()))
+	if err := t.svc.GetSpinnakerConfig().SetHalConfigProp(overrideUrlName, u.String()); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (t *istioTransformer) TransformManifests(ctx context.Context, gen *generated.SpinnakerGeneratedConfig) error {
+	if !applies(t.svc) {
+		return nil
+	}
+	exp := t.svc.GetExposeConfig()
+	ns := t.svc.GetNamespace()
+	gw := getGateway(exp, ns)
+	byHost := getHostServices(exp.Istio.Hosts)
+	hosts := make([]string, 0, len(byHost))
+	for host := range byHost {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		var routes []istioRoute
+		owner := ""
+		for _, serviceName := range byHost[host] {
+			cfg, ok := gen.Config[serviceName]
+			if !ok || cfg.Service == nil {
+				t.log.Info(fmt.Sprintf("no service generated for %s, not exposing it with istio", serviceName))
+				continue
+			}
+			if len(cfg.Service.Spec.Ports) == 0 {
+				return fmt.Errorf("no port found in service %s", cfg.Service.Name)
+			}
+			routes = append(routes, istioRoute{serviceName: cfg.Service.Name, port: cfg.Service.Spec.Ports[0].Port, path: exp.Istio.Hosts[serviceName].Path})
+			cfg.Resources = append(cfg.Resources, newDestinationRule(cfg.Service.Name, ns, exp.Istio.TLSMode))
+			gen.Config[serviceName] = cfg
+			if owner == "" {
+				owner = serviceName
+			}
+		}
+		if owner == "" {
+			continue
+		}
+		// A single VirtualService per host keeps the order of its routes
+		cfg := gen.Config[owner]
+		cfg.Resources = append(cfg.Resources, newVirtualService(host, ns, gw, routes))
+		gen.Config[owner] = cfg
+	}
+	if err := t.deleteStaleVirtualServices(ctx, byHost, gen); err != nil {
+		return err
+	}
+
+	if !exp.Istio.SidecarInjection {
+		return nil
+	}
+	for _, cfg := range gen.Config {
+		if cfg.Deployment == nil {
+			continue
+		}
+		if cfg.Deployment.Spec.Template.Labels == nil {
+			cfg.Deployment.Spec.Template.Labels = map[string]string{}
+		}
+		cfg.Deployment.Spec.Template.Labels[sidecarInjectLabel] = "true"
+	}
+	return nil
+}
+
+// deleteStaleVirtualServices deletes the VirtualServices of hosts removed from the expose configuration with the
+// service of the Deployment controlling them
+func (t *istioTransformer) deleteStaleVirtualServices(ctx context.Context, byHost map[string][]string, gen *generated.SpinnakerGeneratedConfig) error {
+	stale, err := staleVirtualServices(ctx, t.client, t.svc, byHost)
+	if err != nil {
+		return err
+	}
+	for _, vs := range stale {
+		owner := metav1.GetControllerOf(vs).Name
+		for k, cfg := range gen.Config {
+			if cfg.Deployment != nil && cfg.Deployment.Name == owner {
+				cfg.ToDelete = append(cfg.ToDelete, vs)
+				gen.Config[k] = cfg
+				break
+			}
+		}
+	}
+	return nil
+}
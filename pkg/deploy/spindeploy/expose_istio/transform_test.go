@@ -0,0 +1,149 @@
+package expose_istio
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/transformertest"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTransformConfig_SetsUrls(t *testing.T) {
+	tr, spinSvc := transformertest.SetupTransformerFromSpinFile(&TransformerGenerator{}, "testdata/spinsvc_expose_istio.yml", t)
+
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+
+	u, err := spinSvc.GetSpinnakerConfig().GetHalConfigPropString(context.TODO(), "security.apiSecurity.overrideBaseUrl")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://spinnaker.acme.com/api", u)
+	assert.Equal(t, "https://spinnaker.acme.com/api", spinSvc.GetStatus().APIUrl)
+	u, err = spinSvc.GetSpinnakerConfig().GetHalConfigPropString(context.TODO(), "security.uiSecurity.overrideBaseUrl")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://spinnaker.acme.com", u)
+	assert.Equal(t, "https://spinnaker.acme.com", spinSvc.GetStatus().UIUrl)
+}
+
+func TestTransformConfig_KeepsOverrideBaseUrl(t *testing.T) {
+	tr, spinSvc := transformertest.SetupTransformerFromSpinFile(&TransformerGenerator{}, "testdata/spinsvc_expose_istio.yml", t)
+	err := spinSvc.GetSpinnakerConfig().SetHalConfigProp("security.apiSecurity.overrideBaseUrl", "https://api.acme.com")
+	assert.Nil(t, err)
+
+	err = tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+
+	u, err := spinSvc.GetSpinnakerConfig().GetHalConfigPropString(context.TODO(), "security.apiSecurity.overrideBaseUrl")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://api.acme.com", u)
+	assert.Equal(t, "https://api.acme.com", spinSvc.GetStatus().APIUrl)
+}
+
+func TestTransformConfig_NoGateway(t *testing.T) {
+	tr, spinSvc := transformertest.SetupTransformerFromSpinFile(&TransformerGenerator{}, "testdata/spinsvc_expose_istio.yml", t)
+	spinSvc.GetExposeConfig().Istio.Gateway = ""
+
+	err := tr.TransformConfig(context.TODO())
+	assert.Equal(t, noGatewayError, err)
+}
+
+func TestTransformManifests_CreatesIstioResources(t *testing.T) {
+	tr, _ := transformertest.SetupTransformerFromSpinFile(&TransformerGenerator{}, "testdata/spinsvc_expose_istio.yml", t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	svc := &corev1.Service{}
+	test.ReadYamlFile("testdata/gate_service.yml", svc, t)
+	dep := &v1.Deployment{}
+	test.ReadYamlFile("testdata/gate_deployment.yml", dep, t)
+	deckSvc := &corev1.Service{}
+	deckSvc.Name = "spin-deck"
+	deckSvc.Spec.Ports = []corev1.ServicePort{{Port: 9000}}
+	gen.Config = map[string]generated.ServiceConfig{
+		"deck": {Service: deckSvc},
+		"gate": {Service: svc, Deployment: dep},
+	}
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// deck and gate share the host: a single VirtualService is added to deck
+	rs := gen.Config["deck"].Resources
+	if !assert.Equal(t, 2, len(rs)) {
+		return
+	}
+	vs, ok := rs[1].(*unstructured.Unstructured)
+	if assert.True(t, ok) {
+		assert.Equal(t, "VirtualService", vs.GetKind())
+		assert.Equal(t, "spin-spinnaker-acme-com", vs.GetName())
+		hosts, _, _ := unstructured.NestedStringSlice(vs.Object, "spec", "hosts")
+		assert.Equal(t, []string{"spinnaker.acme.com"}, hosts)
+		gws, _, _ := unstructured.NestedStringSlice(vs.Object, "spec", "gateways")
+		assert.Equal(t, []string{"istio-system/public-gateway"}, gws)
+		routes, _, _ := unstructured.NestedSlice(vs.Object, "spec", "http")
+		if assert.Equal(t, 2, len(routes)) {
+			// gate's path is matched before deck's catch-all
+			r := routes[0].(map[string]interface{})
+			assert.Equal(t, "spin-gate", r["name"])
+			match, _, _ := unstructured.NestedSlice(r, "match")
+			if assert.Equal(t, 2, len(match)) {
+				exact, _, _ := unstructured.NestedString(match[0].(map[string]interface{}), "uri", "exact")
+				assert.Equal(t, "/api", exact)
+				prefix, _, _ := unstructured.NestedString(match[1].(map[string]interface{}), "uri", "prefix")
+				assert.Equal(t, "/api/", prefix)
+			}
+			rewrite, _, _ := unstructured.NestedString(r, "rewrite", "uri")
+			assert.Equal(t, "/", rewrite)
+			r = routes[1].(map[string]interface{})
+			assert.Equal(t, "spin-deck", r["name"])
+			_, found, _ := unstructured.NestedSlice(r, "match")
+			assert.False(t, found)
+		}
+	}
+	rs = gen.Config["gate"].Resources
+	if !assert.Equal(t, 1, len(rs)) {
+		return
+	}
+	dr, ok := rs[0].(*unstructured.Unstructured)
+	if assert.True(t, ok) {
+		assert.Equal(t, "DestinationRule", dr.GetKind())
+		host, _, _ := unstructured.NestedString(dr.Object, "spec", "host")
+		assert.Equal(t, "spin-gate.ns1.svc.cluster.local", host)
+		mode, _, _ := unstructured.NestedString(dr.Object, "spec", "trafficPolicy", "tls", "mode")
+		assert.Equal(t, "ISTIO_MUTUAL", mode)
+	}
+	assert.Equal(t, "true", gen.Config["gate"].Deployment.Spec.Template.Labels[sidecarInjectLabel])
+}
+
+func TestTransformManifests_DeletesStaleVirtualServices(t *testing.T) {
+	dep := &v1.Deployment{}
+	test.ReadYamlFile("testdata/gate_deployment.yml", dep, t)
+	dep.Namespace = "ns1"
+	dep.UID = "gate-uid"
+	dep.OwnerReferences = []metav1.OwnerReference{ownerRef("SpinnakerService", "spinnaker", "spinsvc-uid")}
+	old := newVirtualService("old.acme.com", "ns1", "istio-system/public-gateway", []istioRoute{{serviceName: "spin-gate", port: 8084}})
+	old.SetOwnerReferences([]metav1.OwnerReference{ownerRef("Deployment", dep.Name, "gate-uid")})
+	// Written by users for deck and gate, not owned by the SpinnakerService
+	user := &unstructured.Unstructured{}
+	user.SetGroupVersionKind(virtualServiceGVK)
+	user.SetName("spin-gate")
+	user.SetNamespace("ns1")
+	tr, spinSvc := transformertest.SetupTransformerFromSpinFile(&TransformerGenerator{}, "testdata/spinsvc_expose_istio.yml", t, dep, old, user)
+	spinSvc.SetUID("spinsvc-uid")
+	gen := &generated.SpinnakerGeneratedConfig{Config: map[string]generated.ServiceConfig{
+		"gate": {Deployment: dep},
+	}}
+
+	if !assert.Nil(t, tr.TransformManifests(context.TODO(), gen)) {
+		return
+	}
+	toDelete := gen.Config["gate"].ToDelete
+	if assert.Equal(t, 1, len(toDelete)) {
+		assert.Equal(t, "spin-old-acme-com", toDelete[0].GetName())
+	}
+}
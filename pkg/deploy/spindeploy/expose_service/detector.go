@@ -8,10 +8,12 @@ import (
 	"github.com/armory/spinnaker-operator/pkg/util"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
 )
 
 type changeDetector struct {
@@ -52,6 +54,82 @@ func (ch *changeDetector) IsSpinnakerUpToDate(ctx context.Context, svc interface
 	if !upToDateGate || err != nil {
 		return false, err
 	}
+	extra := getExtraExposedServices(svc.GetExposeConfig())
+	for _, n := range extra {
+		upToDate, err := ch.isExtraExposeServiceUpToDate(ctx, svc, n)
+		if !upToDate || err != nil {
+			return false, err
+		}
+	}
+	return ch.areUnexposedServicesUpToDate(ctx, svc, extra)
+}
+
+// areUnexposedServicesUpToDate checks that deployed services without expose overrides anymore are not exposed
+func (ch *changeDetector) areUnexposedServicesUpToDate(ctx context.Context, spinSvc interfaces.SpinnakerService, extra []string) (bool, error) {
+	exposed := map[string]bool{"deck": true, "gate": true, "gate-x509": true}
+	for _, n := range extra {
+		exposed[n] = true
+	}
+	l := &corev1.ServiceList{}
+	if err := ch.client.List(ctx, l, client.InNamespace(spinSvc.GetNamespace())); err != nil {
+		return false, err
+	}
+	for i := range l.Items {
+		svc := &l.Items[i]
+		if !metav1.IsControlledBy(svc, spinSvc) || !strings.HasPrefix(svc.Name, "spin-") || exposed[svc.Name[len("spin-"):]] {
+			continue
+		}
+		if svc.Spec.Type != corev1.ServiceTypeClusterIP {
+			ch.log.WithValues("Service", spinSvc.GetName()).Info(fmt.Sprintf("Service type for %s: expected: %s, actual: %s",
+				svc.Name, corev1.ServiceTypeClusterIP, svc.Spec.Type))
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// isExtraExposeServiceUpToDate checks a service other than deck and gate that has expose overrides.
+// Services that don't exist are ignored since the override may reference a service that is not deployed.
+func (ch *changeDetector) isExtraExposeServiceUpToDate(ctx context.Context, spinSvc interfaces.SpinnakerService, name string) (bool, error) {
+	rLogger := ch.log.WithValues("Service", spinSvc.GetName())
+	serviceName := fmt.Sprintf("spin-%s", name)
+	svc, err := util.GetService(serviceName, spinSvc.GetNamespace(), ch.client)
+	if err != nil || svc == nil {
+		return true, err
+	}
+
+	if upToDate, err := ch.exposeServiceTypeUpToDate(serviceName, spinSvc, svc); !upToDate || err != nil {
+		return false, err
+	}
+
+	// these services keep their port unless explicitly overridden
+	exp := spinSvc.GetExposeConfig()
+	if c := exp.Service.Overrides[name]; c.PublicPort != 0 {
+		publicPort, _ := ch.getSvcPorts(fmt.Sprintf("%s-tcp", name), svc)
+		if publicPort != c.PublicPort {
+			rLogger.Info(fmt.Sprintf("Service port for %s: expected: %d, actual: %d", serviceName,
+				c.PublicPort, publicPort))
+			return false, nil
+		}
+	}
+
+	expectedAnnotations := exp.GetAggregatedAnnotations(name)
+	if !ch.areAnnotationsEqual(svc.Annotations, expectedAnnotations) {
+		rLogger.Info(fmt.Sprintf("Service annotations for %s: expected: %s, actual: %s", serviceName,
+			expectedAnnotations, svc.Annotations))
+		return false, nil
+	}
+
+	if spinSvc.GetStatus().ServiceUrls[name] == "" {
+		lbUrl, err := util.FindLoadBalancerUrl(serviceName, spinSvc.GetNamespace(), ch.client, false)
+		if err != nil {
+			return false, err
+		}
+		if lbUrl != "" {
+			rLogger.Info(fmt.Sprintf("Status url of %s is not set and load balancer url is ready", serviceName))
+			return false, nil
+		}
+	}
 	return true, nil
 }
 
@@ -4,10 +4,12 @@ import (
 	"context"
 	"testing"
 
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/changedetectortest"
 	"github.com/armory/spinnaker-operator/pkg/test"
 	"github.com/armory/spinnaker-operator/pkg/util"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Running Status: No services exist
@@ -194,3 +196,54 @@ func TestIsSpinnakerUpToDate_NoAnnotations(t *testing.T) {
 	assert.True(t, upToDate)
 	assert.Nil(t, err)
 }
+
+// Expose config with an override for a service other than deck and gate
+func TestIsSpinnakerUpToDate_ExtraServiceTypeChanged(t *testing.T) {
+	ch := changedetectortest.SetupChangeDetector(&ChangeDetectorGenerator{}, t,
+		test.BuildSvc("spin-deck", "LoadBalancer", 80, t),
+		test.BuildSvc("spin-gate", "LoadBalancer", 80, t),
+		test.BuildSvc("spin-echo", "ClusterIP", 8089, t))
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc_expose.yml", t)
+	spinSvc.GetExposeConfig().Service.Overrides = map[string]interfaces.ExposeConfigServiceOverrides{
+		"echo": {Type: "LoadBalancer"},
+	}
+
+	upToDate, err := ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+
+	assert.False(t, upToDate)
+	assert.Nil(t, err)
+}
+
+// Overrides for services that are not deployed are ignored
+func TestIsSpinnakerUpToDate_ExtraServiceNotDeployed(t *testing.T) {
+	deckSvc := test.BuildSvc("spin-deck", "LoadBalancer", 80, t)
+	gateSvc := test.BuildSvc("spin-gate", "LoadBalancer", 80, t)
+	ch := changedetectortest.SetupChangeDetector(&ChangeDetectorGenerator{}, t, deckSvc, gateSvc)
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc_expose.yml", t)
+	spinSvc.GetExposeConfig().Service.Overrides = map[string]interfaces.ExposeConfigServiceOverrides{
+		"igor": {Type: "LoadBalancer"},
+	}
+
+	upToDate, err := ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+
+	assert.True(t, upToDate)
+	assert.Nil(t, err)
+}
+
+// A service whose override was removed is still exposed
+func TestIsSpinnakerUpToDate_ExtraServiceOverrideRemoved(t *testing.T) {
+	spinSvc := test.ManifestFileToSpinService("testdata/spinsvc_expose.yml", t)
+	spinSvc.SetUID("spinsvc-uid")
+	controller := true
+	echoSvc := test.BuildSvc("spin-echo", "LoadBalancer", 8089, t)
+	echoSvc.OwnerReferences = []metav1.OwnerReference{{Kind: "SpinnakerService", Name: spinSvc.GetName(), UID: "spinsvc-uid", Controller: &controller}}
+	ch := changedetectortest.SetupChangeDetector(&ChangeDetectorGenerator{}, t,
+		test.BuildSvc("spin-deck", "LoadBalancer", 80, t),
+		test.BuildSvc("spin-gate", "LoadBalancer", 80, t),
+		echoSvc)
+
+	upToDate, err := ch.IsSpinnakerUpToDate(context.TODO(), spinSvc)
+
+	assert.False(t, upToDate)
+	assert.Nil(t, err)
+}
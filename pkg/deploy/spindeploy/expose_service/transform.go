@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"net/url"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -43,13 +44,37 @@ func (t *exposeTransformer) TransformManifests(ctx context.Context, gen *generat
 	if err := t.transformServiceManifest(ctx, "deck", gen.Config["deck"].Service); err != nil {
 		return err
 	}
-	return t.transformServiceManifest(ctx, "gate", gen.Config["gate"].Service)
+	if err := t.transformServiceManifest(ctx, "gate", gen.Config["gate"].Service); err != nil {
+		return err
+	}
+	for _, n := range getExtraExposedServices(t.svc.GetExposeConfig()) {
+		if err := t.transformServiceManifest(ctx, n, gen.Config[n].Service); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getExtraExposedServices returns the services other than deck and gate that have expose overrides.
+// gate-x509 is handled by the x509 transformer.
+func getExtraExposedServices(exp *interfaces.ExposeConfig) []string {
+	names := make([]string, 0)
+	for n := range exp.Service.Overrides {
+		if n == "deck" || n == "gate" || n == "gate-x509" {
+			continue
+		}
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
 }
 
 func (t *exposeTransformer) TransformConfig(ctx context.Context) error {
 	if !applies(t.svc) {
 		return nil
 	}
+	extra := getExtraExposedServices(t.svc.GetExposeConfig())
+	t.svc.GetStatus().RetainServiceUrls(append([]string{"deck", "gate"}, extra...)...)
 
 	if err := t.setStatusAndOverrideBaseUrl(ctx, util.GateServiceName, util.GateOverrideBaseUrlProp); err != nil {
 		t.log.Info(fmt.Sprintf("Error setting gate overrideBaseUrl: %s, ignoring", err))
@@ -59,6 +84,26 @@ func (t *exposeTransformer) TransformConfig(ctx context.Context) error {
 		t.log.Info(fmt.Sprintf("Error setting deck overrideBaseUrl: %s, ignoring", err))
 		return err
 	}
+	for _, n := range extra {
+		if err := t.setStatusUrl(ctx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setStatusUrl records the load balancer url of a service other than deck and gate, if any
+func (t *exposeTransformer) setStatusUrl(ctx context.Context, serviceName string) error {
+	lbUrl, err := util.FindLoadBalancerUrl(fmt.Sprintf("spin-%s", serviceName), t.svc.GetNamespace(), t.client, false)
+	if err != nil || lbUrl == "" {
+		return err
+	}
+	parsedLbUrl, err := url.Parse(lbUrl)
+	if err != nil {
+		return err
+	}
+	desiredPort := util.GetDesiredExposePort(ctx, serviceName, util.GetPort(lbUrl, int32(80)), t.svc)
+	t.svc.GetStatus().SetServiceUrl(serviceName, util.BuildUrl(parsedLbUrl.Scheme, parsedLbUrl.Hostname(), desiredPort))
 	return nil
 }
 
@@ -73,6 +118,9 @@ func (t *exposeTransformer) setStatusAndOverrideBaseUrl(ctx context.Context, ser
 	} else if serviceName == util.DeckServiceName {
 		st.UIUrl = statusUrl
 	}
+	if statusUrl != "" {
+		st.SetServiceUrl(serviceName[len("spin-"):], statusUrl)
+	}
 	if !isFromOverrideBaseUrl {
 		t.log.Info(fmt.Sprintf("Setting %s overrideBaseUrl to: %s", serviceName, statusUrl))
 		if err = t.svc.GetSpinnakerConfig().SetHalConfigProp(overrideUrlName, statusUrl); err != nil {
@@ -128,7 +176,12 @@ func (t *exposeTransformer) transformServiceManifest(ctx context.Context, svcNam
 	if svc == nil {
 		return nil
 	}
-	defaultPort := util.GetDesiredExposePort(ctx, svcName, int32(80), t.svc)
+	defaultPort := int32(80)
+	if svcName != "deck" && svcName != "gate" && len(svc.Spec.Ports) > 0 {
+		// Services other than deck and gate keep their port unless overridden
+		defaultPort = svc.Spec.Ports[0].Port
+	}
+	defaultPort = util.GetDesiredExposePort(ctx, svcName, defaultPort, t.svc)
 	if err := t.applyPortChanges(ctx, fmt.Sprintf("%s-tcp", svcName), defaultPort, svc); err != nil {
 		return err
 	}
@@ -312,3 +312,71 @@ func TestTransformHalconfig_ExposedPortOverridedFromConfig(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, int32(8089), gen.Config["gate"].Service.Spec.Ports[0].Port)
 }
+
+func TestTransformManifests_ExposedExtraServiceFromOverrides(t *testing.T) {
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+spec:
+  spinnakerConfig:
+    config: {}
+  expose:
+    type: service
+    service:
+      type: LoadBalancer
+      overrides:
+        echo:
+          type: NodePort
+          annotations:
+            "service.beta.kubernetes.io/aws-load-balancer-internal": "true"
+`
+	tr, _ := transformertest.SetupTransformerFromSpinText(&TransformerGenerator{}, s, t)
+	gen := &generated.SpinnakerGeneratedConfig{}
+	test.AddServiceToGenConfig(gen, "echo", "testdata/input_service_echo.yml", t)
+
+	err := tr.TransformManifests(context.TODO(), gen)
+	assert.Nil(t, err)
+
+	svc := gen.Config["echo"].Service
+	assert.Equal(t, corev1.ServiceType("NodePort"), svc.Spec.Type)
+	assert.Equal(t, int32(8089), svc.Spec.Ports[0].Port)
+	assert.Equal(t, "echo-tcp", svc.Spec.Ports[0].Name)
+	assert.Equal(t, map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"}, svc.Annotations)
+}
+
+func TestTransformHalconfig_ExposedExtraServiceUrlInStatus(t *testing.T) {
+	echoSvc := &corev1.Service{}
+	test.ReadYamlFile("testdata/input_service_echo.yml", echoSvc, t)
+	echoSvc.Spec.Type = corev1.ServiceTypeLoadBalancer
+	echoSvc.Spec.Ports[0].Name = "echo-tcp"
+	echoSvc.Spec.Ports[0].Port = 443
+	echoSvc.Status.LoadBalancer.Ingress = append(echoSvc.Status.LoadBalancer.Ingress, corev1.LoadBalancerIngress{Hostname: "echo.acme.com"})
+	s := `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: ns1
+spec:
+  spinnakerConfig:
+    config: {}
+  expose:
+    type: service
+    service:
+      type: LoadBalancer
+      overrides:
+        echo:
+          publicPort: 443
+`
+	tr, spinSvc := transformertest.SetupTransformerFromSpinText(&TransformerGenerator{}, s, t, echoSvc)
+	spinSvc.GetStatus().SetServiceUrl("igor", "https://igor.acme.com")
+
+	err := tr.TransformConfig(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "https://echo.acme.com", spinSvc.GetStatus().ServiceUrls["echo"])
+	// igor is no longer exposed
+	_, ok := spinSvc.GetStatus().ServiceUrls["igor"]
+	assert.False(t, ok)
+}
@@ -13,6 +13,7 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -144,6 +145,9 @@ func (d *Deployer) patch(ctx context.Context, modifiedRaw client.Object) error {
 	case "authentication.k8s.io/v1":
 		i = d.rawClient.AuthenticationV1().RESTClient()
 	default:
+		if u, ok := modifiedRaw.(*unstructured.Unstructured); ok {
+			return d.saveUnstructured(ctx, u)
+		}
 		return fmt.Errorf("Unable to find a REST interface for %s", gvk.String())
 	}
 
@@ -190,6 +194,21 @@ func (d *Deployer) patch(ctx context.Context, modifiedRaw client.Object) error {
 	return nil
 }
 
+// saveUnstructured creates or replaces objects with no typed REST interface, such as Istio resources
+func (d *Deployer) saveUnstructured(ctx context.Context, obj *unstructured.Unstructured) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	err := d.client.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, existing)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return d.client.Create(ctx, obj)
+		}
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return d.client.Update(ctx, obj)
+}
+
 // createDeleteJson creates a json with potential fields to be removed from the original object
 func (d *Deployer) createDeleteJson(modifiedRaw runtime.Object, originalRaw runtime.Object) ([]byte, error) {
 	original, ok := originalRaw.(metav1.Object)
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/armory/spinnaker-operator/pkg/deploy"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/accounts"
 	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/changedetector"
 	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/config"
 	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/expose_ingress"
+	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/expose_istio"
 	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/expose_service"
 	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/transformer"
 	"github.com/armory/spinnaker-operator/pkg/deploy/spindeploy/x509"
@@ -21,8 +23,10 @@ import (
 
 var DetectorGenerators = []changedetector.DetectorGenerator{
 	&config.ChangeDetectorGenerator{},
+	&accounts.ChangeDetectorGenerator{},
 	&expose_service.ChangeDetectorGenerator{},
 	&expose_ingress.ChangeDetectorGenerator{},
+	&expose_istio.ChangeDetectorGenerator{},
 	&x509.ChangeDetectorGenerator{},
 }
 
@@ -32,10 +36,12 @@ var TransformerGenerators = []transformer.Generator{
 	&transformer.TargetTransformerGenerator{},
 	&expose_service.TransformerGenerator{},
 	&expose_ingress.TransformerGenerator{},
+	&expose_istio.TransformerGenerator{},
 	&transformer.ServerPortTransformerGenerator{},
 	&x509.X509TransformerGenerator{},
-	&transformer.AccountsTransformerGenerator{},
+	// Manifests are transformed in reverse order: secret references in account settings are mapped by Secrets
 	&transformer.SecretsTransformerGenerator{},
+	&transformer.AccountsTransformerGenerator{},
 	&transformer.StatsTransformerGenerator{},
 	&transformer.PatchTransformerGenerator{},
 	&transformer.DefaultsTransformerGenerator{},
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/armory/spinnaker-operator/pkg/accounts"
 	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/accounts/configserver"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/armory/spinnaker-operator/pkg/generated"
 	"github.com/armory/spinnaker-operator/pkg/util"
@@ -14,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
 )
 
 // accountsTransformer inserts accounts defined via CRD into Spinnaker's config
@@ -48,7 +50,7 @@ func (a *accountsTransformer) TransformManifests(ctx context.Context, gen *gener
 	// Enable "accounts" Spring profile for each potential service
 	svcs := accounts.GetAllServicesWithAccounts()
 	for k := range gen.Config {
-		if a.isServiceWithAccount(k, svcs) {
+		if isServiceWithAccount(k, svcs) {
 			if err := addSpringProfile(gen.Config[k].Deployment, k, accounts.SpringProfile); err != nil {
 				return err
 			}
@@ -56,7 +58,7 @@ func (a *accountsTransformer) TransformManifests(ctx context.Context, gen *gener
 	}
 
 	// Get CRD accounts if enabled
-	crdAccs, err := accounts.AllValidCRDAccounts(ctx, a.client, a.svc.GetNamespace())
+	crdAccs, err := accounts.AllValidCRDAccounts(ctx, a.client, a.svc)
 	if err != nil {
 		// Ignore no kind match
 		if _, ok := err.(*meta.NoKindMatchError); ok {
@@ -66,10 +68,45 @@ func (a *accountsTransformer) TransformManifests(ctx context.Context, gen *gener
 		return err
 	}
 	a.log.Info(fmt.Sprintf("found %d accounts to deploy", len(crdAccs)))
-	return updateServiceSettings(ctx, crdAccs, gen)
+
+	// Services able to reload accounts read them from the operator if accounts are dynamic
+	url := ""
+	if a.svc.GetAccountConfig().Dynamic {
+		if url, err = configserver.URL(a.svc); err != nil {
+			a.log.Info("dynamic accounts written to config secrets", "reason", err.Error())
+		}
+	}
+	if err = updateServiceSettings(ctx, crdAccs, svcs, url, gen); err != nil || url == "" {
+		return err
+	}
+	return addConfigServerToken(ctx, a.client, a.svc, gen)
+}
+
+// addConfigServerToken deploys the secret holding the token services authenticate with to the config server along
+// with the first service served by it
+func addConfigServerToken(ctx context.Context, c client.Client, spinsvc interfaces.SpinnakerService, gen *generated.SpinnakerGeneratedConfig) error {
+	keys := make([]string, 0, len(gen.Config))
+	for k := range gen.Config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !configserver.IsServed(k) {
+			continue
+		}
+		sec, err := configserver.TokenSecret(ctx, c, spinsvc)
+		if err != nil {
+			return err
+		}
+		cfg := gen.Config[k]
+		cfg.Resources = append(cfg.Resources, sec)
+		gen.Config[k] = cfg
+		return nil
+	}
+	return nil
 }
 
-func (a *accountsTransformer) isServiceWithAccount(serviceKey string, accountServices []string) bool {
+func isServiceWithAccount(serviceKey string, accountServices []string) bool {
 	for _, s := range accountServices {
 		if util.IsServiceLike(serviceKey, s) {
 			return true
@@ -78,10 +115,18 @@ func (a *accountsTransformer) isServiceWithAccount(serviceKey string, accountSer
 	return false
 }
 
-func updateServiceSettings(ctx context.Context, crdAccounts []account.Account, gen *generated.SpinnakerGeneratedConfig) error {
+// updateServiceSettings writes accounts to the config secret of each service declared by an account type.
+// If configServerUrl is set, services served by the config server are configured to read their accounts from it instead.
+func updateServiceSettings(ctx context.Context, crdAccounts []account.Account, accountServices []string, configServerUrl string, gen *generated.SpinnakerGeneratedConfig) error {
 	for k := range gen.Config {
-		settings, err := accounts.PrepareSettings(ctx, k, crdAccounts)
-		if err != nil {
+		if !isServiceWithAccount(k, accountServices) {
+			continue
+		}
+		var settings map[string]interface{}
+		var err error
+		if configServerUrl != "" && configserver.IsServed(k) {
+			settings = configserver.ClientSettings(k, configServerUrl)
+		} else if settings, err = accounts.PrepareSettings(account.WithMountedFiles(ctx), k, crdAccounts); err != nil {
 			return err
 		}
 		config, ok := gen.Config[k]
@@ -175,7 +175,7 @@ spec:
 			},
 		},
 	}
-	assert.Nil(t, updateServiceSettings(context.TODO(), nil, g))
+	assert.Nil(t, updateServiceSettings(context.TODO(), nil, []string{"clouddriver"}, "", g))
 
 	accs := []account.Account{
 		&kubernetes.Account{
@@ -185,7 +185,7 @@ spec:
 			},
 		},
 	}
-	if !assert.Nil(t, updateServiceSettings(context.TODO(), accs, g)) {
+	if !assert.Nil(t, updateServiceSettings(context.TODO(), accs, []string{"clouddriver"}, "", g)) {
 		return
 	}
 	b, ok := dcs1.Data["clouddriver-accounts.yml"]
@@ -204,4 +204,18 @@ spec:
 			assert.Equal(t, "kube.yml", v)
 		}
 	}
+
+	// Accounts read from the config server
+	if !assert.Nil(t, updateServiceSettings(context.TODO(), accs, []string{"clouddriver"}, "http://spinnaker-operator-accounts.ops.svc:8989/accounts/ns1/spinnaker", g)) {
+		return
+	}
+	m = make(map[string]interface{})
+	if assert.Nil(t, yaml.Unmarshal(dcs1.Data["clouddriver-accounts.yml"], &m)) {
+		_, err := inspect.GetObjectProp(m, "kubernetes.accounts")
+		assert.NotNil(t, err)
+		v, err := inspect.GetObjectPropString(context.TODO(), m, "spring.config.import")
+		if assert.Nil(t, err) {
+			assert.Equal(t, "optional:configserver:http://spinnaker-operator-accounts.ops.svc:8989/accounts/ns1/spinnaker", v)
+		}
+	}
 }
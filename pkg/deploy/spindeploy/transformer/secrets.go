@@ -2,8 +2,12 @@ package transformer
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	secups "github.com/armory/go-yaml-tools/pkg/secrets"
+	"github.com/armory/spinnaker-operator/pkg/accounts/kubernetes"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/armory/spinnaker-operator/pkg/generated"
 	"github.com/armory/spinnaker-operator/pkg/inspect"
@@ -13,10 +17,14 @@ import (
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"os"
 	"path"
+	"regexp"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
 	"strings"
 )
 
@@ -30,6 +38,7 @@ const (
 	awsArtifactsSecretKey   = "awsSecretAccessKey"
 	awsCanary               = "canary"
 	monitoringContainerName = "monitoring-daemon"
+	decryptedHashAnnotation = "spinnaker.io/decrypted-secrets-hash"
 )
 
 // secretsTransformer maps Kubernetes secrets onto the deployment of the service that requires it
@@ -39,6 +48,8 @@ type secretsTransformer struct {
 	log        logr.Logger
 	client     client.Client
 	k8sSecrets *k8sSecretHolder
+	// collectors keep track of secret references of each service from config to manifests
+	collectors map[string]*kubernetesSecretCollector
 }
 
 type SecretsTransformerGenerator struct{}
@@ -96,7 +107,8 @@ func (s *secretsTransformer) replaceK8sSecretsFromAwsKeys(spinCfg *interfaces.Sp
 	if !ok {
 		return nil
 	}
-	newCan, err := s.sanitizeK8sSecret(can, ctx)
+	// Kayenta reads canary accounts
+	newCan, err := s.collector(ctx, "kayenta").sanitizeSecrets(can)
 	if err != nil {
 		return err
 	}
@@ -109,7 +121,7 @@ func (s *secretsTransformer) getAndReplace(svc, accessKeyProp, secretKeyProp str
 	if err != nil {
 		return nil, nil
 	}
-	if !isK8sEngine(secretRaw) {
+	if !s.isLocalK8sSecret(secretRaw) {
 		return nil, nil
 	}
 	accessKeyRaw, _, err := spinCfg.GetRawConfigPropString(svc, accessKeyProp)
@@ -152,7 +164,7 @@ func (s *secretsTransformer) getAndReplaceArray(svc, rootProp, accessKeyProp, se
 		if !ok {
 			continue
 		}
-		if !isK8sEngine(secretRaw) {
+		if !s.isLocalK8sSecret(secretRaw) {
 			continue
 		}
 		envVarName, secretName, secretKey, err := getEnvVarNameFromSecretRaw(svc, secretRaw)
@@ -188,30 +200,23 @@ func (s *secretsTransformer) getAndReplaceArray(svc, rootProp, accessKeyProp, se
 	}, nil
 }
 
-func (s *secretsTransformer) sanitizeK8sSecret(object interface{}, ctx context.Context) (interface{}, error) {
-	h := func(val string) (string, error) {
-		if !secups.IsEncryptedSecret(val) {
-			return val, nil
-		}
-		e, _, _ := secups.GetEngine(val)
-		if e != "k8s" {
-			return val, nil
-		}
-		s, f, err := secrets.Decode(ctx, val)
-		if err != nil {
-			return "", err
-		}
-		if f {
-			return "", fmt.Errorf("\"encryptedFile...\" specified for a non file property (%s), should be \"encrypted...\" instead", val)
-		}
-		return s, nil
+// collector returns the secret collector of the service
+func (s *secretsTransformer) collector(ctx context.Context, svc string) *kubernetesSecretCollector {
+	if s.collectors == nil {
+		s.collectors = make(map[string]*kubernetesSecretCollector)
 	}
-	return inspect.InspectStrings(object, h)
+	k, ok := s.collectors[svc]
+	if !ok {
+		k = &kubernetesSecretCollector{svc: svc, namespace: s.svc.GetNamespace()}
+		s.collectors[svc] = k
+	}
+	k.ctx = ctx
+	return k
 }
 
 func (s *secretsTransformer) TransformManifests(ctx context.Context, gen *generated.SpinnakerGeneratedConfig) error {
 	for svc, cfg := range gen.Config {
-		kCollector := &kubernetesSecretCollector{svc: svc, namespace: s.svc.GetNamespace()}
+		kCollector := s.collector(ctx, svc)
 		for k := range cfg.Resources {
 			sec, ok := cfg.Resources[k].(*unstructured.Unstructured)
 			if ok && sec.Object["kind"] == "Secret" {
@@ -228,6 +233,15 @@ func (s *secretsTransformer) TransformManifests(ctx context.Context, gen *genera
 		if err != nil {
 			return err
 		}
+		if err := kCollector.mapEnvVars(cfg.Deployment); err != nil {
+			return err
+		}
+		if len(kCollector.decryptedSecrets) > 0 {
+			if err := kCollector.addDecryptedSecrets(&cfg); err != nil {
+				return err
+			}
+			gen.Config[svc] = cfg
+		}
 		if err := kCollector.setInDeployment(cfg.Deployment); err != nil {
 			return err
 		}
@@ -236,11 +250,16 @@ func (s *secretsTransformer) TransformManifests(ctx context.Context, gen *genera
 }
 
 type kubernetesSecretCollector struct {
+	ctx          context.Context
 	envVars      []v1.EnvVar
 	volumes      []v1.Volume
 	volumeMounts []v1.VolumeMount
 	svc          string
 	namespace    string
+	// decryptedSecrets holds values decrypted by the operator for the service by secret engine
+	decryptedSecrets map[string]*v1.Secret
+	// rotatedKeys holds keys of decrypted files updated in place, without restarting pods
+	rotatedKeys map[string]bool
 }
 
 // mapSecrets goes through all secret data and replace references to passwords and files with env variables
@@ -300,25 +319,71 @@ func (k *kubernetesSecretCollector) addEnvVarIfNotAdded(envVar v1.EnvVar) {
 
 func (k *kubernetesSecretCollector) sanitizeSecrets(obj interface{}) (interface{}, error) {
 	h := func(val string) (string, error) {
-		e, f, p := secups.GetEngine(val)
-		// If not Kubernetes secret, we just pass to the service as is
-		if e != "k8s" {
-			return val, nil
-		}
-		name, key, err := secrets.ParseKubernetesSecretParams(p)
-		if err != nil {
+		name, key, isFile, ok, err := k.secretReference(val)
+		if err != nil || !ok {
 			return val, err
 		}
-
-		if f {
+		if isFile {
 			return k.handleSecretFileReference(name, key)
 		}
-		s := k.handleSecretVarReference(name, key)
-		return s, nil
+		return k.handleSecretVarReference(name, key), nil
 	}
 	return inspect.InspectStrings(obj, h)
 }
 
+// secretReference returns the Kubernetes secret and key holding the value of a secret reference. ok is false if
+// the reference is passed to the service as is.
+func (k *kubernetesSecretCollector) secretReference(val string) (name, key string, isFile, ok bool, err error) {
+	e, f, p := secups.GetEngine(val)
+	dataKey, decrypted, err := decryptedDataKey(e, p, k.namespace)
+	if err != nil {
+		return "", "", false, false, err
+	}
+	if decrypted {
+		name, err = k.copyDecryptedValue(val, e, dataKey, f)
+		return name, dataKey, f, err == nil, err
+	}
+	// If not Kubernetes secret, we just pass to the service as is
+	if e != secrets.KubernetesSecretEngine {
+		return "", "", false, false, nil
+	}
+	name, key, err = secrets.ParseKubernetesSecretParams(p)
+	return name, key, f, err == nil, err
+}
+
+// mapEnvVars replaces secret references in the environment of the service container, set in service-settings,
+// with environment variables sourced from Kubernetes secrets or mounted file paths
+func (k *kubernetesSecretCollector) mapEnvVars(deployment *appsv1.Deployment) error {
+	if deployment == nil {
+		return nil
+	}
+	c := util.GetContainerInDeployment(deployment, k.svc)
+	if c == nil {
+		return nil
+	}
+	for i := range c.Env {
+		env := &c.Env[i]
+		if env.ValueFrom != nil || !secups.IsEncryptedSecret(env.Value) {
+			continue
+		}
+		name, key, isFile, ok, err := k.secretReference(env.Value)
+		if err != nil {
+			return fmt.Errorf("error mapping secret of environment variable %s: %w", env.Name, err)
+		}
+		if !ok {
+			continue
+		}
+		if isFile {
+			if env.Value, err = k.handleSecretFileReference(name, key); err != nil {
+				return err
+			}
+			continue
+		}
+		*env = envVarFromSecretReference(env.Name, name, key)
+	}
+	return nil
+}
+
 func (k *kubernetesSecretCollector) getSecretFilePath() string {
 	return fmt.Sprintf("/opt/%s/secrets", k.svc)
 }
@@ -409,6 +474,168 @@ func (k *kubernetesSecretCollector) handleSecretVarReference(secretName, key str
 	return getEnvVarNameReference(varName)
 }
 
+func (k *kubernetesSecretCollector) getDecryptedSecretName(engine string) string {
+	return fmt.Sprintf("spin-%s-%s", k.svc, engine)
+}
+
+// decryptedDataKey returns the key of the value of a secret reference in the secret of the service if the operator
+// decrypts it. Other references are passed to the service as is.
+func decryptedDataKey(engine, params, namespace string) (string, bool, error) {
+	switch {
+	case engine == secrets.KubernetesSecretEngine || engine == secrets.KubernetesConfigMapEngine:
+		// Pods can only reference secrets of their namespace
+		ns, name, key, err := secrets.ParseKubernetesReferenceParams(params)
+		if engine == secrets.KubernetesSecretEngine && (ns == "" || ns == namespace) {
+			return "", false, err
+		}
+		return secretDataKey(ns, name, key), true, err
+	case engine == secrets.VaultEngine && secrets.IsVaultRegistered():
+		// Vault secrets are decrypted by the operator if it's configured to, otherwise by the service
+		e, p, key, _, err := secrets.ParseVaultSecretParams(params)
+		return secretDataKey(e, p, key), true, err
+	case engine == secrets.SSMEngine:
+		// Spinnaker services can't read SSM parameters
+		r, name, key, err := secrets.ParseAWSSecretParams(params, "p")
+		return secretDataKey(r, name, key), true, err
+	}
+	return "", false, nil
+}
+
+// copyDecryptedValue copies the decrypted value to the secret of the service for the engine and returns the name
+// of the secret
+func (k *kubernetesSecretCollector) copyDecryptedValue(val, engine, dataKey string, isFile bool) (string, error) {
+	v, _, err := secrets.Decode(k.ctx, val)
+	if err != nil {
+		return "", err
+	}
+	data := []byte(v)
+	if isFile {
+		if data, err = os.ReadFile(v); err != nil {
+			return "", err
+		}
+	}
+	if k.decryptedSecrets == nil {
+		k.decryptedSecrets = map[string]*v1.Secret{}
+	}
+	sec, ok := k.decryptedSecrets[engine]
+	if !ok {
+		sec = &v1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Name: k.getDecryptedSecretName(engine), Namespace: k.namespace},
+			Data:       map[string][]byte{},
+		}
+		k.decryptedSecrets[engine] = sec
+	}
+	sec.Data[dataKey] = data
+	if isFile && isRotatedFile(val) {
+		if k.rotatedKeys == nil {
+			k.rotatedKeys = map[string]bool{}
+		}
+		k.rotatedKeys[dataKey] = true
+	}
+	return sec.Name, nil
+}
+
+// isRotatedFile returns true if the file referenced is rotated by the operator and read by the service as it changes
+func isRotatedFile(val string) bool {
+	e, _, p := secups.GetEngine(val)
+	if e != secrets.KubernetesSecretEngine {
+		return false
+	}
+	_, name, key, err := secrets.ParseKubernetesReferenceParams(p)
+	return err == nil && kubernetes.IsProvisionedKubeconfig(name, key)
+}
+
+// UpdateRotatedFiles copies the current content of rotated files (see isRotatedFile) read from another namespace to
+// the secrets of the services of the SpinnakerService, without deploying it. Services read the files as they change.
+func UpdateRotatedFiles(ctx context.Context, c client.Client, spinsvc interfaces.SpinnakerService, refs []string) error {
+	for _, ref := range refs {
+		if !isRotatedFile(ref) {
+			continue
+		}
+		e, _, p := secups.GetEngine(ref)
+		dataKey, decrypted, err := decryptedDataKey(e, p, spinsvc.GetNamespace())
+		if err != nil {
+			return err
+		}
+		// Secrets of the SpinnakerService namespace are mounted as is
+		if !decrypted {
+			continue
+		}
+		ns, name, key, err := secrets.ParseKubernetesReferenceParams(p)
+		if err != nil {
+			return err
+		}
+		src := &v1.Secret{}
+		if err = c.Get(ctx, client.ObjectKey{Namespace: ns, Name: name}, src); err != nil {
+			return err
+		}
+		l := &v1.SecretList{}
+		if err = c.List(ctx, l, client.InNamespace(spinsvc.GetNamespace())); err != nil {
+			return err
+		}
+		for i := range l.Items {
+			sec := &l.Items[i]
+			if _, ok := sec.Data[dataKey]; !ok || !strings.HasPrefix(sec.Name, "spin-") || !strings.HasSuffix(sec.Name, "-"+e) {
+				continue
+			}
+			sec.Data[dataKey] = src.Data[key]
+			if err = c.Update(ctx, sec); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addDecryptedSecrets adds the decrypted secrets to the resources of the service. Pods are restarted when
+// decrypted values change, except for rotated files.
+func (k *kubernetesSecretCollector) addDecryptedSecrets(cfg *generated.ServiceConfig) error {
+	engines := make([]string, 0, len(k.decryptedSecrets))
+	for e := range k.decryptedSecrets {
+		engines = append(engines, e)
+	}
+	sort.Strings(engines)
+	data := make(map[string]map[string][]byte)
+	for _, e := range engines {
+		cfg.Resources = append(cfg.Resources, k.decryptedSecrets[e])
+		data[e] = map[string][]byte{}
+		for key, v := range k.decryptedSecrets[e].Data {
+			if !k.rotatedKeys[key] {
+				data[e][key] = v
+			}
+		}
+	}
+	if cfg.Deployment == nil {
+		return nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	h := md5.Sum(b)
+	tmpl := &cfg.Deployment.Spec.Template
+	if tmpl.Annotations == nil {
+		tmpl.Annotations = map[string]string{}
+	}
+	tmpl.Annotations[decryptedHashAnnotation] = hex.EncodeToString(h[:])
+	return nil
+}
+
+var invalidSecretKeyChars = regexp.MustCompile(`[^-._a-zA-Z0-9]`)
+
+// secretDataKey returns the key of a decrypted value in the secret of the service by joining the non empty parts
+// of its reference (e.g. secret.spinnaker.github.token)
+func secretDataKey(parts ...string) string {
+	var segs []string
+	for _, p := range parts {
+		if p = strings.Trim(p, "/"); p != "" {
+			segs = append(segs, strings.ReplaceAll(p, "/", "."))
+		}
+	}
+	return invalidSecretKeyChars.ReplaceAllString(strings.Join(segs, "."), "-")
+}
+
 func (k *kubernetesSecretCollector) setInDeployment(deployment *appsv1.Deployment) error {
 	if len(k.envVars) == 0 && len(k.volumes) == 0 {
 		return nil
@@ -487,7 +714,12 @@ func envVarFromRawString(varName, varValue string) v1.EnvVar {
 	return v1.EnvVar{Name: varName, Value: varValue}
 }
 
-func isK8sEngine(s string) bool {
-	e, _, _ := secups.GetEngine(s)
-	return e == "k8s"
+// isLocalK8sSecret returns true if val references a secret in the namespace of the SpinnakerService
+func (s *secretsTransformer) isLocalK8sSecret(val string) bool {
+	e, _, p := secups.GetEngine(val)
+	if e != secrets.KubernetesSecretEngine {
+		return false
+	}
+	ns, _, _, _ := secrets.ParseKubernetesReferenceParams(p)
+	return ns == "" || ns == s.svc.GetNamespace()
 }
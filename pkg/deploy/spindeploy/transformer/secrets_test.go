@@ -5,6 +5,8 @@ import (
 	"fmt"
 	secups "github.com/armory/go-yaml-tools/pkg/secrets"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/v1alpha2"
+	"github.com/armory/spinnaker-operator/pkg/generated"
 	"github.com/armory/spinnaker-operator/pkg/secrets"
 	"github.com/armory/spinnaker-operator/pkg/test"
 	"github.com/armory/spinnaker-operator/pkg/util"
@@ -12,6 +14,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"strings"
 	"testing"
 )
 
@@ -261,7 +267,7 @@ config:
 `
 	spinCfg := &interfaces.SpinnakerConfig{}
 	assert.Nil(t, yaml.Unmarshal([]byte(cfg), spinCfg))
-	tr := &secretsTransformer{k8sSecrets: &k8sSecretHolder{awsCredsByService: map[string]*awsCredentials{}}}
+	tr := &secretsTransformer{svc: th.TypesFactory.NewService(), k8sSecrets: &k8sSecretHolder{awsCredsByService: map[string]*awsCredentials{}}}
 	secups.Engines["k8s"] = func(ctx context.Context, isFile bool, params string) (secups.Decrypter, error) {
 		_, k, err := secrets.ParseKubernetesSecretParams(params)
 		if err != nil {
@@ -281,6 +287,9 @@ config:
 	assert.Equal(t, "acc1Secret", tr.k8sSecrets.awsCredsByService["clouddriver"].svcSecretKeys[0].ValueFrom.SecretKeyRef.Key)
 	assert.Equal(t, "testsecret", tr.k8sSecrets.awsCredsByService["clouddriver"].svcSecretKeys[1].ValueFrom.SecretKeyRef.Name)
 	assert.Equal(t, "acc2Secret", tr.k8sSecrets.awsCredsByService["clouddriver"].svcSecretKeys[1].ValueFrom.SecretKeyRef.Key)
+	if assert.Equal(t, 1, len(tr.collectors["kayenta"].envVars)) {
+		assert.Equal(t, "canSecret", tr.collectors["kayenta"].envVars[0].ValueFrom.SecretKeyRef.Key)
+	}
 	actual, err := yaml.Marshal(spinCfg)
 	assert.Nil(t, err)
 	expected := `config:
@@ -297,7 +306,7 @@ config:
     serviceIntegrations:
     - accounts:
       - name: can-1
-        secretAccessKey: canSecret
+        secretAccessKey: ${KAYENTA_TESTSECRET_CANSECRET}
       name: aws
   persistentStorage:
     persistentStoreType: s3
@@ -388,3 +397,204 @@ profiles:
 `
 	assert.Equal(t, expected, string(actual))
 }
+
+func TestDecryptedSecretCollector(t *testing.T) {
+	engine := func(ctx context.Context, isFile bool, params string) (secups.Decrypter, error) {
+		k := params[strings.LastIndex(params, ":")+1:]
+		if !isFile {
+			return &test.DummyK8sSecretEngine{Secret: k}, nil
+		}
+		f, err := secups.ToTempFile([]byte(k))
+		return &test.DummyK8sSecretEngine{Secret: f, File: true}, err
+	}
+	ssm := secups.Engines[secrets.SSMEngine]
+	secups.Engines[secrets.VaultEngine] = engine
+	secups.Engines[secrets.SSMEngine] = engine
+	defer func() {
+		delete(secups.Engines, secrets.VaultEngine)
+		secups.Engines[secrets.SSMEngine] = ssm
+	}()
+	ctx := secrets.NewContext(context.TODO(), nil, "spinnaker")
+	defer secrets.Cleanup(ctx)
+
+	s := &v1.Secret{
+		Data: map[string][]byte{"clouddriver.yml": []byte(`
+github:
+  token: encrypted:vault!e:secret!p:spinnaker/github!k:token
+kubeconfigFile: encryptedFile:vault!e:secret!p:spinnaker/kube!k:config
+slack:
+  token: encrypted:ssm!r:us-west-2!p:/spinnaker/slack!k:slacktoken
+password: encrypted:s3!r:us-west-2!b:bucket!f:file!k:password
+dockerPassword: encrypted:secrets-manager!r:us-west-2!s:spinnaker/docker
+`)},
+	}
+	k := &kubernetesSecretCollector{ctx: ctx, svc: "clouddriver", namespace: "spinnaker"}
+	if !assert.Nil(t, k.mapSecrets(s)) {
+		return
+	}
+	expected := `dockerPassword: encrypted:secrets-manager!r:us-west-2!s:spinnaker/docker
+github:
+  token: ${CLOUDDRIVER_SPIN_CLOUDDRIVER_VAULT_SECRET_SPINNAKER_GITHUB_TOKEN}
+kubeconfigFile: /opt/clouddriver/secrets/spin-clouddriver-vault/secret.spinnaker.kube.config
+password: encrypted:s3!r:us-west-2!b:bucket!f:file!k:password
+slack:
+  token: ${CLOUDDRIVER_SPIN_CLOUDDRIVER_SSM_US_WEST_2_SPINNAKER_SLACK_SLACKTOKEN}
+`
+	assert.Equal(t, expected, string(s.Data["clouddriver.yml"]))
+	if assert.Equal(t, 2, len(k.decryptedSecrets)) {
+		vault := k.decryptedSecrets[secrets.VaultEngine]
+		assert.Equal(t, "spin-clouddriver-vault", vault.Name)
+		assert.Equal(t, "spinnaker", vault.Namespace)
+		assert.Equal(t, map[string][]byte{
+			"secret.spinnaker.github.token": []byte("token"),
+			"secret.spinnaker.kube.config":  []byte("config"),
+		}, vault.Data)
+		assert.Equal(t, map[string][]byte{
+			"us-west-2.spinnaker.slack.slacktoken": []byte("slacktoken"),
+		}, k.decryptedSecrets[secrets.SSMEngine].Data)
+	}
+	assert.Equal(t, 2, len(k.envVars))
+
+	cfg := &generated.ServiceConfig{Deployment: &appsv1.Deployment{}}
+	if assert.Nil(t, k.addDecryptedSecrets(cfg)) {
+		if assert.Equal(t, 2, len(cfg.Resources)) {
+			assert.Equal(t, "spin-clouddriver-ssm", cfg.Resources[0].GetName())
+		}
+		assert.NotEmpty(t, cfg.Deployment.Spec.Template.Annotations[decryptedHashAnnotation])
+	}
+}
+
+func TestCrossNamespaceSecretCollector(t *testing.T) {
+	engine := func(ctx context.Context, isFile bool, params string) (secups.Decrypter, error) {
+		_, _, k, err := secrets.ParseKubernetesReferenceParams(params)
+		if err != nil || !isFile {
+			return &test.DummyK8sSecretEngine{Secret: k}, err
+		}
+		f, err := secups.ToTempFile([]byte(k))
+		return &test.DummyK8sSecretEngine{Secret: f, File: true}, err
+	}
+	k8s, k8scm := secups.Engines[secrets.KubernetesSecretEngine], secups.Engines[secrets.KubernetesConfigMapEngine]
+	secups.Engines[secrets.KubernetesSecretEngine] = engine
+	secups.Engines[secrets.KubernetesConfigMapEngine] = engine
+	defer func() {
+		secups.Engines[secrets.KubernetesSecretEngine] = k8s
+		secups.Engines[secrets.KubernetesConfigMapEngine] = k8scm
+	}()
+	ctx := secrets.NewContext(context.TODO(), nil, "spinnaker")
+	defer secrets.Cleanup(ctx)
+
+	s := &v1.Secret{
+		Data: map[string][]byte{"clouddriver.yml": []byte(`
+local: encrypted:k8s!n:creds!k:token!ns:spinnaker
+shared: encrypted:k8s!n:creds!k:token!ns:infra
+caFile: encryptedFile:k8scm!n:ca!k:ca.crt
+`)},
+	}
+	k := &kubernetesSecretCollector{ctx: ctx, svc: "clouddriver", namespace: "spinnaker"}
+	if !assert.Nil(t, k.mapSecrets(s)) {
+		return
+	}
+	expected := `caFile: /opt/clouddriver/secrets/spin-clouddriver-k8scm/ca.ca.crt
+local: ${CLOUDDRIVER_CREDS_TOKEN}
+shared: ${CLOUDDRIVER_SPIN_CLOUDDRIVER_K8S_INFRA_CREDS_TOKEN}
+`
+	assert.Equal(t, expected, string(s.Data["clouddriver.yml"]))
+	if assert.Equal(t, 2, len(k.decryptedSecrets)) {
+		assert.Equal(t, map[string][]byte{"infra.creds.token": []byte("token")}, k.decryptedSecrets[secrets.KubernetesSecretEngine].Data)
+		assert.Equal(t, map[string][]byte{"ca.ca.crt": []byte("ca.crt")}, k.decryptedSecrets[secrets.KubernetesConfigMapEngine].Data)
+	}
+}
+
+func TestRotatedFileNotHashed(t *testing.T) {
+	engine := func(ctx context.Context, isFile bool, params string) (secups.Decrypter, error) {
+		f, err := secups.ToTempFile([]byte("kubeconfig"))
+		return &test.DummyK8sSecretEngine{Secret: f, File: true}, err
+	}
+	k8s := secups.Engines[secrets.KubernetesSecretEngine]
+	secups.Engines[secrets.KubernetesSecretEngine] = engine
+	defer func() {
+		secups.Engines[secrets.KubernetesSecretEngine] = k8s
+	}()
+	ctx := secrets.NewContext(context.TODO(), nil, "spinnaker")
+	defer secrets.Cleanup(ctx)
+
+	s := &v1.Secret{
+		Data: map[string][]byte{"clouddriver.yml": []byte(`
+kubeconfigFile: encryptedFile:k8s!ns:team!n:spinnaker-account-dev-kubeconfig!k:kubeconfig
+`)},
+	}
+	k := &kubernetesSecretCollector{ctx: ctx, svc: "clouddriver", namespace: "spinnaker"}
+	if !assert.Nil(t, k.mapSecrets(s)) {
+		return
+	}
+	assert.Equal(t, map[string]bool{"team.spinnaker-account-dev-kubeconfig.kubeconfig": true}, k.rotatedKeys)
+
+	hash := func() string {
+		cfg := &generated.ServiceConfig{Deployment: &appsv1.Deployment{}}
+		assert.Nil(t, k.addDecryptedSecrets(cfg))
+		return cfg.Deployment.Spec.Template.Annotations[decryptedHashAnnotation]
+	}
+	h := hash()
+	// A rotated token doesn't restart pods
+	k.decryptedSecrets[secrets.KubernetesSecretEngine].Data["team.spinnaker-account-dev-kubeconfig.kubeconfig"] = []byte("rotated")
+	assert.Equal(t, h, hash())
+}
+
+func TestUpdateRotatedFiles(t *testing.T) {
+	ref := "encryptedFile:k8s!ns:team!n:spinnaker-account-dev-kubeconfig!k:kubeconfig"
+	dataKey := "team.spinnaker-account-dev-kubeconfig.kubeconfig"
+	c := fake.NewClientBuilder().WithObjects(
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "spinnaker-account-dev-kubeconfig", Namespace: "team"},
+			Data:       map[string][]byte{"kubeconfig": []byte("rotated")},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "spin-clouddriver-k8s", Namespace: "spinnaker"},
+			Data:       map[string][]byte{dataKey: []byte("kubeconfig"), "other": []byte("other")},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "spin-echo-k8s", Namespace: "spinnaker"},
+			Data:       map[string][]byte{"other": []byte("other")},
+		},
+	).Build()
+	spinsvc := &v1alpha2.SpinnakerService{ObjectMeta: metav1.ObjectMeta{Name: "spinnaker", Namespace: "spinnaker"}}
+	if !assert.Nil(t, UpdateRotatedFiles(context.TODO(), c, spinsvc, []string{ref})) {
+		return
+	}
+	sec := &v1.Secret{}
+	if assert.Nil(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "spinnaker", Name: "spin-clouddriver-k8s"}, sec)) {
+		assert.Equal(t, map[string][]byte{dataKey: []byte("rotated"), "other": []byte("other")}, sec.Data)
+	}
+	if assert.Nil(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "spinnaker", Name: "spin-echo-k8s"}, sec)) {
+		assert.Equal(t, map[string][]byte{"other": []byte("other")}, sec.Data)
+	}
+}
+
+func TestMapEnvVars(t *testing.T) {
+	dep := &appsv1.Deployment{}
+	dep.Spec.Template.Spec.Containers = []v1.Container{{
+		Name: "clouddriver",
+		Env: []v1.EnvVar{
+			{Name: "PLAIN", Value: "value"},
+			{Name: "TOKEN", Value: "encrypted:k8s!n:creds!k:token"},
+			{Name: "KUBECONFIG", Value: "encryptedFile:k8s!n:creds!k:kubeconfig"},
+			{Name: "FROM", ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+		},
+	}}
+	k := &kubernetesSecretCollector{ctx: context.TODO(), svc: "clouddriver", namespace: "spinnaker"}
+	if !assert.Nil(t, k.mapEnvVars(dep)) {
+		return
+	}
+	assert.Nil(t, k.setInDeployment(dep))
+	env := dep.Spec.Template.Spec.Containers[0].Env
+	assert.Equal(t, "value", env[0].Value)
+	if assert.NotNil(t, env[1].ValueFrom.SecretKeyRef) {
+		assert.Equal(t, "", env[1].Value)
+		assert.Equal(t, "creds", env[1].ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, "token", env[1].ValueFrom.SecretKeyRef.Key)
+	}
+	assert.Equal(t, "/opt/clouddriver/secrets/creds/kubeconfig", env[2].Value)
+	assert.Equal(t, "metadata.name", env[3].ValueFrom.FieldRef.FieldPath)
+	assert.Equal(t, 1, len(dep.Spec.Template.Spec.Volumes))
+	assert.Equal(t, 1, len(dep.Spec.Template.Spec.Containers[0].VolumeMounts))
+}
@@ -16,6 +16,10 @@ var validationsToSkip = []string{
 	"DeploymentConfigurationValidator",
 	"DockerRegistryAccountValidator",
 	"AwsAccountValidator",
+	"GoogleAccountValidator",
+	"AzureAccountValidator",
+	"CloudFoundryAccountValidator",
+	"EcsAccountValidator",
 }
 
 type validationEnableRule struct {
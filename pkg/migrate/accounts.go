@@ -0,0 +1,242 @@
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts"
+	"github.com/armory/spinnaker-operator/pkg/apis"
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/yaml"
+)
+
+// AccountsCommand is the operator subcommand converting accounts of a halconfig to SpinnakerAccount manifests.
+// Manifests are written to stdout, followed by the updated SpinnakerService when reading a SpinnakerService.
+// Accounts that can't be migrated are reported to stderr and left in the halconfig.
+const AccountsCommand = "migrate-accounts"
+
+// newClient returns the client used to check generated secrets don't overwrite existing secrets
+var newClient = func() (client.Client, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(cfg, client.Options{})
+}
+
+type accountsOptions struct {
+	spinnakerService string
+	halDir           string
+	deployment       string
+	namespace        string
+	skipSecretCheck  bool
+	secretExists     accounts.SecretExists
+}
+
+// RunAccounts runs the migrate-accounts command with the given arguments
+func RunAccounts(args []string, stdout, stderr io.Writer) error {
+	opts := accountsOptions{}
+	fs := flag.NewFlagSet(AccountsCommand, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.spinnakerService, "spinnakerservice", "", "SpinnakerService manifest to migrate accounts from")
+	fs.StringVar(&opts.halDir, "halconfig", "", "Halyard directory (e.g. ~/.hal) to migrate accounts from")
+	fs.StringVar(&opts.deployment, "deployment", "", "Halyard deployment to migrate accounts from. Default: current deployment")
+	fs.StringVar(&opts.namespace, "namespace", "", "Namespace of generated objects. Default: namespace of the SpinnakerService")
+	fs.BoolVar(&opts.skipSecretCheck, "skip-secret-check", false, "Don't check the cluster for existing secrets with the names of generated secrets")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (opts.spinnakerService == "") == (opts.halDir == "") {
+		return errors.New("one of --spinnakerservice or --halconfig is required")
+	}
+	if !opts.skipSecretCheck {
+		c, err := newClient()
+		if err != nil {
+			return fmt.Errorf("unable to connect to the cluster to check existing secrets, use --skip-secret-check to skip the check: %w", err)
+		}
+		opts.secretExists = func(name string) (bool, error) {
+			if opts.namespace == "" {
+				return false, errors.New("--namespace is required to check existing secrets")
+			}
+			err := c.Get(context.TODO(), client.ObjectKey{Namespace: opts.namespace, Name: name}, &corev1.Secret{})
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return err == nil, err
+		}
+	}
+
+	var docs []interface{}
+	var res *accounts.MigrationResult
+	var err error
+	if opts.spinnakerService != "" {
+		var spinsvc map[string]interface{}
+		if spinsvc, res, err = migrateSpinnakerService(&opts); err == nil {
+			docs, err = manifests(res, opts.namespace)
+			docs = append(docs, spinsvc)
+		}
+	} else {
+		if res, err = migrateHalconfig(&opts); err == nil {
+			docs, err = manifests(res, opts.namespace)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, d := range docs {
+		b, err := yaml.Marshal(d)
+		if err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintf(stdout, "---\n%s", b); err != nil {
+			return err
+		}
+	}
+	for _, s := range res.Skipped {
+		fmt.Fprintf(stderr, "skipped %s\n", s.Error())
+	}
+	return nil
+}
+
+// migrateSpinnakerService migrates accounts of spec.spinnakerConfig.config. Files of spec.spinnakerConfig.files moved
+// to secrets are removed unless still referenced. If any account is migrated, spec.accounts.enabled is set so the
+// SpinnakerService picks up the migrated accounts.
+func migrateSpinnakerService(opts *accountsOptions) (map[string]interface{}, *accounts.MigrationResult, error) {
+	b, err := os.ReadFile(opts.spinnakerService)
+	if err != nil {
+		return nil, nil, err
+	}
+	spinsvc := make(map[string]interface{})
+	if err = yaml.Unmarshal(b, &spinsvc); err != nil {
+		return nil, nil, err
+	}
+	if opts.namespace == "" {
+		opts.namespace, _ = inspect.GetRawObjectPropString(spinsvc, "metadata.namespace")
+	}
+	cfg := &interfaces.SpinnakerConfig{}
+	if v, err := inspect.GetObjectProp(spinsvc, "spec.spinnakerConfig"); err == nil {
+		if err = inspect.Convert(v.Interface(), cfg); err != nil {
+			return nil, nil, err
+		}
+	}
+	if cfg.Config == nil {
+		return nil, nil, fmt.Errorf("no spec.spinnakerConfig.config found in %s", opts.spinnakerService)
+	}
+
+	moved := make(map[string]bool)
+	res, err := accounts.Migrate(context.TODO(), cfg.Config, func(name string) ([]byte, error) {
+		f, ok := cfg.Files[name]
+		if !ok {
+			return nil, fmt.Errorf("file not found in spec.spinnakerConfig.files")
+		}
+		moved[name] = true
+		return []byte(f), nil
+	}, opts.secretExists)
+	if err != nil {
+		return nil, nil, err
+	}
+	remaining, err := json.Marshal(cfg.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+	for name := range moved {
+		if !strings.Contains(string(remaining), fmt.Sprintf("%q", name)) {
+			delete(cfg.Files, name)
+		}
+	}
+	if err = inspect.SetObjectProp(spinsvc, "spec.spinnakerConfig.config", map[string]interface{}(cfg.Config)); err != nil {
+		return nil, nil, err
+	}
+	if cfg.Files != nil {
+		if err = inspect.SetObjectProp(spinsvc, "spec.spinnakerConfig.files", cfg.Files); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(res.Accounts) > 0 {
+		if err = inspect.SetObjectProp(spinsvc, "spec.accounts.enabled", true); err != nil {
+			return nil, nil, err
+		}
+	}
+	return spinsvc, res, nil
+}
+
+// migrateHalconfig migrates accounts of a deployment of the Halyard config. Files are read from the local filesystem
+// and the Halyard config is left untouched.
+func migrateHalconfig(opts *accountsOptions) (*accounts.MigrationResult, error) {
+	b, err := os.ReadFile(filepath.Join(opts.halDir, "config"))
+	if err != nil {
+		return nil, err
+	}
+	hal := struct {
+		CurrentDeployment        string                `json:"currentDeployment"`
+		DeploymentConfigurations []interfaces.FreeForm `json:"deploymentConfigurations"`
+	}{}
+	if err = yaml.Unmarshal(b, &hal); err != nil {
+		return nil, err
+	}
+	name := opts.deployment
+	if name == "" {
+		name = hal.CurrentDeployment
+	}
+	for _, d := range hal.DeploymentConfigurations {
+		if d["name"] == name {
+			return accounts.Migrate(context.TODO(), d, os.ReadFile, opts.secretExists)
+		}
+	}
+	return nil, fmt.Errorf("deployment %s not found in %s", name, filepath.Join(opts.halDir, "config"))
+}
+
+// manifests returns the manifests of secrets and accounts without their status
+func manifests(res *accounts.MigrationResult, namespace string) ([]interface{}, error) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		return nil, err
+	}
+	if err := corev1.AddToScheme(s); err != nil {
+		return nil, err
+	}
+	objs := make([]client.Object, 0, len(res.Secrets)+len(res.Accounts))
+	for _, sec := range res.Secrets {
+		objs = append(objs, sec)
+	}
+	for _, acc := range res.Accounts {
+		objs = append(objs, acc)
+	}
+
+	docs := make([]interface{}, 0, len(objs))
+	for _, o := range objs {
+		gvk, err := apiutil.GVKForObject(o, s)
+		if err != nil {
+			return nil, err
+		}
+		o.GetObjectKind().SetGroupVersionKind(gvk)
+		if namespace != "" {
+			o.SetNamespace(namespace)
+		}
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+		if err != nil {
+			return nil, err
+		}
+		delete(m, "status")
+		if meta, ok := m["metadata"].(map[string]interface{}); ok {
+			delete(meta, "creationTimestamp")
+		}
+		docs = append(docs, m)
+	}
+	return docs, nil
+}
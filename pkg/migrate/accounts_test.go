@@ -0,0 +1,206 @@
+package migrate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/accounts/kubernetes"
+	"github.com/armory/spinnaker-operator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func init() {
+	kubernetes.TypesFactory = test.TypesFactory
+	withSecrets()
+}
+
+// withSecrets makes the secrets exist in the cluster checked by the command
+func withSecrets(objs ...client.Object) {
+	newClient = func() (client.Client, error) {
+		return fake.NewClientBuilder().WithObjects(objs...).Build(), nil
+	}
+}
+
+const spinnakerService = `
+apiVersion: spinnaker.io/v1alpha2
+kind: SpinnakerService
+metadata:
+  name: spinnaker
+  namespace: spinnaker
+spec:
+  spinnakerConfig:
+    config:
+      version: 2.20.0
+      providers:
+        kubernetes:
+          enabled: true
+          primaryAccount: prod
+          accounts:
+          - name: prod
+            kubeconfigFile: kubeconfig-prod
+            permissions:
+              READ: [dev]
+          - name: Invalid_Name
+            kubeconfigFile: kubeconfig-shared
+          - name: staging
+            kubeconfigFile: kubeconfig-shared
+    files:
+      kubeconfig-prod: prod-content
+      kubeconfig-shared: shared-content
+`
+
+// splitManifests returns the documents written by the command
+func splitManifests(t *testing.T, out string) []map[string]interface{} {
+	docs := make([]map[string]interface{}, 0)
+	for _, d := range strings.Split(out, "---\n") {
+		if strings.TrimSpace(d) == "" {
+			continue
+		}
+		m := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(d), &m); err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, m)
+	}
+	return docs
+}
+
+func TestRunAccountsSpinnakerService(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "spinsvc.yml")
+	if err := os.WriteFile(f, []byte(spinnakerService), 0600); err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if !assert.Nil(t, RunAccounts([]string{"--spinnakerservice", f}, stdout, stderr)) {
+		return
+	}
+	assert.Equal(t, "skipped Kubernetes account Invalid_Name: account name is not a valid SpinnakerAccount name: "+
+		"a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end "+
+		"with an alphanumeric character (e.g. 'example.com', regex used for validation is "+
+		"'[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')\n", stderr.String())
+
+	docs := splitManifests(t, stdout.String())
+	if !assert.Equal(t, 5, len(docs)) {
+		return
+	}
+	kinds := make([]string, 0)
+	for _, d := range docs {
+		kinds = append(kinds, d["kind"].(string))
+		assert.Equal(t, "spinnaker", d["metadata"].(map[string]interface{})["namespace"])
+	}
+	assert.Equal(t, []string{"Secret", "Secret", "SpinnakerAccount", "SpinnakerAccount", "SpinnakerService"}, kinds)
+
+	prod := docs[2]
+	assert.Equal(t, "spinnaker.io/v1alpha2", prod["apiVersion"])
+	assert.Nil(t, prod["status"])
+	spec := prod["spec"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"READ": []interface{}{"dev"}}, spec["permissions"])
+	assert.Equal(t, map[string]interface{}{"name": "prod-kubeconfig", "key": "kubeconfig"}, spec["kubernetes"].(map[string]interface{})["kubeconfigSecret"])
+
+	assert.Equal(t, map[string]interface{}{"enabled": true}, docs[4]["spec"].(map[string]interface{})["accounts"])
+	spinnakerConfig := docs[4]["spec"].(map[string]interface{})["spinnakerConfig"].(map[string]interface{})
+	k8s := spinnakerConfig["config"].(map[string]interface{})["providers"].(map[string]interface{})["kubernetes"].(map[string]interface{})
+	assert.Nil(t, k8s["primaryAccount"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "Invalid_Name", "kubeconfigFile": "kubeconfig-shared"}}, k8s["accounts"])
+	// Files still referenced are kept
+	assert.Equal(t, map[string]interface{}{"kubeconfig-shared": "shared-content"}, spinnakerConfig["files"])
+}
+
+func TestRunAccountsHalconfig(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	hal := `
+currentDeployment: default
+deploymentConfigurations:
+- name: default
+  providers:
+    kubernetes:
+      accounts:
+      - name: prod
+        kubeconfigFile: ` + kubeconfig + `
+- name: other
+  providers:
+    kubernetes:
+      accounts:
+      - name: other
+        serviceAccount: true
+`
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte(hal), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(kubeconfig, []byte("content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if assert.Nil(t, RunAccounts([]string{"--halconfig", dir, "--namespace", "ns"}, stdout, stderr)) {
+		docs := splitManifests(t, stdout.String())
+		if assert.Equal(t, 2, len(docs)) {
+			assert.Equal(t, "Secret", docs[0]["kind"])
+			assert.Equal(t, "Y29udGVudA==", docs[0]["data"].(map[string]interface{})["kubeconfig"])
+			assert.Equal(t, "prod", docs[1]["metadata"].(map[string]interface{})["name"])
+			assert.Equal(t, "ns", docs[1]["metadata"].(map[string]interface{})["namespace"])
+		}
+		assert.Empty(t, stderr.String())
+	}
+
+	stdout.Reset()
+	if assert.Nil(t, RunAccounts([]string{"--halconfig", dir, "--deployment", "other"}, stdout, stderr)) {
+		docs := splitManifests(t, stdout.String())
+		if assert.Equal(t, 1, len(docs)) {
+			assert.Equal(t, "other", docs[0]["metadata"].(map[string]interface{})["name"])
+		}
+	}
+
+	assert.NotNil(t, RunAccounts([]string{"--halconfig", dir, "--deployment", "missing"}, stdout, stderr))
+	assert.NotNil(t, RunAccounts(nil, stdout, stderr))
+}
+
+func TestRunAccountsSkipped(t *testing.T) {
+	dir := t.TempDir()
+	hal := `
+currentDeployment: default
+deploymentConfigurations:
+- name: default
+  providers:
+    kubernetes:
+      accounts:
+      - name: prod
+        kubeconfigContents: content
+      - name: staging
+        serviceAccount: true
+    aws:
+      accounts:
+      - name: aws-prod
+        accountId: "123456789012"
+`
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte(hal), 0600); err != nil {
+		t.Fatal(err)
+	}
+	withSecrets(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "prod-kubeconfig", Namespace: "ns"}})
+	defer withSecrets()
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	if assert.Nil(t, RunAccounts([]string{"--halconfig", dir, "--namespace", "ns"}, stdout, stderr)) {
+		docs := splitManifests(t, stdout.String())
+		if assert.Equal(t, 1, len(docs)) {
+			assert.Equal(t, "staging", docs[0]["metadata"].(map[string]interface{})["name"])
+		}
+		assert.Equal(t, "skipped AWS account aws-prod: migration of AWS accounts is not supported\n"+
+			"skipped Kubernetes account prod: secret prod-kubeconfig already exists\n", stderr.String())
+	}
+
+	// Secrets are only checked in the cluster when asked to
+	stdout.Reset()
+	stderr.Reset()
+	if assert.Nil(t, RunAccounts([]string{"--halconfig", dir, "--namespace", "ns", "--skip-secret-check"}, stdout, stderr)) {
+		assert.Equal(t, 3, len(splitManifests(t, stdout.String())))
+	}
+}
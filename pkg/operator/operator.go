@@ -7,12 +7,16 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 
+	"github.com/armory/spinnaker-operator/pkg/accounts/configserver"
+	"github.com/armory/spinnaker-operator/pkg/accounts/kubernetes"
 	"github.com/armory/spinnaker-operator/pkg/controller"
 	"github.com/armory/spinnaker-operator/pkg/controller/accountvalidating"
 	"github.com/armory/spinnaker-operator/pkg/controller/spinnakerservice"
 	"github.com/armory/spinnaker-operator/pkg/controller/spinnakervalidating"
 	"github.com/armory/spinnaker-operator/pkg/controller/webhook"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
 	"github.com/armory/spinnaker-operator/pkg/version"
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	kubemetrics "github.com/operator-framework/operator-sdk/pkg/kube-metrics"
@@ -62,6 +66,23 @@ func Start(apiScheme func(s *kruntime.Scheme) error) {
 	defaultCertsDir := filepath.Join(getHome(), "spinnaker-operator-certs")
 	fs.BoolVar(&disableAdmission, "disable-admission-controller", false, "Set to disable admission controller")
 	fs.StringVar(&webhook.CertsDir, "certs-dir", defaultCertsDir, "Directory where tls.crt, tls.key and ca.crt files are found. Default: $HOME/spinnaker-operator-certs")
+	vault := secrets.VaultConfig{}
+	fs.StringVar(&vault.AuthMethod, "vault-auth-method", "", "Vault auth method (kubernetes, approle or token) of the vault secret engine. Default: vault secrets are decrypted by Spinnaker services")
+	fs.StringVar(&vault.Address, "vault-address", "", "Vault address. Default: $VAULT_ADDR")
+	fs.StringVar(&vault.AuthPath, "vault-auth-path", "", "Mount path of the Vault auth method. Default: name of the auth method")
+	fs.StringVar(&vault.Role, "vault-role", "", "Vault role of the operator's service account with kubernetes auth")
+	fs.StringVar(&vault.ServiceAccountTokenFile, "vault-service-account-token-file", secrets.DefaultServiceAccountTokenFile, "Service account token sent to Vault with kubernetes auth")
+	fs.StringVar(&vault.RoleID, "vault-approle-role-id", "", "Role id with approle auth")
+	fs.StringVar(&vault.SecretIDFile, "vault-approle-secret-id-file", "", "File containing the secret id with approle auth")
+	awsCfg := secrets.AWSConfig{}
+	fs.StringVar(&awsCfg.SecretsManagerEndpoint, "aws-secrets-manager-endpoint", "", "Endpoint of AWS Secrets Manager. Default: regional endpoint")
+	fs.StringVar(&awsCfg.SSMEndpoint, "aws-ssm-endpoint", "", "Endpoint of SSM Parameter Store. Default: regional endpoint")
+	fs.BoolVar(&configserver.Enabled, "accounts-config-server", false, "Set to serve SpinnakerAccount objects to Clouddriver when spec.accounts.dynamic is true. Default: accounts are written to config secrets")
+	fs.StringVar(&configserver.CertsDir, "accounts-config-server-certs-dir", "", "Directory where tls.crt and tls.key of the accounts config server are found, valid for <operator name>-accounts.<operator namespace>.svc")
+	var secretNamespaces string
+	fs.StringVar(&secretNamespaces, "secret-namespaces", "", "Comma separated namespaces k8s and k8scm secret references can read from with ns, in addition to the SpinnakerService's namespace")
+	var provisionRoles string
+	fs.StringVar(&provisionRoles, "provision-roles", kubernetes.DefaultProvisionRole, "Comma separated ClusterRoles Kubernetes accounts can be provisioned with (kubernetes.provision.role). The operator's role must allow binding them")
 	pflag.CommandLine.AddGoFlagSet(&fs)
 
 	pflag.Parse()
@@ -78,6 +99,19 @@ func Start(apiScheme func(s *kruntime.Scheme) error) {
 
 	printVersion()
 
+	if vault.AuthMethod != "" {
+		if err := secrets.RegisterVault(vault); err != nil {
+			log.Error(err, "unable to configure vault secret engine")
+			os.Exit(1)
+		}
+		log.Info(fmt.Sprintf("Decrypting vault secrets with %s auth", vault.AuthMethod))
+	}
+	secrets.RegisterAWS(awsCfg)
+	if secretNamespaces != "" {
+		secrets.AllowedNamespaces = strings.Split(secretNamespaces, ",")
+	}
+	kubernetes.ProvisionRoles = strings.Split(provisionRoles, ",")
+
 	namespace, _ := k8sutil.GetWatchNamespace()
 	if namespace != "" {
 		log.Info(fmt.Sprintf("Watching Spinnaker configuration in %s", namespace))
@@ -140,6 +174,13 @@ func Start(apiScheme func(s *kruntime.Scheme) error) {
 		}
 	}
 
+	if configserver.Enabled {
+		log.Info("starting accounts config server...")
+		if err := configserver.Add(mgr); err != nil {
+			log.Info("Accounts config server not started, dynamic accounts are written to config secrets", "error", err.Error())
+		}
+	}
+
 	gvks, err := getGVKs(mgr)
 	if err != nil {
 		log.Error(err, "unable to get GroupVersionKind")
@@ -0,0 +1,199 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/armory/go-yaml-tools/pkg/secrets"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+const (
+	SecretsManagerEngine = "secrets-manager"
+	SSMEngine            = "ssm"
+
+	awsLeaseKeyTemplate = "%s:%s/%s"
+)
+
+// AWSConfig configures the AWS secret engines. Credentials are read from the default credential chain, including
+// web identity tokens of IAM roles for service accounts.
+type AWSConfig struct {
+	// SecretsManagerEndpoint overrides the endpoint of AWS Secrets Manager
+	SecretsManagerEndpoint string
+	// SSMEndpoint overrides the endpoint of SSM Parameter Store
+	SSMEndpoint string
+}
+
+// RegisterAWS registers the AWS secret engines:
+// encrypted:secrets-manager!r:<region>!s:<secret name>[!k:<key of a JSON secret>] and
+// encrypted:ssm!r:<region>!p:<parameter name>[!k:<key of a JSON parameter>].
+// encryptedFile references to Secrets Manager write binary secrets as is.
+func RegisterAWS(cfg AWSConfig) {
+	secrets.Engines[SecretsManagerEngine] = func(ctx context.Context, isFile bool, params string) (secrets.Decrypter, error) {
+		return newAWSDecrypter(ctx, cfg.SecretsManagerEndpoint, SecretsManagerEngine, "s", isFile, params, fetchSecretsManagerSecret)
+	}
+	secrets.Engines[SSMEngine] = func(ctx context.Context, isFile bool, params string) (secrets.Decrypter, error) {
+		return newAWSDecrypter(ctx, cfg.SSMEndpoint, SSMEngine, "p", isFile, params, fetchSSMParameter)
+	}
+}
+
+// awsFetcher returns the value of a secret as a string or binary
+type awsFetcher func(sess *session.Session, endpoint, name string) (*string, []byte, error)
+
+type AWSDecrypter struct {
+	engine   string
+	endpoint string
+	region   string
+	name     string
+	key      string
+	isFile   bool
+	fetch    awsFetcher
+	ctx      context.Context
+}
+
+func newAWSDecrypter(ctx context.Context, endpoint, engine, nameParam string, isFile bool, params string, fetch awsFetcher) (secrets.Decrypter, error) {
+	region, name, key, err := ParseAWSSecretParams(params, nameParam)
+	if err != nil {
+		return nil, err
+	}
+	return &AWSDecrypter{engine: engine, endpoint: endpoint, region: region, name: name, key: key, isFile: isFile, fetch: fetch, ctx: ctx}, nil
+}
+
+func (a *AWSDecrypter) Decrypt() (string, error) {
+	c, err := FromContextWithError(a.ctx)
+	if err != nil {
+		return "", err
+	}
+	data, err := a.read(c)
+	if err != nil {
+		return "", err
+	}
+	var b []byte
+	switch {
+	case a.key != "":
+		s, _ := data["string"].(string)
+		v, err := jsonKey(s, a.key)
+		if err != nil {
+			return "", fmt.Errorf("Error reading key %s of %s secret %s:\n  %w", a.key, a.engine, a.name, err)
+		}
+		b = []byte(v)
+	case data["binary"] != nil:
+		if !a.isFile {
+			return "", fmt.Errorf("%s secret %s is binary and can only be referenced with \"encryptedFile\"", a.engine, a.name)
+		}
+		b = data["binary"].([]byte)
+	default:
+		s, _ := data["string"].(string)
+		b = []byte(s)
+	}
+	if a.isFile {
+		return secrets.ToTempFile(b)
+	}
+	return string(b), nil
+}
+
+func (a *AWSDecrypter) IsFile() bool {
+	return a.isFile
+}
+
+// read returns the secret, read once per secret context
+func (a *AWSDecrypter) read(c *SecretContext) (map[string]interface{}, error) {
+	leaseKey := fmt.Sprintf(awsLeaseKeyTemplate, a.engine, a.region, a.name)
+	if l, ok := c.GetLease(leaseKey); ok {
+		return l.Data, nil
+	}
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(a.region)})
+	if err != nil {
+		return nil, fmt.Errorf("Error creating AWS session:\n  %w", err)
+	}
+	s, b, err := a.fetch(sess, a.endpoint, a.name)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading %s secret %s in %s:\n  %w", a.engine, a.name, a.region, err)
+	}
+	data := map[string]interface{}{}
+	if s != nil {
+		data["string"] = *s
+	}
+	if b != nil {
+		data["binary"] = b
+	}
+	return c.SetLease(leaseKey, data, 0).Data, nil
+}
+
+func fetchSecretsManagerSecret(sess *session.Session, endpoint, name string) (*string, []byte, error) {
+	cfg := aws.NewConfig()
+	if endpoint != "" {
+		cfg = cfg.WithEndpoint(endpoint)
+	}
+	out, err := secretsmanager.New(sess, cfg).GetSecretValue(&secretsmanager.GetSecretValueInput{SecretId: aws.String(name)})
+	if err != nil {
+		return nil, nil, err
+	}
+	return out.SecretString, out.SecretBinary, nil
+}
+
+func fetchSSMParameter(sess *session.Session, endpoint, name string) (*string, []byte, error) {
+	cfg := aws.NewConfig()
+	if endpoint != "" {
+		cfg = cfg.WithEndpoint(endpoint)
+	}
+	out, err := ssm.New(sess, cfg).GetParameter(&ssm.GetParameterInput{Name: aws.String(name), WithDecryption: aws.Bool(true)})
+	if err != nil {
+		return nil, nil, err
+	}
+	if out.Parameter == nil {
+		return nil, nil, fmt.Errorf("parameter not found")
+	}
+	return out.Parameter.Value, nil, nil
+}
+
+// ParseAWSSecretParams returns the region, name and optional key of an AWS secret reference. nameParam is the
+// parameter holding the name of the secret.
+func ParseAWSSecretParams(params, nameParam string) (string, string, string, error) {
+	var region, name, key string
+	for _, element := range strings.Split(params, "!") {
+		kv := strings.Split(element, ":")
+		if len(kv) != 2 {
+			return "", "", "", fmt.Errorf("Secret format error - 'r' for region and '%s' for name are required - got '%s'", nameParam, element)
+		}
+		switch kv[0] {
+		case "r":
+			region = kv[1]
+		case nameParam:
+			name = kv[1]
+		case "k":
+			key = kv[1]
+		default:
+			return "", "", "", fmt.Errorf("Secret format error - unknown parameter '%s'", kv[0])
+		}
+	}
+	if region == "" {
+		return "", "", "", fmt.Errorf("Secret format error - 'r' for region is required")
+	}
+	if name == "" {
+		return "", "", "", fmt.Errorf("Secret format error - '%s' for name is required", nameParam)
+	}
+	return region, name, key, nil
+}
+
+// jsonKey returns the value of the key of a JSON object
+func jsonKey(s, key string) (string, error) {
+	m := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		return "", fmt.Errorf("value is not a JSON object")
+	}
+	switch v := m[key].(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", fmt.Errorf("key not found")
+	default:
+		b, err := json.Marshal(v)
+		return string(b), err
+	}
+}
@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// awsStub serves GetSecretValue of Secrets Manager and GetParameter of SSM
+type awsStub struct {
+	requests map[string]int
+}
+
+func (a *awsStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	target := r.Header.Get("X-Amz-Target")
+	in := make(map[string]interface{})
+	_ = json.NewDecoder(r.Body).Decode(&in)
+	a.requests[target]++
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	reply := func(code int, body interface{}) {
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(body)
+	}
+	switch {
+	case target == "secretsmanager.GetSecretValue" && in["SecretId"] == "spinnaker":
+		reply(http.StatusOK, map[string]interface{}{"SecretString": `{"password":"sm-password","port":5432}`})
+	case target == "secretsmanager.GetSecretValue" && in["SecretId"] == "keystore":
+		reply(http.StatusOK, map[string]interface{}{"SecretBinary": "Y29udGVudA=="})
+	case target == "AmazonSSM.GetParameter" && in["Name"] == "/spinnaker/token" && in["WithDecryption"] == true:
+		reply(http.StatusOK, map[string]interface{}{"Parameter": map[string]interface{}{"Value": "ssm-token"}})
+	default:
+		reply(http.StatusBadRequest, map[string]interface{}{"__type": "ResourceNotFoundException", "message": "not found"})
+	}
+}
+
+func TestAWSDecrypt(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "access-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret-key")
+	stub := &awsStub{requests: make(map[string]int)}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+	RegisterAWS(AWSConfig{SecretsManagerEndpoint: srv.URL, SSMEndpoint: srv.URL})
+	defer RegisterAWS(AWSConfig{})
+
+	ctx := NewContext(context.TODO(), nil, "ns")
+	defer Cleanup(ctx)
+
+	v, _, err := Decode(ctx, "encrypted:secrets-manager!r:us-west-2!s:spinnaker!k:password")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "sm-password", v)
+	}
+	v, _, err = Decode(ctx, "encrypted:secrets-manager!r:us-west-2!s:spinnaker!k:port")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "5432", v)
+	}
+	v, err = DecodeAsFile(ctx, "encryptedFile:secrets-manager!r:us-west-2!s:keystore")
+	if assert.Nil(t, err) {
+		b, err := os.ReadFile(v)
+		assert.Nil(t, err)
+		assert.Equal(t, "content", string(b))
+	}
+	_, _, err = Decode(ctx, "encrypted:secrets-manager!r:us-west-2!s:keystore")
+	assert.NotNil(t, err)
+	_, _, err = Decode(ctx, "encrypted:secrets-manager!r:us-west-2!s:missing")
+	assert.NotNil(t, err)
+
+	v, _, err = Decode(ctx, "encrypted:ssm!r:us-west-2!p:/spinnaker/token")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "ssm-token", v)
+	}
+
+	// Secrets are read once per context: spinnaker, keystore and missing
+	assert.Equal(t, 3, stub.requests["secretsmanager.GetSecretValue"])
+	assert.Equal(t, 1, stub.requests["AmazonSSM.GetParameter"])
+}
+
+func TestParseAWSSecretParams(t *testing.T) {
+	r, n, k, err := ParseAWSSecretParams("r:us-west-2!s:spinnaker!k:password", "s")
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"us-west-2", "spinnaker", "password"}, []string{r, n, k})
+	}
+	_, _, _, err = ParseAWSSecretParams("r:us-west-2", "p")
+	assert.NotNil(t, err)
+	_, _, _, err = ParseAWSSecretParams("s:spinnaker", "s")
+	assert.NotNil(t, err)
+	_, _, _, err = ParseAWSSecretParams("r:us-west-2!p:name!x:y", "p")
+	assert.NotNil(t, err)
+}
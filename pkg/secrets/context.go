@@ -5,6 +5,7 @@ import (
 	"errors"
 	"k8s.io/client-go/rest"
 	"os"
+	"time"
 )
 
 type SecretContext struct {
@@ -12,6 +13,52 @@ type SecretContext struct {
 	FileCache  map[string]string
 	RestConfig *rest.Config
 	Namespace  string
+	// Leases holds credentials and secrets read by secret engines, shared by all values decoded with the context
+	Leases map[string]*Lease
+	// Namespaces are other namespaces Kubernetes references can read from, e.g. namespaces of SpinnakerAccount
+	// objects whose references were already read from their own namespace
+	Namespaces map[string]bool
+}
+
+// Lease is data read from a secret backend, valid until Expiry if set
+type Lease struct {
+	Data   map[string]interface{}
+	Expiry time.Time
+}
+
+// Valid returns true if the lease hasn't expired
+func (l *Lease) Valid(now time.Time) bool {
+	return l.Expiry.IsZero() || now.Before(l.Expiry)
+}
+
+// GetLease returns the lease cached under key if still valid
+func (s *SecretContext) GetLease(key string) (*Lease, bool) {
+	l, ok := s.Leases[key]
+	if !ok || !l.Valid(time.Now()) {
+		return nil, false
+	}
+	return l, true
+}
+
+// SetLease caches the data under key for the given duration. A zero duration never expires.
+func (s *SecretContext) SetLease(key string, data map[string]interface{}, d time.Duration) *Lease {
+	l := &Lease{Data: data}
+	if d > 0 {
+		l.Expiry = time.Now().Add(d)
+	}
+	if s.Leases == nil {
+		s.Leases = make(map[string]*Lease)
+	}
+	s.Leases[key] = l
+	return l
+}
+
+// AllowNamespace allows Kubernetes references decoded with the context to read from namespace
+func (s *SecretContext) AllowNamespace(namespace string) {
+	if s.Namespaces == nil {
+		s.Namespaces = make(map[string]bool)
+	}
+	s.Namespaces[namespace] = true
 }
 
 var errContextNotInitialized = errors.New("secret context not initialized")
@@ -23,6 +70,7 @@ func NewContext(ctx context.Context, c *rest.Config, namespace string) context.C
 		FileCache:  make(map[string]string),
 		RestConfig: c,
 		Namespace:  namespace,
+		Leases:     make(map[string]*Lease),
 	})
 }
 
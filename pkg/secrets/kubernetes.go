@@ -10,43 +10,101 @@ import (
 	"strings"
 )
 
+const (
+	KubernetesSecretEngine    = "k8s"
+	KubernetesConfigMapEngine = "k8scm"
+)
+
+// AllowedNamespaces are the namespaces, other than the SpinnakerService's, that k8s and k8scm references can
+// read from with the ns parameter. Set from the operator's flags.
+var AllowedNamespaces []string
+
 type KubernetesDecrypter struct {
 	name       string
 	key        string
 	restConfig *rest.Config
 	namespace  string
+	configMap  bool
 	isFile     bool
 	ctx        context.Context
 }
 
+// NewKubernetesSecretDecrypter decrypts encrypted:k8s!n:<secret name>!k:<key>[!ns:<namespace>]
 func NewKubernetesSecretDecrypter(ctx context.Context, isFile bool, params string) (secrets.Decrypter, error) {
+	return newKubernetesDecrypter(ctx, false, isFile, params)
+}
+
+// NewKubernetesConfigMapDecrypter decrypts encrypted:k8scm!n:<config map name>!k:<key>[!ns:<namespace>]
+func NewKubernetesConfigMapDecrypter(ctx context.Context, isFile bool, params string) (secrets.Decrypter, error) {
+	return newKubernetesDecrypter(ctx, true, isFile, params)
+}
+
+func newKubernetesDecrypter(ctx context.Context, configMap, isFile bool, params string) (secrets.Decrypter, error) {
 	c, err := FromContextWithError(ctx)
 	if err != nil {
 		return nil, err
 	}
-	k := &KubernetesDecrypter{restConfig: c.RestConfig, namespace: c.Namespace, isFile: isFile, ctx: ctx}
+	k := &KubernetesDecrypter{restConfig: c.RestConfig, namespace: c.Namespace, configMap: configMap, isFile: isFile, ctx: ctx}
 	if err := k.parse(params); err != nil {
 		return nil, err
 	}
+	if !IsNamespaceAllowed(k.namespace, c.Namespace) && !c.Namespaces[k.namespace] {
+		return nil, fmt.Errorf("Namespace %s is not allowed for secret references, allowed namespaces: %v", k.namespace, AllowedNamespaces)
+	}
 	return k, nil
 }
 
+// IsNamespaceAllowed returns true if references of a SpinnakerService in home can read from namespace
+func IsNamespaceAllowed(namespace, home string) bool {
+	if namespace == home {
+		return true
+	}
+	for _, ns := range AllowedNamespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
 func (k *KubernetesDecrypter) Decrypt() (string, error) {
 	client, err := corev1.NewForConfig(k.restConfig)
 	if err != nil {
 		return "", fmt.Errorf("Error creating kubernetes client:\n  %w", err)
 	}
-	sec, err := client.Secrets(k.namespace).Get(k.ctx, k.name, metav1.GetOptions{})
+	d, err := k.read(client)
 	if err != nil {
-		return "", fmt.Errorf("Error reading secret with name '%s' from kubernetes:\n  %w", k.name, err)
+		return "", err
+	}
+	if k.isFile {
+		return secrets.ToTempFile(d)
 	}
-	if d, ok := sec.Data[k.key]; ok {
-		if k.isFile {
-			return secrets.ToTempFile(d)
+	return string(d), nil
+}
+
+// read returns the value of the key in the secret or config map
+func (k *KubernetesDecrypter) read(client corev1.CoreV1Interface) ([]byte, error) {
+	if !k.configMap {
+		sec, err := client.Secrets(k.namespace).Get(k.ctx, k.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("Error reading secret with name '%s' from kubernetes:\n  %w", k.name, err)
+		}
+		if d, ok := sec.Data[k.key]; ok {
+			return d, nil
 		}
-		return string(d), nil
+		return nil, fmt.Errorf("Cannot find key %s in secret %s", k.key, k.name)
+	}
+	cm, err := client.ConfigMaps(k.namespace).Get(k.ctx, k.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Error reading config map with name '%s' from kubernetes:\n  %w", k.name, err)
+	}
+	if d, ok := cm.Data[k.key]; ok {
+		return []byte(d), nil
+	}
+	if d, ok := cm.BinaryData[k.key]; ok {
+		return d, nil
 	}
-	return "", fmt.Errorf("Cannot find key %s in secret %s", k.key, k.name)
+	return nil, fmt.Errorf("Cannot find key %s in config map %s", k.key, k.name)
 }
 
 func (s *KubernetesDecrypter) IsFile() bool {
@@ -54,19 +112,28 @@ func (s *KubernetesDecrypter) IsFile() bool {
 }
 
 func (k *KubernetesDecrypter) parse(params string) error {
-	name, key, err := ParseKubernetesSecretParams(params)
+	ns, name, key, err := ParseKubernetesReferenceParams(params)
 	k.name = name
 	k.key = key
+	if ns != "" {
+		k.namespace = ns
+	}
 	return err
 }
 
 func ParseKubernetesSecretParams(params string) (string, string, error) {
-	var name, key string
+	_, name, key, err := ParseKubernetesReferenceParams(params)
+	return name, key, err
+}
+
+// ParseKubernetesReferenceParams returns the optional namespace, the name and the key of a k8s or k8scm reference
+func ParseKubernetesReferenceParams(params string) (string, string, string, error) {
+	var ns, name, key string
 	tokens := strings.Split(params, "!")
 	for _, element := range tokens {
 		kv := strings.Split(element, ":")
 		if len(kv) != 2 {
-			return "", "", fmt.Errorf("Secret format error - 'n' for name is required, 'k' for secret key is required - got '%s'", element)
+			return "", "", "", fmt.Errorf("Secret format error - 'n' for name is required, 'k' for secret key is required - got '%s'", element)
 		}
 
 		switch kv[0] {
@@ -74,14 +141,16 @@ func ParseKubernetesSecretParams(params string) (string, string, error) {
 			name = kv[1]
 		case "k":
 			key = kv[1]
+		case "ns":
+			ns = kv[1]
 		}
 	}
 
 	if name == "" {
-		return "", "", fmt.Errorf("Secret format error - 'n' for name is required")
+		return "", "", "", fmt.Errorf("Secret format error - 'n' for name is required")
 	}
 	if key == "" {
-		return "", "", fmt.Errorf("Secret format error - 'k' for secret key is required")
+		return "", "", "", fmt.Errorf("Secret format error - 'k' for secret key is required")
 	}
-	return name, key, nil
+	return ns, name, key, nil
 }
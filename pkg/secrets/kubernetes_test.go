@@ -1,6 +1,14 @@
 package secrets
 
-import "testing"
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
 
 func TestParseKubernetesSecretParams(t *testing.T) {
 	type args struct {
@@ -48,3 +56,67 @@ func TestParseKubernetesSecretParams(t *testing.T) {
 		})
 	}
 }
+
+func TestParseKubernetesReferenceParams(t *testing.T) {
+	ns, n, k, err := ParseKubernetesReferenceParams("n:ca!k:ca.crt!ns:infra")
+	if assert.Nil(t, err) {
+		assert.Equal(t, []string{"infra", "ca", "ca.crt"}, []string{ns, n, k})
+	}
+	ns, _, _, err = ParseKubernetesReferenceParams("n:ca!k:ca.crt")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "", ns)
+	}
+}
+
+func TestKubernetesDecrypterNamespace(t *testing.T) {
+	AllowedNamespaces = []string{"infra"}
+	defer func() { AllowedNamespaces = nil }()
+	ctx := NewContext(context.TODO(), nil, "spinnaker")
+	defer Cleanup(ctx)
+
+	d, err := NewKubernetesSecretDecrypter(ctx, false, "n:creds!k:token!ns:infra")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "infra", d.(*KubernetesDecrypter).namespace)
+	}
+	d, err = NewKubernetesConfigMapDecrypter(ctx, true, "n:ca!k:ca.crt")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "spinnaker", d.(*KubernetesDecrypter).namespace)
+	}
+	_, err = NewKubernetesSecretDecrypter(ctx, false, "n:creds!k:token!ns:kube-system")
+	assert.NotNil(t, err)
+
+	c, _ := FromContext(ctx)
+	c.AllowNamespace("team-a")
+	d, err = NewKubernetesSecretDecrypter(ctx, true, "n:creds!k:token!ns:team-a")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "team-a", d.(*KubernetesDecrypter).namespace)
+	}
+}
+
+func TestKubernetesDecrypterRead(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "infra"}, Data: map[string][]byte{"token": []byte("abc")}},
+		&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "infra"}, Data: map[string]string{"ca.crt": "cert"}, BinaryData: map[string][]byte{"ca.der": []byte("der")}},
+	).CoreV1()
+	k := &KubernetesDecrypter{name: "creds", key: "token", namespace: "infra", ctx: context.TODO()}
+	b, err := k.read(client)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "abc", string(b))
+	}
+	k = &KubernetesDecrypter{name: "ca", key: "ca.crt", namespace: "infra", configMap: true, ctx: context.TODO()}
+	b, err = k.read(client)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "cert", string(b))
+	}
+	k.key = "ca.der"
+	b, err = k.read(client)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "der", string(b))
+	}
+	k.key = "missing"
+	_, err = k.read(client)
+	assert.NotNil(t, err)
+	k.namespace = "spinnaker"
+	_, err = k.read(client)
+	assert.NotNil(t, err)
+}
@@ -8,7 +8,9 @@ import (
 )
 
 func init() {
-	secrets.Engines["k8s"] = NewKubernetesSecretDecrypter
+	secrets.Engines[KubernetesSecretEngine] = NewKubernetesSecretDecrypter
+	secrets.Engines[KubernetesConfigMapEngine] = NewKubernetesConfigMapDecrypter
+	RegisterAWS(AWSConfig{})
 }
 
 // Decode decodes a potential value into a secret
@@ -0,0 +1,289 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/armory/go-yaml-tools/pkg/secrets"
+	"github.com/hashicorp/vault/api"
+)
+
+const (
+	VaultEngine = "vault"
+
+	VaultKubernetesAuth = "kubernetes"
+	VaultAppRoleAuth    = "approle"
+	VaultTokenAuth      = "token"
+
+	DefaultServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	vaultTokenLease       = "vault:token"
+	kvV2Warning           = "Invalid path for a versioned K/V secrets engine"
+	vaultLeaseKeyTemplate = "vault:%s/%s"
+)
+
+// VaultConfig configures how the operator authenticates to Vault. The address and TLS settings default to the
+// standard Vault environment variables (VAULT_ADDR, VAULT_CACERT...).
+type VaultConfig struct {
+	// Address of Vault, overrides VAULT_ADDR
+	Address string
+	// AuthMethod is one of kubernetes, approle or token
+	AuthMethod string
+	// AuthPath is the mount path of the auth method. Defaults to the name of the auth method.
+	AuthPath string
+	// Role logs in with the kubernetes auth method
+	Role string
+	// ServiceAccountTokenFile is the token of the operator's service account, used with the kubernetes auth method
+	ServiceAccountTokenFile string
+	// RoleID and SecretID log in with the approle auth method. SecretIDFile is read at each login instead of SecretID.
+	RoleID       string
+	SecretID     string
+	SecretIDFile string
+	// Token is used with the token auth method. Defaults to VAULT_TOKEN.
+	Token string
+}
+
+// RegisterVault registers the vault secret engine with the given config:
+// encrypted:vault!e:<engine>!p:<path>!k:<key>[!b:true] where b indicates the value is base64 encoded.
+func RegisterVault(cfg VaultConfig) error {
+	if cfg.AuthPath == "" {
+		cfg.AuthPath = cfg.AuthMethod
+	}
+	switch cfg.AuthMethod {
+	case VaultKubernetesAuth:
+		if cfg.Role == "" {
+			return errors.New("vault role required for kubernetes auth")
+		}
+		if cfg.ServiceAccountTokenFile == "" {
+			cfg.ServiceAccountTokenFile = DefaultServiceAccountTokenFile
+		}
+	case VaultAppRoleAuth:
+		if cfg.RoleID == "" || (cfg.SecretID == "" && cfg.SecretIDFile == "") {
+			return errors.New("vault role id and secret id required for approle auth")
+		}
+	case VaultTokenAuth:
+		if cfg.Token == "" {
+			cfg.Token = os.Getenv(api.EnvVaultToken)
+		}
+		if cfg.Token == "" {
+			return fmt.Errorf("vault token required for token auth, set %s", api.EnvVaultToken)
+		}
+	default:
+		return fmt.Errorf("unknown vault auth method %q, expected one of %s, %s or %s", cfg.AuthMethod, VaultKubernetesAuth, VaultAppRoleAuth, VaultTokenAuth)
+	}
+	secrets.Engines[VaultEngine] = func(ctx context.Context, isFile bool, params string) (secrets.Decrypter, error) {
+		return NewVaultDecrypter(ctx, cfg, isFile, params)
+	}
+	return nil
+}
+
+// IsVaultRegistered returns true if the operator can decrypt vault secrets
+func IsVaultRegistered() bool {
+	_, ok := secrets.Engines[VaultEngine]
+	return ok
+}
+
+type VaultDecrypter struct {
+	config        VaultConfig
+	engine        string
+	path          string
+	key           string
+	base64Encoded bool
+	isFile        bool
+	ctx           context.Context
+}
+
+func NewVaultDecrypter(ctx context.Context, cfg VaultConfig, isFile bool, params string) (secrets.Decrypter, error) {
+	v := &VaultDecrypter{config: cfg, isFile: isFile, ctx: ctx}
+	if err := v.parse(params); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (v *VaultDecrypter) Decrypt() (string, error) {
+	c, err := FromContextWithError(v.ctx)
+	if err != nil {
+		return "", err
+	}
+	data, err := v.readSecret(c)
+	if err != nil {
+		return "", err
+	}
+	s, ok := data[v.key].(string)
+	if !ok {
+		return "", fmt.Errorf("Cannot find key %s in vault secret %s of engine %s", v.key, v.path, v.engine)
+	}
+	b := []byte(s)
+	if v.base64Encoded {
+		if b, err = base64.StdEncoding.DecodeString(s); err != nil {
+			return "", fmt.Errorf("Error decoding base64 key %s of vault secret %s:\n  %w", v.key, v.path, err)
+		}
+	}
+	if v.isFile {
+		return secrets.ToTempFile(b)
+	}
+	return string(b), nil
+}
+
+func (v *VaultDecrypter) IsFile() bool {
+	return v.isFile
+}
+
+func (v *VaultDecrypter) parse(params string) error {
+	engine, path, key, b64, err := ParseVaultSecretParams(params)
+	v.engine, v.path, v.key, v.base64Encoded = engine, path, key, b64
+	return err
+}
+
+// ParseVaultSecretParams returns the engine, path and key of a vault reference and whether the value is base64 encoded
+func ParseVaultSecretParams(params string) (string, string, string, bool, error) {
+	var engine, path, key string
+	b64 := false
+	for _, element := range strings.Split(params, "!") {
+		kv := strings.Split(element, ":")
+		if len(kv) != 2 {
+			return "", "", "", false, fmt.Errorf("Secret format error - 'e' for engine, 'p' for path and 'k' for key are required - got '%s'", element)
+		}
+		switch kv[0] {
+		case "e":
+			engine = kv[1]
+		case "p", "n":
+			path = kv[1]
+		case "k":
+			key = kv[1]
+		case "b":
+			b64 = kv[1] == "true"
+		}
+	}
+	if engine == "" {
+		return "", "", "", false, fmt.Errorf("Secret format error - 'e' for engine is required")
+	}
+	if path == "" {
+		return "", "", "", false, fmt.Errorf("Secret format error - 'p' for path is required")
+	}
+	if key == "" {
+		return "", "", "", false, fmt.Errorf("Secret format error - 'k' for key is required")
+	}
+	return engine, path, key, b64, nil
+}
+
+// readSecret returns the data at the path, read once per lease. The token is renewed once if Vault denies access.
+func (v *VaultDecrypter) readSecret(c *SecretContext) (map[string]interface{}, error) {
+	leaseKey := fmt.Sprintf(vaultLeaseKeyTemplate, v.engine, v.path)
+	if l, ok := c.GetLease(leaseKey); ok {
+		return l.Data, nil
+	}
+	client, err := v.client(c)
+	if err != nil {
+		return nil, err
+	}
+	sec, err := v.read(client)
+	if isForbidden(err) {
+		delete(c.Leases, vaultTokenLease)
+		if client, err = v.client(c); err != nil {
+			return nil, err
+		}
+		sec, err = v.read(client)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading vault secret %s of engine %s:\n  %w", v.path, v.engine, err)
+	}
+	if sec == nil || sec.Data == nil {
+		return nil, fmt.Errorf("Cannot find vault secret %s of engine %s", v.path, v.engine)
+	}
+	data := sec.Data
+	// KV v2 nests the secret under data
+	if d, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = d
+		}
+	}
+	return c.SetLease(leaseKey, data, time.Duration(sec.LeaseDuration)*time.Second).Data, nil
+}
+
+// read reads the secret from a KV v1 engine, falling back to the KV v2 path
+func (v *VaultDecrypter) read(client *api.Client) (*api.Secret, error) {
+	sec, err := client.Logical().Read(fmt.Sprintf("%s/%s", v.engine, v.path))
+	if err != nil || sec == nil {
+		return sec, err
+	}
+	for _, w := range sec.Warnings {
+		if strings.Contains(w, kvV2Warning) {
+			return client.Logical().Read(fmt.Sprintf("%s/data/%s", v.engine, v.path))
+		}
+	}
+	return sec, nil
+}
+
+// client returns a Vault client with a token cached in the secret context
+func (v *VaultDecrypter) client(c *SecretContext) (*api.Client, error) {
+	cfg := api.DefaultConfig()
+	if cfg.Error != nil {
+		return nil, fmt.Errorf("Error configuring vault client:\n  %w", cfg.Error)
+	}
+	if v.config.Address != "" {
+		cfg.Address = v.config.Address
+	}
+	client, err := api.NewClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating vault client:\n  %w", err)
+	}
+	if l, ok := c.GetLease(vaultTokenLease); ok {
+		client.SetToken(l.Data["token"].(string))
+		return client, nil
+	}
+	token, ttl, err := v.login(client)
+	if err != nil {
+		return nil, fmt.Errorf("Error logging into vault with %s auth:\n  %w", v.config.AuthMethod, err)
+	}
+	c.SetLease(vaultTokenLease, map[string]interface{}{"token": token}, ttl)
+	client.SetToken(token)
+	return client, nil
+}
+
+// login returns a token for the configured auth method and its time to live
+func (v *VaultDecrypter) login(client *api.Client) (string, time.Duration, error) {
+	var data map[string]interface{}
+	switch v.config.AuthMethod {
+	case VaultTokenAuth:
+		return v.config.Token, 0, nil
+	case VaultKubernetesAuth:
+		jwt, err := os.ReadFile(v.config.ServiceAccountTokenFile)
+		if err != nil {
+			return "", 0, err
+		}
+		data = map[string]interface{}{"role": v.config.Role, "jwt": strings.TrimSpace(string(jwt))}
+	case VaultAppRoleAuth:
+		secretID := v.config.SecretID
+		if v.config.SecretIDFile != "" {
+			b, err := os.ReadFile(v.config.SecretIDFile)
+			if err != nil {
+				return "", 0, err
+			}
+			secretID = strings.TrimSpace(string(b))
+		}
+		data = map[string]interface{}{"role_id": v.config.RoleID, "secret_id": secretID}
+	}
+	// Don't send a token read from the environment with the login request
+	client.ClearToken()
+	sec, err := client.Logical().Write(fmt.Sprintf("auth/%s/login", v.config.AuthPath), data)
+	if err != nil {
+		return "", 0, err
+	}
+	if sec == nil || sec.Auth == nil {
+		return "", 0, errors.New("no token returned")
+	}
+	return sec.Auth.ClientToken, time.Duration(sec.Auth.LeaseDuration) * time.Second, nil
+}
+
+func isForbidden(err error) bool {
+	var re *api.ResponseError
+	return errors.As(err, &re) && re.StatusCode == http.StatusForbidden
+}
@@ -0,0 +1,144 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// vaultStub serves KV v1 engine "kv" and KV v2 engine "secret", and logs in with kubernetes and approle auth
+type vaultStub struct {
+	token    string
+	requests map[string]int
+}
+
+func (v *vaultStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.requests[r.URL.Path]++
+	reply := func(code int, body interface{}) {
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(body)
+	}
+	switch r.URL.Path {
+	case "/v1/auth/kubernetes/login", "/v1/auth/approle/login":
+		data := make(map[string]interface{})
+		_ = json.NewDecoder(r.Body).Decode(&data)
+		if data["jwt"] == "sa-token" || data["secret_id"] == "secret-id" {
+			reply(http.StatusOK, map[string]interface{}{"auth": map[string]interface{}{"client_token": v.token, "lease_duration": 3600}})
+			return
+		}
+		reply(http.StatusBadRequest, map[string]interface{}{"errors": []string{"invalid credentials"}})
+		return
+	}
+	if r.Header.Get("X-Vault-Token") != v.token {
+		reply(http.StatusForbidden, map[string]interface{}{"errors": []string{"permission denied"}})
+		return
+	}
+	switch r.URL.Path {
+	case "/v1/kv/spinnaker":
+		reply(http.StatusOK, map[string]interface{}{"lease_duration": 3600, "data": map[string]interface{}{"password": "v1-password", "cert": "Y29udGVudA=="}})
+	case "/v1/secret/spinnaker":
+		reply(http.StatusNotFound, map[string]interface{}{"warnings": []string{"Invalid path for a versioned K/V secrets engine. See the API docs for the appropriate API endpoints to use. If using the Vault CLI, use 'vault kv get' for this operation."}})
+	case "/v1/secret/data/spinnaker":
+		reply(http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"data": map[string]interface{}{"password": "v2-password"}, "metadata": map[string]interface{}{"version": 1}}})
+	default:
+		reply(http.StatusNotFound, map[string]interface{}{"errors": []string{}})
+	}
+}
+
+func newVaultStub(t *testing.T) (*vaultStub, string) {
+	stub := &vaultStub{token: "client-token", requests: make(map[string]int)}
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+	return stub, srv.URL
+}
+
+func TestVaultDecrypt(t *testing.T) {
+	stub, addr := newVaultStub(t)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("sa-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Nil(t, RegisterVault(VaultConfig{Address: addr, AuthMethod: VaultKubernetesAuth, Role: "operator", ServiceAccountTokenFile: tokenFile})) {
+		return
+	}
+	ctx := NewContext(context.TODO(), nil, "ns")
+	defer Cleanup(ctx)
+
+	v, f, err := Decode(ctx, "encrypted:vault!e:kv!p:spinnaker!k:password")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "v1-password", v)
+		assert.False(t, f)
+	}
+	v, err = DecodeAsFile(ctx, "encryptedFile:vault!e:kv!p:spinnaker!k:cert!b:true")
+	if assert.Nil(t, err) {
+		b, err := os.ReadFile(v)
+		assert.Nil(t, err)
+		assert.Equal(t, "content", string(b))
+	}
+	v, _, err = Decode(ctx, "encrypted:vault!e:secret!p:spinnaker!k:password")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "v2-password", v)
+	}
+	_, _, err = Decode(ctx, "encrypted:vault!e:kv!p:spinnaker!k:missing")
+	assert.NotNil(t, err)
+	_, _, err = Decode(ctx, "encrypted:vault!e:kv!p:missing!k:password")
+	assert.NotNil(t, err)
+
+	// Token and secrets are read once per context
+	assert.Equal(t, 1, stub.requests["/v1/auth/kubernetes/login"])
+	assert.Equal(t, 1, stub.requests["/v1/kv/spinnaker"])
+}
+
+func TestVaultTokenRenewal(t *testing.T) {
+	stub, addr := newVaultStub(t)
+	if !assert.Nil(t, RegisterVault(VaultConfig{Address: addr, AuthMethod: VaultAppRoleAuth, RoleID: "operator", SecretID: "secret-id"})) {
+		return
+	}
+	ctx := NewContext(context.TODO(), nil, "ns")
+	defer Cleanup(ctx)
+	c, _ := FromContext(ctx)
+	c.SetLease(vaultTokenLease, map[string]interface{}{"token": "revoked"}, 0)
+
+	v, _, err := Decode(ctx, "encrypted:vault!e:kv!p:spinnaker!k:password")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "v1-password", v)
+	}
+	assert.Equal(t, 1, stub.requests["/v1/auth/approle/login"])
+	assert.Equal(t, "client-token", c.Leases[vaultTokenLease].Data["token"])
+}
+
+func TestVaultTokenAuth(t *testing.T) {
+	_, addr := newVaultStub(t)
+	if !assert.Nil(t, RegisterVault(VaultConfig{Address: addr, AuthMethod: VaultTokenAuth, Token: "wrong-token"})) {
+		return
+	}
+	ctx := NewContext(context.TODO(), nil, "ns")
+	defer Cleanup(ctx)
+	_, _, err := Decode(ctx, "encrypted:vault!e:kv!p:spinnaker!k:password")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "403")
+	}
+}
+
+func TestRegisterVault(t *testing.T) {
+	assert.NotNil(t, RegisterVault(VaultConfig{AuthMethod: VaultKubernetesAuth}))
+	assert.NotNil(t, RegisterVault(VaultConfig{AuthMethod: VaultAppRoleAuth, RoleID: "operator"}))
+	assert.NotNil(t, RegisterVault(VaultConfig{AuthMethod: "ldap"}))
+}
+
+func TestParseVaultSecretParams(t *testing.T) {
+	e, p, k, b, err := ParseVaultSecretParams("e:secret!p:spinnaker/github!k:token!b:true")
+	if assert.Nil(t, err) {
+		assert.Equal(t, []interface{}{"secret", "spinnaker/github", "token", true}, []interface{}{e, p, k, b})
+	}
+	_, _, _, _, err = ParseVaultSecretParams("e:secret!k:token")
+	assert.NotNil(t, err)
+	_, _, _, _, err = ParseVaultSecretParams("e:secret!p:a:b!k:token")
+	assert.NotNil(t, err)
+}
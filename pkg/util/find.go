@@ -9,14 +9,19 @@ import (
 	"github.com/armory/spinnaker-operator/pkg/generated"
 	"github.com/ghodss/yaml"
 	v12 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
+	"time"
 )
 
+// RootCAConfigMap is published by Kubernetes in every namespace with the cluster's root CA
+const RootCAConfigMap = "kube-root-ca.crt"
+
 var errSecretNotFound = errors.New("secret not found")
 
 func FindSpinnakerService(c client.Client, ns string, builder interfaces.TypesFactory) (interfaces.SpinnakerService, error) {
@@ -136,6 +141,36 @@ func GetServiceAccountData(ctx context.Context, name, ns string, c client.Client
 	return "", "", fmt.Errorf("no secret for service account %s was found on namespace %s", name, ns)
 }
 
+// RequestServiceAccountToken mints a token for the service account with the TokenRequest API.
+// It returns the token and its expiration time.
+func RequestServiceAccountToken(ctx context.Context, c *rest.Config, namespace, name string, expirationSeconds int64) (string, time.Time, error) {
+	cl, err := clientcorev1.NewForConfig(c)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	tr := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expirationSeconds},
+	}
+	res, err := cl.ServiceAccounts(namespace).CreateToken(ctx, name, tr, metav1.CreateOptions{})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("unable to request a token for service account %s in namespace %s: %w", name, namespace, err)
+	}
+	return res.Status.Token, res.Status.ExpirationTimestamp.Time, nil
+}
+
+// GetRootCA returns the cluster's root CA from the kube-root-ca.crt config map of the namespace
+func GetRootCA(ctx context.Context, c client.Client, namespace string) ([]byte, error) {
+	cm := &v1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: RootCAConfigMap}, cm); err != nil {
+		return nil, err
+	}
+	ca, ok := cm.Data[v1.ServiceAccountRootCAKey]
+	if !ok {
+		return nil, fmt.Errorf("no %s in config map %s of namespace %s", v1.ServiceAccountRootCAKey, RootCAConfigMap, namespace)
+	}
+	return []byte(ca), nil
+}
+
 func GetSpinnakerServices(list interfaces.SpinnakerServiceList, ns string, c client.Client) ([]interfaces.SpinnakerService, error) {
 	var opts client.ListOption
 	opts = client.InNamespace(ns)
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	apiAdmissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -12,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"net/url"
+	"os"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strconv"
 	"strings"
@@ -156,6 +158,24 @@ func GetDesiredExposePort(ctx context.Context, svcNameWithoutPrefix string, defa
 	return desiredPort
 }
 
+// GetOperatorNamespaceAndName returns the namespace and name of the running operator.
+// ADMISSION_PROXY_NAMESPACE is used when the namespace can't be determined, e.g. when running out of cluster.
+func GetOperatorNamespaceAndName() (string, string, error) {
+	name, err := k8sutil.GetOperatorName()
+	if err != nil {
+		return "", "", err
+	}
+	ns, err := k8sutil.GetOperatorNamespace()
+	if err != nil {
+		envNs := os.Getenv("ADMISSION_PROXY_NAMESPACE")
+		if envNs == "" {
+			return "", "", fmt.Errorf("unable to determine operator namespace. Error: %s and ADMISSION_PROXY_NAMESPACE env var not set", err.Error())
+		}
+		ns = envNs
+	}
+	return ns, name, nil
+}
+
 func CreateOrUpdateService(svc *corev1.Service, rawClient *kubernetes.Clientset) error {
 	namespacedClient := rawClient.CoreV1().Services(svc.Namespace)
 	_, err := namespacedClient.Get(context.TODO(), svc.Name, v1.GetOptions{})
@@ -9,6 +9,7 @@ import (
 	"github.com/armory/spinnaker-operator/pkg/inspect"
 	"gomodules.xyz/jsonpatch/v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
 	"time"
 )
 
@@ -23,7 +24,12 @@ func GetAccountValidationsFor(spinSvc interfaces.SpinnakerService, options Optio
 		}
 		// Get accounts from that type
 		as, err := getAllAccounts(spinSvc, t, options)
-		if err != nil {
+		// Accounts that can't be parsed are reported without stopping the validation of other accounts
+		if ie, ok := err.(invalidAccountsError); ok {
+			for _, e := range ie {
+				validators = append(validators, &invalidAccountValidator{err: e})
+			}
+		} else if err != nil {
 			return nil, err
 		}
 		status := spinSvc.GetStatus()
@@ -38,14 +44,18 @@ func GetAccountValidationsFor(spinSvc interfaces.SpinnakerService, options Optio
 			hc := status.GetHash(k)
 			// If accounts were never validated or if the validation is too old less than x ago
 			if hc == nil || hc.Hash != h || v.NeedsValidation(hc.LastUpdatedAt) {
-				validators = append(validators, &accountValidator{
+				av := &accountValidator{
 					v:     a.NewValidator(),
 					fatal: v.IsFatal(),
 					name:  a.GetName(),
 					key:   k,
 					hash:  h,
 					t:     now,
-				})
+				}
+				if sv, ok := a.(account.AccountWithSettingsValidator); ok {
+					av.settings = sv.NewSettingsValidator()
+				}
+				validators = append(validators, av)
 			}
 		}
 	}
@@ -55,26 +65,22 @@ func GetAccountValidationsFor(spinSvc interfaces.SpinnakerService, options Optio
 func getAllAccounts(spinSvc interfaces.SpinnakerService, accountType account.SpinnakerAccountType, options Options) ([]account.Account, error) {
 	// Get accounts from profile
 	acc, err := getAccountsFromProfile(options.Ctx, spinSvc, accountType)
-	if err != nil {
-		return nil, err
+	if acc != nil || err != nil {
+		return acc, err
 	}
 	// If not found get accounts from main config
-	if acc == nil {
-		acc, err = getAccountsFromConfig(options.Ctx, spinSvc, accountType)
-		if err != nil {
-			return nil, err
-		}
-	}
-	return acc, err
+	return getAccountsFromConfig(options.Ctx, spinSvc, accountType)
 }
 
 type accountValidator struct {
-	v     account.AccountValidator
-	key   string
-	hash  string
-	t     time.Time
-	name  string
-	fatal bool
+	v account.AccountValidator
+	// settings, if set, is the only validator whose errors are fatal
+	settings account.AccountValidator
+	key      string
+	hash     string
+	t        time.Time
+	name     string
+	fatal    bool
 }
 
 func getAccountsFromProfile(ctx context.Context, spinSvc interfaces.SpinnakerService, accountType account.SpinnakerAccountType) ([]account.Account, error) {
@@ -87,13 +93,21 @@ func getAccountsFromProfile(ctx context.Context, spinSvc interfaces.SpinnakerSer
 		if err != nil {
 			continue
 		}
-		return accounts.FromSpinnakerConfigSlice(ctx, accountType, arr, false)
+		return fromSpinnakerConfigSlice(ctx, accountType, arr)
 	}
 	return nil, nil
 }
 
 func getAccountsFromConfig(ctx context.Context, spinSvc interfaces.SpinnakerService, accountType account.SpinnakerAccountType) ([]account.Account, error) {
 	cfg := spinSvc.GetSpinnakerConfig()
+	if st, ok := accountType.(account.SingletonAccountType); ok && st.IsSingleton() {
+		return getSingletonAccountFromConfig(ctx, cfg, accountType)
+	}
+	if et, ok := accountType.(account.AccountTypeWithEnabledKey); ok {
+		if enabled, err := cfg.GetHalConfigPropBool(et.GetEnabledKey(), false); err != nil || !enabled {
+			return nil, nil
+		}
+	}
 	arr, err := cfg.GetHalConfigObjectArray(context.TODO(), accountType.GetConfigAccountsKey())
 	if err != nil {
 		// Ignore, key or format don't match expectations
@@ -112,13 +126,84 @@ func getAccountsFromConfig(ctx context.Context, spinSvc interfaces.SpinnakerServ
 			return nil, fmt.Errorf("primary account defined on '%s' is not present under '%s'", accountType.GetPrimaryAccountsKey(), accountType.GetConfigAccountsKey())
 		}
 	}
-	return accounts.FromSpinnakerConfigSlice(ctx, accountType, arr, false)
+	return fromSpinnakerConfigSlice(ctx, accountType, arr)
+}
+
+// invalidAccountsError holds the errors of accounts of Spinnaker settings that can't be parsed
+type invalidAccountsError []error
+
+func (e invalidAccountsError) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// invalidAccountValidator reports an account that can't be parsed without failing validation, as Halyard does
+type invalidAccountValidator struct {
+	err error
+}
+
+func (i *invalidAccountValidator) Validate(spinSvc interfaces.SpinnakerService, options Options) ValidationResult {
+	return NewResultFromError(i.err, false)
+}
+
+// fromSpinnakerConfigSlice parses the accounts of Spinnaker settings. Accounts that can't be parsed are left out and
+// returned as an invalidAccountsError.
+func fromSpinnakerConfigSlice(ctx context.Context, accountType account.SpinnakerAccountType, arr []map[string]interface{}) ([]account.Account, error) {
+	as := make([]account.Account, 0, len(arr))
+	var errs invalidAccountsError
+	for _, s := range arr {
+		a, err := accountType.FromSpinnakerConfig(ctx, s)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		as = append(as, a)
+	}
+	if len(errs) > 0 {
+		return as, errs
+	}
+	return as, nil
+}
+
+// getSingletonAccountFromConfig reads the configuration of a singleton account type (e.g. notifications.slack)
+// if it's enabled
+func getSingletonAccountFromConfig(ctx context.Context, cfg *interfaces.SpinnakerConfig, accountType account.SpinnakerAccountType) ([]account.Account, error) {
+	k := accountType.GetConfigAccountsKey()
+	if k == "" {
+		return nil, nil
+	}
+	v, err := inspect.GetObjectProp(cfg.Config, k)
+	if err != nil {
+		// Ignore, key or format don't match expectations
+		return nil, nil
+	}
+	m, ok := v.Interface().(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	if e, _ := inspect.GetObjectPropBool(m, "enabled", false); !e {
+		return nil, nil
+	}
+	a, err := accountType.FromSpinnakerConfig(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	return []account.Account{a}, nil
 }
 
 func (a *accountValidator) Validate(spinSvc interfaces.SpinnakerService, options Options) ValidationResult {
-	err := a.v.Validate(spinSvc, options.Client, options.Ctx, options.Log.WithValues("Accounts.Name", a.name))
+	log := options.Log.WithValues("Accounts.Name", a.name)
+	if a.settings != nil {
+		if err := a.settings.Validate(spinSvc, options.Client, options.Ctx, log); err != nil {
+			return NewResultFromError(fmt.Errorf("Validator for account '%s' detected an error:\n  %w", a.name, err), a.fatal)
+		}
+	}
+	err := a.v.Validate(spinSvc, options.Client, options.Ctx, log)
 	if err != nil {
-		return NewResultFromError(fmt.Errorf("Validator for account '%s' detected an error:\n  %w", a.name, err), a.fatal)
+		return NewResultFromError(fmt.Errorf("Validator for account '%s' detected an error:\n  %w", a.name, err), a.fatal && a.settings == nil)
 	}
 	p := getHashPatch(a.key, a.hash, a.t)
 	return ValidationResult{
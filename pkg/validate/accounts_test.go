@@ -2,11 +2,19 @@ package validate
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/armory/spinnaker-operator/pkg/accounts/account"
+	"github.com/armory/spinnaker-operator/pkg/accounts/aws"
 	"github.com/armory/spinnaker-operator/pkg/accounts/kubernetes"
+	"github.com/armory/spinnaker-operator/pkg/accounts/notifications"
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/armory/spinnaker-operator/pkg/test"
 	"github.com/ghodss/yaml"
+	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	"testing"
 )
 
@@ -37,6 +45,47 @@ spec:
 	}
 }
 
+func TestGetSingletonAccountFromConfig(t *testing.T) {
+	s := `
+kind: SpinnakerService
+spec:
+  spinnakerConfig:
+    config:
+      notifications:
+        slack:
+          enabled: true
+          botName: spinnakerbot
+          token: my-token
+`
+	spinsvc := interfaces.DefaultTypesFactory.NewService()
+	if assert.Nil(t, yaml.Unmarshal([]byte(s), spinsvc)) {
+		acc, err := getAccountsFromConfig(context.TODO(), spinsvc, &notifications.SlackAccountType{})
+		if assert.Nil(t, err) && assert.Equal(t, 1, len(acc)) {
+			assert.Equal(t, "spinnakerbot", acc[0].(*notifications.SlackAccount).Slack.BotName)
+		}
+		// SMTP can't be read from the config
+		acc, err = getAccountsFromConfig(context.TODO(), spinsvc, &notifications.SMTPAccountType{})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(acc))
+	}
+
+	s = `
+kind: SpinnakerService
+spec:
+  spinnakerConfig:
+    config:
+      notifications:
+        slack:
+          enabled: false
+`
+	spinsvc = interfaces.DefaultTypesFactory.NewService()
+	if assert.Nil(t, yaml.Unmarshal([]byte(s), spinsvc)) {
+		acc, err := getAccountsFromConfig(context.TODO(), spinsvc, &notifications.SlackAccountType{})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(acc))
+	}
+}
+
 func TestPrimaryAccount(t *testing.T) {
 	s := `
 kind: SpinnakerService
@@ -75,3 +124,76 @@ spec:
 		}
 	}
 }
+
+func TestAccountValidatorWithSettingsValidator(t *testing.T) {
+	failing := account.ValidatorFunc(func(interfaces.SpinnakerService, client.Client, context.Context, logr.Logger) error {
+		return errors.New("failed")
+	})
+	passing := account.ValidatorFunc(func(interfaces.SpinnakerService, client.Client, context.Context, logr.Logger) error {
+		return nil
+	})
+	opts := Options{Ctx: context.TODO(), Log: log.Log}
+
+	cases := []struct {
+		name     string
+		v        account.AccountValidator
+		settings account.AccountValidator
+		errors   int
+		fatal    bool
+	}{
+		{"no settings validator", failing, nil, 1, true},
+		{"invalid settings", passing, failing, 1, true},
+		{"unreachable service", failing, passing, 1, false},
+		{"valid", passing, passing, 0, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a := &accountValidator{v: c.v, settings: c.settings, name: "test", key: "account-aws-test", fatal: true}
+			r := a.Validate(nil, opts)
+			assert.Equal(t, c.errors, len(r.Errors))
+			assert.Equal(t, c.fatal, r.Fatal)
+		})
+	}
+}
+
+func TestGetAwsAccountsFromConfig(t *testing.T) {
+	s := `
+kind: SpinnakerService
+spec:
+  spinnakerConfig:
+    config:
+      providers:
+        aws:
+          enabled: %v
+          accounts:
+          - name: acc1
+            accountId: 123456789012
+          - name: acc2
+            accountId: "1234"
+          - name: acc3
+            regions: us-west-2
+`
+	spinsvc := interfaces.DefaultTypesFactory.NewService()
+	if assert.Nil(t, yaml.Unmarshal([]byte(fmt.Sprintf(s, false)), spinsvc)) {
+		acc, err := getAccountsFromConfig(context.TODO(), spinsvc, &aws.AccountType{})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(acc))
+	}
+	spinsvc = interfaces.DefaultTypesFactory.NewService()
+	if !assert.Nil(t, yaml.Unmarshal([]byte(fmt.Sprintf(s, true)), spinsvc)) {
+		return
+	}
+	acc, err := getAccountsFromConfig(context.TODO(), spinsvc, &aws.AccountType{})
+	// acc3 can't be read and is reported without failing validation
+	if assert.IsType(t, invalidAccountsError{}, err) {
+		assert.Equal(t, 1, len(err.(invalidAccountsError)))
+		r := (&invalidAccountValidator{err: err.(invalidAccountsError)[0]}).Validate(spinsvc, Options{})
+		assert.False(t, r.Fatal)
+	}
+	if assert.Equal(t, 2, len(acc)) {
+		assert.Equal(t, "123456789012", acc[0].(*aws.Account).AWS.AccountId)
+		// Only lifecycle hooks fail accounts of Spinnaker settings
+		settings := acc[1].(account.AccountWithSettingsValidator).NewSettingsValidator()
+		assert.Nil(t, settings.Validate(spinsvc, nil, context.TODO(), log.Log))
+	}
+}
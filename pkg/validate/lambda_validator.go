@@ -14,6 +14,8 @@ import (
 )
 
 const (
+	awsAccountType                  = "aws"
+	awsAccountsEnabledKey           = "providers.aws.enabled"
 	lambdaAccountType               = "lambda"
 	lambdaClouddriverEnabledKey     = "aws.features.lambda.enabled"
 	AccessKeyId                     = "providers.aws.accessKeyId"
@@ -15,9 +15,6 @@ import (
 // Validators registered here should be stateless
 var ParallelValidators = []SpinnakerValidator{
 	&versionValidator{},
-	&dockerRegistryValidator{},
-	&cloudFoundryValidator{},
-	&awsAccountValidator{},
 	&lambdaValidator{},
 }
 